@@ -14,6 +14,20 @@ import (
 // Categories defines model for Categories.
 type Categories []Triplet
 
+// CategoryMetadata defines model for CategoryMetadata.
+type CategoryMetadata struct {
+	Code *string `json:"code,omitempty"`
+
+	// eg Count
+	MeasurementUnit *string `json:"measurement_unit,omitempty"`
+	Name            *string `json:"name,omitempty"`
+	Slug            *string `json:"slug,omitempty"`
+
+	// eg Person
+	StatUnit *string        `json:"stat_unit,omitempty"`
+	Table    *TableMetadata `json:"table,omitempty"`
+}
+
 // Error defines model for Error.
 type Error struct {
 	// error message
@@ -31,6 +45,22 @@ type Metadata struct {
 // MetadataResponse defines model for MetadataResponse.
 type MetadataResponse []Metadata
 
+// MetadataSearchResult defines model for MetadataSearchResult.
+type MetadataSearchResult struct {
+	Code *string `json:"code,omitempty"`
+	Name *string `json:"name,omitempty"`
+	Slug *string `json:"slug,omitempty"`
+
+	// code of the table, or of the category's table
+	TableCode *string `json:"table_code,omitempty"`
+
+	// table or category
+	Type *string `json:"type,omitempty"`
+}
+
+// MetadataSearchResults defines model for MetadataSearchResults.
+type MetadataSearchResults []MetadataSearchResult
+
 // Table defines model for Table.
 type Table struct {
 	Categories *Categories `json:"categories,omitempty"`
@@ -40,6 +70,18 @@ type Table struct {
 	Total      *Triplet    `json:"total,omitempty"`
 }
 
+// TableMetadata defines model for TableMetadata.
+type TableMetadata struct {
+	Categories *[]CategoryMetadata `json:"categories,omitempty"`
+	Code       *string             `json:"code,omitempty"`
+	Name       *string             `json:"name,omitempty"`
+
+	// population the table counts, eg All usual residents
+	PopStat *string  `json:"pop_stat,omitempty"`
+	Slug    *string  `json:"slug,omitempty"`
+	Topic   *Triplet `json:"topic,omitempty"`
+}
+
 // Tables defines model for Tables.
 type Tables []Table
 
@@ -50,6 +92,115 @@ type Triplet struct {
 	Slug *string `json:"slug,omitempty"`
 }
 
+// GetAggregateParams defines parameters for GetAggregate.
+type GetAggregateParams struct {
+	// [ONS codes](https://en.wikipedia.org/wiki/ONS_coding_system) for the geographies that you
+	// want data for. Can be:
+	// - single values (e.g. E01000001)
+	// - comma-separated array of values (e.g E01000001,E01000002,E01000003)
+	// - ellipsis-separated contiguous range of values (e.g. E01000001...E01000010)
+	//
+	// Multiple rows parameters can be supplied, e.g. rows=E01000001&rows=E01000001...E01000010
+	Rows *[]string `json:"rows,omitempty"`
+
+	// The census data that you want (NB - use metadata endpoint to see list of currently available census data). Can be:
+	// - single values (e.g. QS101EW0001)
+	// - comma-separated array of values (e.g QS101EW0001,QS101EW0002,QS101EW0003)
+	// - ellipsis-separated contiguous range of values (e.g. QS101EW0001...QS101EW0010)
+	//
+	// Multiple cols parameters can be supplied, e.g. cols=QS101EW0001&rows=QS101EW0001...QS101EW0010
+	Cols *[]string `json:"cols,omitempty"`
+
+	// Two long, lat coordinate pairs representing the opposite corners of a bounding box (e.g. bbox=0.1338,51.4635,0.1017,51.4647).
+	// This will select all geographies that lie within this bounding box. Bbox can be used instead of, or in combination with the
+	// rows parameter as a way of selecting geography.
+	Bbox *string `json:"bbox,omitempty"`
+
+	// Geotype filters API results to a specific geography type. Can be single values or comma-separated array.
+	// At the moment these options are supported:
+	// - LAD
+	// - LSOA
+	//
+	// Multiple geotype parameters can be supplied, e.g. geotype=LAD&geotype=LSOA
+	Geotype *[]string `json:"geotype,omitempty"`
+
+	// Radius and location (both are required) will select all geographies with radius of the long,lat pair location,
+	// e.g. location=0.1338,51.4635&radius=1000. Radius and location can be used instead of, or in combination with the rows parameter as a way of selecting geography.
+	Location *string `json:"location,omitempty"`
+
+	// Radius and location (both are required) will select all geographies with radius of the long,lat pair location,
+	// e.g. location=0.1338,51.4635&radius=1000. Radius and location can be used instead of, or in combination with the rows parameter as a way of selecting geography.
+	Radius *int `json:"radius,omitempty"`
+
+	// A sequence of long, lat coordinate pairs representing a closed polygon (NB - 'closed' means the first and last coordinate pair
+	// must be the same), e.g. polygon=0.0844,51.4897,0.1214,51.4910,0.1338,51.4635,0.1017,51.4647,0.0844,51.4897. This will select
+	// all geographies that lie within this polygon. polygon can be used instead of, or in combination with the rows parameter as a
+	// way of selecting geography.
+	Polygon     *string `json:"polygon,omitempty"`
+	Censustable *string `json:"censustable,omitempty"`
+
+	// How geographies on the edge of the selection are counted:
+	// - whole (the default) counts every geography selected as in /query2 in full
+	// - apportion counts every geography that intersects a bbox, location/radius or polygon, weighted
+	//   by the fraction of its area inside the selection. rows cannot be used with apportion.
+	Method *string `json:"method,omitempty"`
+
+	// Data version, as listed by /versions. The default is the latest public version for the year.
+	// Non-public versions need the API token in the Authorization header.
+	Version *string `json:"version,omitempty"`
+}
+
+// GetBreaksParams defines parameters for GetBreaks.
+type GetBreaksParams struct {
+	// The census data category to calculate data breaks for, as for /ckmeans.
+	// Ranges (e.g. QS202EW0003...QS202EW0004) are NOT supported.
+	Cat *[]string `json:"cat,omitempty"`
+
+	// The type of geography to calculate data breaks for, as for /ckmeans.
+	Geotype *[]string `json:"geotype,omitempty"`
+
+	// The number of classes. headtail may return fewer.
+	K *int `json:"k,omitempty"`
+
+	// (OPTIONAL) - census data category to use as denominator, as for /ckmeans.
+	DivideBy *string `json:"divide_by,omitempty"`
+
+	// Classification method:
+	// - ckmeans (the default) - natural breaks, as from /ckmeans
+	// - quantile - the same number of geographies in each class
+	// - equal - classes of equal width between the min and max
+	// - stddev - classes one standard deviation wide, centred on the mean
+	// - headtail - head/tail breaks for heavy-tailed data; repeatedly splits the values above the mean
+	Method *string `json:"method,omitempty"`
+
+	// (OPTIONAL) - [ONS codes](https://en.wikipedia.org/wiki/ONS_coding_system) for the geographies to calculate breaks over,
+	// as for /query2. Can be single values, comma-separated arrays or ellipsis-separated ranges.
+	// If no rows, bbox, location/radius, polygon or within are given, breaks are calculated over all geographies.
+	Rows *[]string `json:"rows,omitempty"`
+
+	// (OPTIONAL) - Two long, lat coordinate pairs representing the opposite corners of a bounding box
+	// (e.g. bbox=0.1338,51.4635,0.1017,51.4647), as for /query2.
+	Bbox *string `json:"bbox,omitempty"`
+
+	// (OPTIONAL) - long,lat centre of a radius selection (e.g. location=0.1338,51.4635&radius=1000), as for /query2.
+	Location *string `json:"location,omitempty"`
+
+	// (OPTIONAL) - radius in metres around location, as for /query2.
+	Radius *int `json:"radius,omitempty"`
+
+	// (OPTIONAL) - closed polygon of long, lat coordinate pairs, as for /query2.
+	Polygon *string `json:"polygon,omitempty"`
+
+	// (OPTIONAL) - Geography codes to calculate breaks within, using the geography hierarchy,
+	// e.g. within=E12000007&geotype=LSOA for LSOA breaks within London. Can be used on its own, or to restrict
+	// rows, bbox, location/radius or polygon selections.
+	Within *[]string `json:"within,omitempty"`
+
+	// Data version, as listed by /versions. The default is the latest public version for the year.
+	// Non-public versions need the API token in the Authorization header.
+	Version *string `json:"version,omitempty"`
+}
+
 // GetCkmeansYearParams defines parameters for GetCkmeansYear.
 type GetCkmeansYearParams struct {
 	// The census data category to calculate data breaks for.
@@ -81,6 +232,33 @@ type GetCkmeansYearParams struct {
 	// breaks, instead of raw data (NB if multiple cat are supplied, each cat will be divided by divide_by). Only
 	// single values for divide_by are supported.
 	DivideBy *string `json:"divide_by,omitempty"`
+
+	// (OPTIONAL) - [ONS codes](https://en.wikipedia.org/wiki/ONS_coding_system) for the geographies to calculate breaks over,
+	// as for /query2. Can be single values, comma-separated arrays or ellipsis-separated ranges.
+	// If no rows, bbox, location/radius, polygon or within are given, breaks are calculated over all geographies.
+	Rows *[]string `json:"rows,omitempty"`
+
+	// (OPTIONAL) - Two long, lat coordinate pairs representing the opposite corners of a bounding box
+	// (e.g. bbox=0.1338,51.4635,0.1017,51.4647), as for /query2.
+	Bbox *string `json:"bbox,omitempty"`
+
+	// (OPTIONAL) - long,lat centre of a radius selection (e.g. location=0.1338,51.4635&radius=1000), as for /query2.
+	Location *string `json:"location,omitempty"`
+
+	// (OPTIONAL) - radius in metres around location, as for /query2.
+	Radius *int `json:"radius,omitempty"`
+
+	// (OPTIONAL) - closed polygon of long, lat coordinate pairs, as for /query2.
+	Polygon *string `json:"polygon,omitempty"`
+
+	// (OPTIONAL) - Geography codes to calculate breaks within, using the geography hierarchy,
+	// e.g. within=E12000007&geotype=LSOA for LSOA breaks within London. Can be used on its own, or to restrict
+	// rows, bbox, location/radius or polygon selections.
+	Within *[]string `json:"within,omitempty"`
+
+	// Data version, as listed by /versions. The default is the latest public version for the year.
+	// Non-public versions need the API token in the Authorization header.
+	Version *string `json:"version,omitempty"`
 }
 
 // GetCkmeansratioYearParams defines parameters for GetCkmeansratioYear.
@@ -98,6 +276,57 @@ type GetCkmeansratioYearParams struct {
 
 	// The number of data breaks to estimate.
 	K *int `json:"k,omitempty"`
+
+	// Data version, as listed by /versions. The default is the latest public version for the year.
+	// Non-public versions need the API token in the Authorization header.
+	Version *string `json:"version,omitempty"`
+}
+
+// GetDistributionParams defines parameters for GetDistribution.
+type GetDistributionParams struct {
+	// The census data category to summarise, as for /ckmeans.
+	// Ranges (e.g. QS202EW0003...QS202EW0004) are NOT supported.
+	Cat *[]string `json:"cat,omitempty"`
+
+	// The type of geography to summarise, as for /ckmeans.
+	Geotype *[]string `json:"geotype,omitempty"`
+
+	// (OPTIONAL) - number of histogram bins of equal width, 1 to 100. The default is 10. Cannot be used with breaks.
+	Bins *int `json:"bins,omitempty"`
+
+	// (OPTIONAL) - ascending upper bounds of the histogram bins, comma-separated (e.g. breaks=0.2,0.4,0.6,0.8,1).
+	// Values above the last break go in an extra bin. Cannot be used with bins.
+	Breaks *[]string `json:"breaks,omitempty"`
+
+	// (OPTIONAL) - census data category to use as denominator, as for /ckmeans.
+	DivideBy *string `json:"divide_by,omitempty"`
+
+	// (OPTIONAL) - [ONS codes](https://en.wikipedia.org/wiki/ONS_coding_system) for the geographies to summarise,
+	// as for /query2. Can be single values, comma-separated arrays or ellipsis-separated ranges.
+	// If no rows, bbox, location/radius, polygon or within are given, all geographies are summarised.
+	Rows *[]string `json:"rows,omitempty"`
+
+	// (OPTIONAL) - Two long, lat coordinate pairs representing the opposite corners of a bounding box
+	// (e.g. bbox=0.1338,51.4635,0.1017,51.4647), as for /query2.
+	Bbox *string `json:"bbox,omitempty"`
+
+	// (OPTIONAL) - long,lat centre of a radius selection (e.g. location=0.1338,51.4635&radius=1000), as for /query2.
+	Location *string `json:"location,omitempty"`
+
+	// (OPTIONAL) - radius in metres around location, as for /query2.
+	Radius *int `json:"radius,omitempty"`
+
+	// (OPTIONAL) - closed polygon of long, lat coordinate pairs, as for /query2.
+	Polygon *string `json:"polygon,omitempty"`
+
+	// (OPTIONAL) - Geography codes to summarise within, using the geography hierarchy,
+	// e.g. within=E12000007&geotype=LSOA for the LSOAs in London. Can be used on its own, or to restrict
+	// rows, bbox, location/radius or polygon selections.
+	Within *[]string `json:"within,omitempty"`
+
+	// Data version, as listed by /versions. The default is the latest public version for the year.
+	// Non-public versions need the API token in the Authorization header.
+	Version *string `json:"version,omitempty"`
 }
 
 // GetGeoParams defines parameters for GetGeo.
@@ -105,14 +334,61 @@ type GetGeoParams struct {
 	// Geography code, eg E09000004
 	Geocode *string `json:"geocode,omitempty"`
 
-	// Geography name, eg Bexley
+	// Geography name in English or Welsh, eg Bexley
 	Geoname *string `json:"geoname,omitempty"`
+
+	// Language of the name in the response: en (English) or cy (Welsh). Names without a Welsh version are in English.
+	// Overrides the Accept-Language header. The default is English.
+	Lang *string `json:"lang,omitempty"`
+}
+
+// GetGeoChildrenParams defines parameters for GetGeoChildren.
+type GetGeoChildrenParams struct {
+	// Geotype of descendants to list, eg LSOA
+	Geotype *string `json:"geotype,omitempty"`
+}
+
+// GetLocateParams defines parameters for GetLocate.
+type GetLocateParams struct {
+	// A long,lat pair, e.g. location=0.1338,51.4635
+	Location string `json:"location"`
 }
 
 // GetMetadataYearParams defines parameters for GetMetadataYear.
 type GetMetadataYearParams struct {
 	// Use filtertotals=true if you want to have 'totals' categories separated from other categories in the response (see Examples).
 	Filtertotals *bool `json:"filtertotals,omitempty"`
+
+	// Language of topic, table and category names: en (English) or cy (Welsh). Names without a Welsh translation are in English.
+	// Overrides the Accept-Language header. The default is English. Slugs are always English.
+	Lang *string `json:"lang,omitempty"`
+}
+
+// GetMetadataCategoryParams defines parameters for GetMetadataCategory.
+type GetMetadataCategoryParams struct {
+	// Language of names: en (English) or cy (Welsh). Names without a Welsh translation are in English.
+	// Overrides the Accept-Language header. The default is English. Slugs are always English.
+	Lang *string `json:"lang,omitempty"`
+}
+
+// GetMetadataSearchParams defines parameters for GetMetadataSearch.
+type GetMetadataSearchParams struct {
+	// Words to search for, eg travel to work
+	Q string `json:"q"`
+
+	// Maximum number of results, 1 to 100. The default is 20.
+	Limit *int `json:"limit,omitempty"`
+
+	// Language of names in the results: en (English) or cy (Welsh). Names without a Welsh translation are in English.
+	// Overrides the Accept-Language header. The default is English.
+	Lang *string `json:"lang,omitempty"`
+}
+
+// GetMetadataTableParams defines parameters for GetMetadataTable.
+type GetMetadataTableParams struct {
+	// Language of names: en (English) or cy (Welsh). Names without a Welsh translation are in English.
+	// Overrides the Accept-Language header. The default is English. Slugs are always English.
+	Lang *string `json:"lang,omitempty"`
 }
 
 // GetQueryYearParams defines parameters for GetQueryYear.
@@ -161,6 +437,16 @@ type GetQueryYearParams struct {
 	// way of selecting geography.
 	Polygon     *string `json:"polygon,omitempty"`
 	Censustable *string `json:"censustable,omitempty"`
+
+	// Output format. Overrides the Accept header. Can be:
+	// - csv (the default)
+	// - json (an array of objects, one per geography, keyed by category code)
+	// - ndjson (one JSON object per line; jsonl is also accepted)
+	Format *string `json:"format,omitempty"`
+
+	// Data version, as listed by /versions. The default is the latest public version for the year.
+	// Non-public versions need the API token in the Authorization header.
+	Version *string `json:"version,omitempty"`
 }
 
 // GetQueryParams defines parameters for GetQuery.
@@ -207,12 +493,91 @@ type GetQueryParams struct {
 	// must be the same), e.g. polygon=0.0844,51.4897,0.1214,51.4910,0.1338,51.4635,0.1017,51.4647,0.0844,51.4897. This will select
 	// all geographies that lie within this polygon. polygon can be used instead of, or in combination with the rows parameter as a
 	// way of selecting geography.
-	Polygon     *string `json:"polygon,omitempty"`
-	Censustable *string `json:"censustable,omitempty"`
+	Polygon *string `json:"polygon,omitempty"`
+
+	// Geography codes to select geographies within, using the geography hierarchy
+	// (LSOA, MSOA, LAD, Region, Country, EW), e.g. within=E09000004&geotype=LSOA for all the LSOAs in Bexley.
+	// Can be single values or comma-separated array. Within can be used on its own, or to restrict rows,
+	// bbox, location/radius or polygon selections.
+	Within      *[]string `json:"within,omitempty"`
+	Censustable *string   `json:"censustable,omitempty"`
+
+	// Output format. Overrides the Accept header. Can be:
+	// - csv (the default)
+	// - json (an array of objects, one per geography, keyed by category code)
+	// - ndjson (one JSON object per line; jsonl is also accepted)
+	// - geojson (a FeatureCollection with one feature per geography; the boundary is the geometry
+	//   and the category values are the properties)
+	Format *string `json:"format,omitempty"`
+
+	// Maximum number of geographies to return. If there are more, the response has a Link header
+	// with rel="next" and an X-Next-Cursor header. The default of 0 means no limit.
+	Limit *int `json:"limit,omitempty"`
+
+	// Opaque cursor from the X-Next-Cursor header of the previous page. Send it with the same
+	// selection parameters to get the next page.
+	Cursor *string `json:"cursor,omitempty"`
+
+	// Simplification tolerance for geojson boundaries, in degrees (e.g. simplify=0.0005).
+	// Larger values give smaller responses with coarser boundaries. The default of 0 means no simplification.
+	Simplify *float64 `json:"simplify,omitempty"`
+
+	// Category code to divide every other category by (e.g. divide_by=QS101EW0001).
+	// Adds a <category>_per_<divide_by> column next to each category.
+	// A zero or missing denominator gives null (JSON) or an empty field (CSV).
+	DivideBy *string `json:"divide_by,omitempty"`
+
+	// Set to "total" to add a <category>_pct column next to each category, holding its value
+	// as a percentage of its table's total category (the one ending in 0001).
+	// A zero or missing total gives null (JSON) or an empty field (CSV).
+	PercentOf *string `json:"percent_of,omitempty"`
+
+	// Data version, as listed by /versions. The default is the latest public version for the year.
+	// Non-public versions need the API token in the Authorization header.
+	Version *string `json:"version,omitempty"`
+}
+
+// GetSearchParams defines parameters for GetSearch.
+type GetSearchParams struct {
+	// Text to search for, eg Bex
+	Q string `json:"q"`
+
+	// Geotypes to search. Can be single values or comma-separated array. The default is Region, LAD and MSOA.
+	Geotype *[]string `json:"geotype,omitempty"`
+
+	// Two long, lat coordinate pairs representing the opposite corners of a bounding box (e.g. bbox=0.1338,51.4635,0.1017,51.4647).
+	// Only geographies overlapping the bounding box are returned.
+	Bbox *string `json:"bbox,omitempty"`
+
+	// Maximum number of results, 1 to 100. The default is 10.
+	Limit *int `json:"limit,omitempty"`
+
+	// Language of name in the results: en (English) or cy (Welsh). Names without a Welsh version are in English.
+	// Overrides the Accept-Language header. The default is English.
+	Lang *string `json:"lang,omitempty"`
+}
+
+// GetTileParams defines parameters for GetTile.
+type GetTileParams struct {
+	// Categories to add to each feature, up to 20. Can be single values (e.g. QS101EW0001) or
+	// comma-separated array of values (e.g QS101EW0001,QS101EW0002), but not ranges.
+	//
+	// Multiple cols parameters can be supplied, e.g. cols=QS101EW0001&cols=QS101EW0002
+	Cols *[]string `json:"cols,omitempty"`
+
+	// Data version, as listed by /versions. The default is the latest public version for the year.
+	// Non-public versions need the API token in the Authorization header.
+	Version *string `json:"version,omitempty"`
 }
 
 // ServerInterface represents all server handlers.
 type ServerInterface interface {
+	// Sum census data over an area
+	// (GET /aggregate/{year})
+	GetAggregate(w http.ResponseWriter, r *http.Request, year int, params GetAggregateParams)
+	// calculate class breaks over a given category and geography type
+	// (GET /breaks/{year})
+	GetBreaks(w http.ResponseWriter, r *http.Request, year int, params GetBreaksParams)
 	// calculate ckmeans over a given category and geography type
 	// (GET /ckmeans/{year})
 	GetCkmeansYear(w http.ResponseWriter, r *http.Request, year int, params GetCkmeansYearParams)
@@ -222,27 +587,60 @@ type ServerInterface interface {
 	// remove all entries from request cache
 	// (GET /clear-cache)
 	GetClearCache(w http.ResponseWriter, r *http.Request)
+	// summarise the distribution of a category, with a histogram
+	// (GET /distribution/{year})
+	GetDistribution(w http.ResponseWriter, r *http.Request, year int, params GetDistributionParams)
 	// Get geographic info about an area. Queryable with either geocode or geoname (but not both)
 	// (GET /geo/{year})
 	GetGeo(w http.ResponseWriter, r *http.Request, year int, params GetGeoParams)
+	// List the geographies above an area in the geography hierarchy
+	// (GET /geo/{year}/{geocode}/ancestors)
+	GetGeoAncestors(w http.ResponseWriter, r *http.Request, year int, geocode string)
+	// List the geographies below an area in the geography hierarchy
+	// (GET /geo/{year}/{geocode}/children)
+	GetGeoChildren(w http.ResponseWriter, r *http.Request, year int, geocode string, params GetGeoChildrenParams)
+	// Find the areas that contain a point
+	// (GET /locate/{year})
+	GetLocate(w http.ResponseWriter, r *http.Request, year int, params GetLocateParams)
 	// Get Metadata
 	// (GET /metadata/{year})
 	GetMetadataYear(w http.ResponseWriter, r *http.Request, year int, params GetMetadataYearParams)
+	// Get metadata for one category
+	// (GET /metadata/{year}/categories/{code})
+	GetMetadataCategory(w http.ResponseWriter, r *http.Request, year int, code string, params GetMetadataCategoryParams)
+	// Search table and category names
+	// (GET /metadata/{year}/search)
+	GetMetadataSearch(w http.ResponseWriter, r *http.Request, year int, params GetMetadataSearchParams)
+	// Get metadata for one table
+	// (GET /metadata/{year}/tables/{code})
+	GetMetadataTable(w http.ResponseWriter, r *http.Request, year int, code string, params GetMetadataTableParams)
 	// return MSOA code and its name
 	// (GET /msoa/{postcode})
 	GetMsoaPostcode(w http.ResponseWriter, r *http.Request, postcode string)
+	// Look up every geography containing a postcode
+	// (GET /postcode/{postcode})
+	GetPostcode(w http.ResponseWriter, r *http.Request, postcode string)
 	// query census
 	// (GET /query/{year})
 	GetQueryYear(w http.ResponseWriter, r *http.Request, year int, params GetQueryYearParams)
 	// List geocodes matching search conditions
 	// (GET /query2/{year})
 	GetQuery(w http.ResponseWriter, r *http.Request, year int, params GetQueryParams)
+	// Search geography names, for autocompletion
+	// (GET /search/{year})
+	GetSearch(w http.ResponseWriter, r *http.Request, year int, params GetSearchParams)
 	// spec
 	// (GET /swagger)
 	GetSwagger(w http.ResponseWriter, r *http.Request)
 	// spec
 	// (GET /swaggerui)
 	GetSwaggerui(w http.ResponseWriter, r *http.Request)
+	// Get a Mapbox Vector Tile of boundaries, with census data
+	// (GET /tiles/{year}/{geotype}/{z}/{x}/{y}.pbf)
+	GetTile(w http.ResponseWriter, r *http.Request, year int, geotype string, z int, x int, y int, params GetTileParams)
+	// List data versions
+	// (GET /versions)
+	GetVersions(w http.ResponseWriter, r *http.Request)
 	// CORS preflight OPTIONS request
 	// (OPTIONS /{path}/{year})
 	Preflight(w http.ResponseWriter, r *http.Request, path string, year int)
@@ -256,8 +654,8 @@ type ServerInterfaceWrapper struct {
 
 type MiddlewareFunc func(http.HandlerFunc) http.HandlerFunc
 
-// GetCkmeansYear operation middleware
-func (siw *ServerInterfaceWrapper) GetCkmeansYear(w http.ResponseWriter, r *http.Request) {
+// GetAggregate operation middleware
+func (siw *ServerInterfaceWrapper) GetAggregate(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	var err error
@@ -272,127 +670,120 @@ func (siw *ServerInterfaceWrapper) GetCkmeansYear(w http.ResponseWriter, r *http
 	}
 
 	// Parameter object where we will unmarshal all parameters from the context
-	var params GetCkmeansYearParams
+	var params GetAggregateParams
 
-	// ------------- Optional query parameter "cat" -------------
-	if paramValue := r.URL.Query().Get("cat"); paramValue != "" {
+	// ------------- Optional query parameter "rows" -------------
+	if paramValue := r.URL.Query().Get("rows"); paramValue != "" {
 
 	}
 
-	err = runtime.BindQueryParameter("form", true, false, "cat", r.URL.Query(), &params.Cat)
+	err = runtime.BindQueryParameter("form", true, false, "rows", r.URL.Query(), &params.Rows)
 	if err != nil {
-		http.Error(w, fmt.Sprintf("Invalid format for parameter cat: %s", err), http.StatusBadRequest)
+		http.Error(w, fmt.Sprintf("Invalid format for parameter rows: %s", err), http.StatusBadRequest)
 		return
 	}
 
-	// ------------- Optional query parameter "geotype" -------------
-	if paramValue := r.URL.Query().Get("geotype"); paramValue != "" {
+	// ------------- Optional query parameter "cols" -------------
+	if paramValue := r.URL.Query().Get("cols"); paramValue != "" {
 
 	}
 
-	err = runtime.BindQueryParameter("form", true, false, "geotype", r.URL.Query(), &params.Geotype)
+	err = runtime.BindQueryParameter("form", true, false, "cols", r.URL.Query(), &params.Cols)
 	if err != nil {
-		http.Error(w, fmt.Sprintf("Invalid format for parameter geotype: %s", err), http.StatusBadRequest)
+		http.Error(w, fmt.Sprintf("Invalid format for parameter cols: %s", err), http.StatusBadRequest)
 		return
 	}
 
-	// ------------- Optional query parameter "k" -------------
-	if paramValue := r.URL.Query().Get("k"); paramValue != "" {
+	// ------------- Optional query parameter "bbox" -------------
+	if paramValue := r.URL.Query().Get("bbox"); paramValue != "" {
 
 	}
 
-	err = runtime.BindQueryParameter("form", true, false, "k", r.URL.Query(), &params.K)
+	err = runtime.BindQueryParameter("form", true, false, "bbox", r.URL.Query(), &params.Bbox)
 	if err != nil {
-		http.Error(w, fmt.Sprintf("Invalid format for parameter k: %s", err), http.StatusBadRequest)
+		http.Error(w, fmt.Sprintf("Invalid format for parameter bbox: %s", err), http.StatusBadRequest)
 		return
 	}
 
-	// ------------- Optional query parameter "divide_by" -------------
-	if paramValue := r.URL.Query().Get("divide_by"); paramValue != "" {
+	// ------------- Optional query parameter "geotype" -------------
+	if paramValue := r.URL.Query().Get("geotype"); paramValue != "" {
 
 	}
 
-	err = runtime.BindQueryParameter("form", true, false, "divide_by", r.URL.Query(), &params.DivideBy)
+	err = runtime.BindQueryParameter("form", true, false, "geotype", r.URL.Query(), &params.Geotype)
 	if err != nil {
-		http.Error(w, fmt.Sprintf("Invalid format for parameter divide_by: %s", err), http.StatusBadRequest)
+		http.Error(w, fmt.Sprintf("Invalid format for parameter geotype: %s", err), http.StatusBadRequest)
 		return
 	}
 
-	var handler = func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetCkmeansYear(w, r, year, params)
-	}
+	// ------------- Optional query parameter "location" -------------
+	if paramValue := r.URL.Query().Get("location"); paramValue != "" {
 
-	for _, middleware := range siw.HandlerMiddlewares {
-		handler = middleware(handler)
 	}
 
-	handler(w, r.WithContext(ctx))
-}
-
-// GetCkmeansratioYear operation middleware
-func (siw *ServerInterfaceWrapper) GetCkmeansratioYear(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+	err = runtime.BindQueryParameter("form", true, false, "location", r.URL.Query(), &params.Location)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter location: %s", err), http.StatusBadRequest)
+		return
+	}
 
-	var err error
+	// ------------- Optional query parameter "radius" -------------
+	if paramValue := r.URL.Query().Get("radius"); paramValue != "" {
 
-	// ------------- Path parameter "year" -------------
-	var year int
+	}
 
-	err = runtime.BindStyledParameter("simple", false, "year", chi.URLParam(r, "year"), &year)
+	err = runtime.BindQueryParameter("form", true, false, "radius", r.URL.Query(), &params.Radius)
 	if err != nil {
-		http.Error(w, fmt.Sprintf("Invalid format for parameter year: %s", err), http.StatusBadRequest)
+		http.Error(w, fmt.Sprintf("Invalid format for parameter radius: %s", err), http.StatusBadRequest)
 		return
 	}
 
-	// Parameter object where we will unmarshal all parameters from the context
-	var params GetCkmeansratioYearParams
-
-	// ------------- Optional query parameter "cat1" -------------
-	if paramValue := r.URL.Query().Get("cat1"); paramValue != "" {
+	// ------------- Optional query parameter "polygon" -------------
+	if paramValue := r.URL.Query().Get("polygon"); paramValue != "" {
 
 	}
 
-	err = runtime.BindQueryParameter("form", true, false, "cat1", r.URL.Query(), &params.Cat1)
+	err = runtime.BindQueryParameter("form", true, false, "polygon", r.URL.Query(), &params.Polygon)
 	if err != nil {
-		http.Error(w, fmt.Sprintf("Invalid format for parameter cat1: %s", err), http.StatusBadRequest)
+		http.Error(w, fmt.Sprintf("Invalid format for parameter polygon: %s", err), http.StatusBadRequest)
 		return
 	}
 
-	// ------------- Optional query parameter "cat2" -------------
-	if paramValue := r.URL.Query().Get("cat2"); paramValue != "" {
+	// ------------- Optional query parameter "censustable" -------------
+	if paramValue := r.URL.Query().Get("censustable"); paramValue != "" {
 
 	}
 
-	err = runtime.BindQueryParameter("form", true, false, "cat2", r.URL.Query(), &params.Cat2)
+	err = runtime.BindQueryParameter("form", true, false, "censustable", r.URL.Query(), &params.Censustable)
 	if err != nil {
-		http.Error(w, fmt.Sprintf("Invalid format for parameter cat2: %s", err), http.StatusBadRequest)
+		http.Error(w, fmt.Sprintf("Invalid format for parameter censustable: %s", err), http.StatusBadRequest)
 		return
 	}
 
-	// ------------- Optional query parameter "geotype" -------------
-	if paramValue := r.URL.Query().Get("geotype"); paramValue != "" {
+	// ------------- Optional query parameter "method" -------------
+	if paramValue := r.URL.Query().Get("method"); paramValue != "" {
 
 	}
 
-	err = runtime.BindQueryParameter("form", true, false, "geotype", r.URL.Query(), &params.Geotype)
+	err = runtime.BindQueryParameter("form", true, false, "method", r.URL.Query(), &params.Method)
 	if err != nil {
-		http.Error(w, fmt.Sprintf("Invalid format for parameter geotype: %s", err), http.StatusBadRequest)
+		http.Error(w, fmt.Sprintf("Invalid format for parameter method: %s", err), http.StatusBadRequest)
 		return
 	}
 
-	// ------------- Optional query parameter "k" -------------
-	if paramValue := r.URL.Query().Get("k"); paramValue != "" {
+	// ------------- Optional query parameter "version" -------------
+	if paramValue := r.URL.Query().Get("version"); paramValue != "" {
 
 	}
 
-	err = runtime.BindQueryParameter("form", true, false, "k", r.URL.Query(), &params.K)
+	err = runtime.BindQueryParameter("form", true, false, "version", r.URL.Query(), &params.Version)
 	if err != nil {
-		http.Error(w, fmt.Sprintf("Invalid format for parameter k: %s", err), http.StatusBadRequest)
+		http.Error(w, fmt.Sprintf("Invalid format for parameter version: %s", err), http.StatusBadRequest)
 		return
 	}
 
 	var handler = func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetCkmeansratioYear(w, r, year, params)
+		siw.Handler.GetAggregate(w, r, year, params)
 	}
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -402,23 +793,641 @@ func (siw *ServerInterfaceWrapper) GetCkmeansratioYear(w http.ResponseWriter, r
 	handler(w, r.WithContext(ctx))
 }
 
-// GetClearCache operation middleware
-func (siw *ServerInterfaceWrapper) GetClearCache(w http.ResponseWriter, r *http.Request) {
+// GetBreaks operation middleware
+func (siw *ServerInterfaceWrapper) GetBreaks(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	var handler = func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetClearCache(w, r)
-	}
+	var err error
 
-	for _, middleware := range siw.HandlerMiddlewares {
-		handler = middleware(handler)
-	}
+	// ------------- Path parameter "year" -------------
+	var year int
 
-	handler(w, r.WithContext(ctx))
-}
+	err = runtime.BindStyledParameter("simple", false, "year", chi.URLParam(r, "year"), &year)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter year: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetBreaksParams
+
+	// ------------- Optional query parameter "cat" -------------
+	if paramValue := r.URL.Query().Get("cat"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "cat", r.URL.Query(), &params.Cat)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter cat: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "geotype" -------------
+	if paramValue := r.URL.Query().Get("geotype"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "geotype", r.URL.Query(), &params.Geotype)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter geotype: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "k" -------------
+	if paramValue := r.URL.Query().Get("k"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "k", r.URL.Query(), &params.K)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter k: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "divide_by" -------------
+	if paramValue := r.URL.Query().Get("divide_by"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "divide_by", r.URL.Query(), &params.DivideBy)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter divide_by: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "method" -------------
+	if paramValue := r.URL.Query().Get("method"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "method", r.URL.Query(), &params.Method)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter method: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "rows" -------------
+	if paramValue := r.URL.Query().Get("rows"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "rows", r.URL.Query(), &params.Rows)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter rows: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "bbox" -------------
+	if paramValue := r.URL.Query().Get("bbox"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "bbox", r.URL.Query(), &params.Bbox)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter bbox: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "location" -------------
+	if paramValue := r.URL.Query().Get("location"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "location", r.URL.Query(), &params.Location)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter location: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "radius" -------------
+	if paramValue := r.URL.Query().Get("radius"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "radius", r.URL.Query(), &params.Radius)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter radius: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "polygon" -------------
+	if paramValue := r.URL.Query().Get("polygon"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "polygon", r.URL.Query(), &params.Polygon)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter polygon: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "within" -------------
+	if paramValue := r.URL.Query().Get("within"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "within", r.URL.Query(), &params.Within)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter within: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "version" -------------
+	if paramValue := r.URL.Query().Get("version"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "version", r.URL.Query(), &params.Version)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter version: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetBreaks(w, r, year, params)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
+// GetCkmeansYear operation middleware
+func (siw *ServerInterfaceWrapper) GetCkmeansYear(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "year" -------------
+	var year int
+
+	err = runtime.BindStyledParameter("simple", false, "year", chi.URLParam(r, "year"), &year)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter year: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetCkmeansYearParams
+
+	// ------------- Optional query parameter "cat" -------------
+	if paramValue := r.URL.Query().Get("cat"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "cat", r.URL.Query(), &params.Cat)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter cat: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "geotype" -------------
+	if paramValue := r.URL.Query().Get("geotype"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "geotype", r.URL.Query(), &params.Geotype)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter geotype: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "k" -------------
+	if paramValue := r.URL.Query().Get("k"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "k", r.URL.Query(), &params.K)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter k: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "divide_by" -------------
+	if paramValue := r.URL.Query().Get("divide_by"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "divide_by", r.URL.Query(), &params.DivideBy)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter divide_by: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "rows" -------------
+	if paramValue := r.URL.Query().Get("rows"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "rows", r.URL.Query(), &params.Rows)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter rows: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "bbox" -------------
+	if paramValue := r.URL.Query().Get("bbox"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "bbox", r.URL.Query(), &params.Bbox)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter bbox: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "location" -------------
+	if paramValue := r.URL.Query().Get("location"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "location", r.URL.Query(), &params.Location)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter location: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "radius" -------------
+	if paramValue := r.URL.Query().Get("radius"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "radius", r.URL.Query(), &params.Radius)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter radius: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "polygon" -------------
+	if paramValue := r.URL.Query().Get("polygon"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "polygon", r.URL.Query(), &params.Polygon)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter polygon: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "within" -------------
+	if paramValue := r.URL.Query().Get("within"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "within", r.URL.Query(), &params.Within)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter within: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "version" -------------
+	if paramValue := r.URL.Query().Get("version"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "version", r.URL.Query(), &params.Version)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter version: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetCkmeansYear(w, r, year, params)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
+// GetCkmeansratioYear operation middleware
+func (siw *ServerInterfaceWrapper) GetCkmeansratioYear(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "year" -------------
+	var year int
+
+	err = runtime.BindStyledParameter("simple", false, "year", chi.URLParam(r, "year"), &year)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter year: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetCkmeansratioYearParams
+
+	// ------------- Optional query parameter "cat1" -------------
+	if paramValue := r.URL.Query().Get("cat1"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "cat1", r.URL.Query(), &params.Cat1)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter cat1: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "cat2" -------------
+	if paramValue := r.URL.Query().Get("cat2"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "cat2", r.URL.Query(), &params.Cat2)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter cat2: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "geotype" -------------
+	if paramValue := r.URL.Query().Get("geotype"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "geotype", r.URL.Query(), &params.Geotype)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter geotype: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "k" -------------
+	if paramValue := r.URL.Query().Get("k"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "k", r.URL.Query(), &params.K)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter k: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "version" -------------
+	if paramValue := r.URL.Query().Get("version"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "version", r.URL.Query(), &params.Version)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter version: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetCkmeansratioYear(w, r, year, params)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
+// GetClearCache operation middleware
+func (siw *ServerInterfaceWrapper) GetClearCache(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetClearCache(w, r)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
+// GetDistribution operation middleware
+func (siw *ServerInterfaceWrapper) GetDistribution(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "year" -------------
+	var year int
+
+	err = runtime.BindStyledParameter("simple", false, "year", chi.URLParam(r, "year"), &year)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter year: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetDistributionParams
+
+	// ------------- Optional query parameter "cat" -------------
+	if paramValue := r.URL.Query().Get("cat"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "cat", r.URL.Query(), &params.Cat)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter cat: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "geotype" -------------
+	if paramValue := r.URL.Query().Get("geotype"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "geotype", r.URL.Query(), &params.Geotype)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter geotype: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "bins" -------------
+	if paramValue := r.URL.Query().Get("bins"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "bins", r.URL.Query(), &params.Bins)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter bins: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "breaks" -------------
+	if paramValue := r.URL.Query().Get("breaks"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "breaks", r.URL.Query(), &params.Breaks)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter breaks: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "divide_by" -------------
+	if paramValue := r.URL.Query().Get("divide_by"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "divide_by", r.URL.Query(), &params.DivideBy)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter divide_by: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "rows" -------------
+	if paramValue := r.URL.Query().Get("rows"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "rows", r.URL.Query(), &params.Rows)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter rows: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "bbox" -------------
+	if paramValue := r.URL.Query().Get("bbox"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "bbox", r.URL.Query(), &params.Bbox)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter bbox: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "location" -------------
+	if paramValue := r.URL.Query().Get("location"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "location", r.URL.Query(), &params.Location)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter location: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "radius" -------------
+	if paramValue := r.URL.Query().Get("radius"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "radius", r.URL.Query(), &params.Radius)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter radius: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "polygon" -------------
+	if paramValue := r.URL.Query().Get("polygon"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "polygon", r.URL.Query(), &params.Polygon)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter polygon: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "within" -------------
+	if paramValue := r.URL.Query().Get("within"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "within", r.URL.Query(), &params.Within)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter within: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "version" -------------
+	if paramValue := r.URL.Query().Get("version"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "version", r.URL.Query(), &params.Version)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter version: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetDistribution(w, r, year, params)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
+// GetGeo operation middleware
+func (siw *ServerInterfaceWrapper) GetGeo(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "year" -------------
+	var year int
+
+	err = runtime.BindStyledParameter("simple", false, "year", chi.URLParam(r, "year"), &year)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter year: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetGeoParams
+
+	// ------------- Optional query parameter "geocode" -------------
+	if paramValue := r.URL.Query().Get("geocode"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "geocode", r.URL.Query(), &params.Geocode)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter geocode: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "geoname" -------------
+	if paramValue := r.URL.Query().Get("geoname"); paramValue != "" {
+
+	}
 
-// GetGeo operation middleware
-func (siw *ServerInterfaceWrapper) GetGeo(w http.ResponseWriter, r *http.Request) {
+	err = runtime.BindQueryParameter("form", true, false, "geoname", r.URL.Query(), &params.Geoname)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter geoname: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "lang" -------------
+	if paramValue := r.URL.Query().Get("lang"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "lang", r.URL.Query(), &params.Lang)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter lang: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetGeo(w, r, year, params)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
+// GetGeoAncestors operation middleware
+func (siw *ServerInterfaceWrapper) GetGeoAncestors(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	var err error
@@ -432,33 +1441,109 @@ func (siw *ServerInterfaceWrapper) GetGeo(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	// Parameter object where we will unmarshal all parameters from the context
-	var params GetGeoParams
+	// ------------- Path parameter "geocode" -------------
+	var geocode string
 
-	// ------------- Optional query parameter "geocode" -------------
-	if paramValue := r.URL.Query().Get("geocode"); paramValue != "" {
+	err = runtime.BindStyledParameter("simple", false, "geocode", chi.URLParam(r, "geocode"), &geocode)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter geocode: %s", err), http.StatusBadRequest)
+		return
+	}
 
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetGeoAncestors(w, r, year, geocode)
 	}
 
-	err = runtime.BindQueryParameter("form", true, false, "geocode", r.URL.Query(), &params.Geocode)
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
+// GetGeoChildren operation middleware
+func (siw *ServerInterfaceWrapper) GetGeoChildren(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "year" -------------
+	var year int
+
+	err = runtime.BindStyledParameter("simple", false, "year", chi.URLParam(r, "year"), &year)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter year: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Path parameter "geocode" -------------
+	var geocode string
+
+	err = runtime.BindStyledParameter("simple", false, "geocode", chi.URLParam(r, "geocode"), &geocode)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Invalid format for parameter geocode: %s", err), http.StatusBadRequest)
 		return
 	}
 
-	// ------------- Optional query parameter "geoname" -------------
-	if paramValue := r.URL.Query().Get("geoname"); paramValue != "" {
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetGeoChildrenParams
+
+	// ------------- Optional query parameter "geotype" -------------
+	if paramValue := r.URL.Query().Get("geotype"); paramValue != "" {
 
 	}
 
-	err = runtime.BindQueryParameter("form", true, false, "geoname", r.URL.Query(), &params.Geoname)
+	err = runtime.BindQueryParameter("form", true, false, "geotype", r.URL.Query(), &params.Geotype)
 	if err != nil {
-		http.Error(w, fmt.Sprintf("Invalid format for parameter geoname: %s", err), http.StatusBadRequest)
+		http.Error(w, fmt.Sprintf("Invalid format for parameter geotype: %s", err), http.StatusBadRequest)
 		return
 	}
 
 	var handler = func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetGeo(w, r, year, params)
+		siw.Handler.GetGeoChildren(w, r, year, geocode, params)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
+// GetLocate operation middleware
+func (siw *ServerInterfaceWrapper) GetLocate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "year" -------------
+	var year int
+
+	err = runtime.BindStyledParameter("simple", false, "year", chi.URLParam(r, "year"), &year)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter year: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetLocateParams
+
+	// ------------- Required query parameter "location" -------------
+	if paramValue := r.URL.Query().Get("location"); paramValue != "" {
+
+	} else {
+		http.Error(w, "Query argument location is required, but not found", http.StatusBadRequest)
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "location", r.URL.Query(), &params.Location)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter location: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetLocate(w, r, year, params)
 	}
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -491,14 +1576,214 @@ func (siw *ServerInterfaceWrapper) GetMetadataYear(w http.ResponseWriter, r *htt
 
 	}
 
-	err = runtime.BindQueryParameter("form", true, false, "filtertotals", r.URL.Query(), &params.Filtertotals)
+	err = runtime.BindQueryParameter("form", true, false, "filtertotals", r.URL.Query(), &params.Filtertotals)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter filtertotals: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "lang" -------------
+	if paramValue := r.URL.Query().Get("lang"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "lang", r.URL.Query(), &params.Lang)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter lang: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetMetadataYear(w, r, year, params)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
+// GetMetadataCategory operation middleware
+func (siw *ServerInterfaceWrapper) GetMetadataCategory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "year" -------------
+	var year int
+
+	err = runtime.BindStyledParameter("simple", false, "year", chi.URLParam(r, "year"), &year)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter year: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Path parameter "code" -------------
+	var code string
+
+	err = runtime.BindStyledParameter("simple", false, "code", chi.URLParam(r, "code"), &code)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter code: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetMetadataCategoryParams
+
+	// ------------- Optional query parameter "lang" -------------
+	if paramValue := r.URL.Query().Get("lang"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "lang", r.URL.Query(), &params.Lang)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter lang: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetMetadataCategory(w, r, year, code, params)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
+// GetMetadataSearch operation middleware
+func (siw *ServerInterfaceWrapper) GetMetadataSearch(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "year" -------------
+	var year int
+
+	err = runtime.BindStyledParameter("simple", false, "year", chi.URLParam(r, "year"), &year)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter year: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetMetadataSearchParams
+
+	// ------------- Required query parameter "q" -------------
+	if paramValue := r.URL.Query().Get("q"); paramValue != "" {
+
+	} else {
+		http.Error(w, "Query argument q is required, but not found", http.StatusBadRequest)
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "q", r.URL.Query(), &params.Q)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter q: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+	if paramValue := r.URL.Query().Get("limit"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter limit: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "lang" -------------
+	if paramValue := r.URL.Query().Get("lang"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "lang", r.URL.Query(), &params.Lang)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter lang: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetMetadataSearch(w, r, year, params)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
+// GetMetadataTable operation middleware
+func (siw *ServerInterfaceWrapper) GetMetadataTable(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "year" -------------
+	var year int
+
+	err = runtime.BindStyledParameter("simple", false, "year", chi.URLParam(r, "year"), &year)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter year: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Path parameter "code" -------------
+	var code string
+
+	err = runtime.BindStyledParameter("simple", false, "code", chi.URLParam(r, "code"), &code)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter code: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetMetadataTableParams
+
+	// ------------- Optional query parameter "lang" -------------
+	if paramValue := r.URL.Query().Get("lang"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "lang", r.URL.Query(), &params.Lang)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter lang: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetMetadataTable(w, r, year, code, params)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
+// GetMsoaPostcode operation middleware
+func (siw *ServerInterfaceWrapper) GetMsoaPostcode(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "postcode" -------------
+	var postcode string
+
+	err = runtime.BindStyledParameter("simple", false, "postcode", chi.URLParam(r, "postcode"), &postcode)
 	if err != nil {
-		http.Error(w, fmt.Sprintf("Invalid format for parameter filtertotals: %s", err), http.StatusBadRequest)
+		http.Error(w, fmt.Sprintf("Invalid format for parameter postcode: %s", err), http.StatusBadRequest)
 		return
 	}
 
 	var handler = func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetMetadataYear(w, r, year, params)
+		siw.Handler.GetMsoaPostcode(w, r, postcode)
 	}
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -508,8 +1793,8 @@ func (siw *ServerInterfaceWrapper) GetMetadataYear(w http.ResponseWriter, r *htt
 	handler(w, r.WithContext(ctx))
 }
 
-// GetMsoaPostcode operation middleware
-func (siw *ServerInterfaceWrapper) GetMsoaPostcode(w http.ResponseWriter, r *http.Request) {
+// GetPostcode operation middleware
+func (siw *ServerInterfaceWrapper) GetPostcode(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	var err error
@@ -524,7 +1809,7 @@ func (siw *ServerInterfaceWrapper) GetMsoaPostcode(w http.ResponseWriter, r *htt
 	}
 
 	var handler = func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetMsoaPostcode(w, r, postcode)
+		siw.Handler.GetPostcode(w, r, postcode)
 	}
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -640,6 +1925,28 @@ func (siw *ServerInterfaceWrapper) GetQueryYear(w http.ResponseWriter, r *http.R
 		return
 	}
 
+	// ------------- Optional query parameter "format" -------------
+	if paramValue := r.URL.Query().Get("format"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "format", r.URL.Query(), &params.Format)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter format: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "version" -------------
+	if paramValue := r.URL.Query().Get("version"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "version", r.URL.Query(), &params.Version)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter version: %s", err), http.StatusBadRequest)
+		return
+	}
+
 	var handler = func(w http.ResponseWriter, r *http.Request) {
 		siw.Handler.GetQueryYear(w, r, year, params)
 	}
@@ -746,6 +2053,17 @@ func (siw *ServerInterfaceWrapper) GetQuery(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	// ------------- Optional query parameter "within" -------------
+	if paramValue := r.URL.Query().Get("within"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "within", r.URL.Query(), &params.Within)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter within: %s", err), http.StatusBadRequest)
+		return
+	}
+
 	// ------------- Optional query parameter "censustable" -------------
 	if paramValue := r.URL.Query().Get("censustable"); paramValue != "" {
 
@@ -757,6 +2075,83 @@ func (siw *ServerInterfaceWrapper) GetQuery(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	// ------------- Optional query parameter "format" -------------
+	if paramValue := r.URL.Query().Get("format"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "format", r.URL.Query(), &params.Format)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter format: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+	if paramValue := r.URL.Query().Get("limit"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter limit: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "cursor" -------------
+	if paramValue := r.URL.Query().Get("cursor"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "cursor", r.URL.Query(), &params.Cursor)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter cursor: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "simplify" -------------
+	if paramValue := r.URL.Query().Get("simplify"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "simplify", r.URL.Query(), &params.Simplify)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter simplify: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "divide_by" -------------
+	if paramValue := r.URL.Query().Get("divide_by"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "divide_by", r.URL.Query(), &params.DivideBy)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter divide_by: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "percent_of" -------------
+	if paramValue := r.URL.Query().Get("percent_of"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "percent_of", r.URL.Query(), &params.PercentOf)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter percent_of: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "version" -------------
+	if paramValue := r.URL.Query().Get("version"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "version", r.URL.Query(), &params.Version)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter version: %s", err), http.StatusBadRequest)
+		return
+	}
+
 	var handler = func(w http.ResponseWriter, r *http.Request) {
 		siw.Handler.GetQuery(w, r, year, params)
 	}
@@ -768,6 +2163,93 @@ func (siw *ServerInterfaceWrapper) GetQuery(w http.ResponseWriter, r *http.Reque
 	handler(w, r.WithContext(ctx))
 }
 
+// GetSearch operation middleware
+func (siw *ServerInterfaceWrapper) GetSearch(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "year" -------------
+	var year int
+
+	err = runtime.BindStyledParameter("simple", false, "year", chi.URLParam(r, "year"), &year)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter year: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetSearchParams
+
+	// ------------- Required query parameter "q" -------------
+	if paramValue := r.URL.Query().Get("q"); paramValue != "" {
+
+	} else {
+		http.Error(w, "Query argument q is required, but not found", http.StatusBadRequest)
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "q", r.URL.Query(), &params.Q)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter q: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "geotype" -------------
+	if paramValue := r.URL.Query().Get("geotype"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "geotype", r.URL.Query(), &params.Geotype)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter geotype: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "bbox" -------------
+	if paramValue := r.URL.Query().Get("bbox"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "bbox", r.URL.Query(), &params.Bbox)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter bbox: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+	if paramValue := r.URL.Query().Get("limit"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter limit: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "lang" -------------
+	if paramValue := r.URL.Query().Get("lang"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "lang", r.URL.Query(), &params.Lang)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter lang: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetSearch(w, r, year, params)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
 // GetSwagger operation middleware
 func (siw *ServerInterfaceWrapper) GetSwagger(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -798,6 +2280,108 @@ func (siw *ServerInterfaceWrapper) GetSwaggerui(w http.ResponseWriter, r *http.R
 	handler(w, r.WithContext(ctx))
 }
 
+// GetTile operation middleware
+func (siw *ServerInterfaceWrapper) GetTile(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "year" -------------
+	var year int
+
+	err = runtime.BindStyledParameter("simple", false, "year", chi.URLParam(r, "year"), &year)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter year: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Path parameter "geotype" -------------
+	var geotype string
+
+	err = runtime.BindStyledParameter("simple", false, "geotype", chi.URLParam(r, "geotype"), &geotype)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter geotype: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Path parameter "z" -------------
+	var z int
+
+	err = runtime.BindStyledParameter("simple", false, "z", chi.URLParam(r, "z"), &z)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter z: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Path parameter "x" -------------
+	var x int
+
+	err = runtime.BindStyledParameter("simple", false, "x", chi.URLParam(r, "x"), &x)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter x: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Path parameter "y" -------------
+	var y int
+
+	err = runtime.BindStyledParameter("simple", false, "y", chi.URLParam(r, "y"), &y)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter y: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetTileParams
+
+	// ------------- Optional query parameter "cols" -------------
+	if paramValue := r.URL.Query().Get("cols"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "cols", r.URL.Query(), &params.Cols)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter cols: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "version" -------------
+	if paramValue := r.URL.Query().Get("version"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "version", r.URL.Query(), &params.Version)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid format for parameter version: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetTile(w, r, year, geotype, z, x, y, params)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
+// GetVersions operation middleware
+func (siw *ServerInterfaceWrapper) GetVersions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetVersions(w, r)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
 // Preflight operation middleware
 func (siw *ServerInterfaceWrapper) Preflight(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -870,6 +2454,12 @@ func HandlerWithOptions(si ServerInterface, options ChiServerOptions) http.Handl
 		HandlerMiddlewares: options.Middlewares,
 	}
 
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/aggregate/{year}", wrapper.GetAggregate)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/breaks/{year}", wrapper.GetBreaks)
+	})
 	r.Group(func(r chi.Router) {
 		r.Get(options.BaseURL+"/ckmeans/{year}", wrapper.GetCkmeansYear)
 	})
@@ -879,27 +2469,60 @@ func HandlerWithOptions(si ServerInterface, options ChiServerOptions) http.Handl
 	r.Group(func(r chi.Router) {
 		r.Get(options.BaseURL+"/clear-cache", wrapper.GetClearCache)
 	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/distribution/{year}", wrapper.GetDistribution)
+	})
 	r.Group(func(r chi.Router) {
 		r.Get(options.BaseURL+"/geo/{year}", wrapper.GetGeo)
 	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/geo/{year}/{geocode}/ancestors", wrapper.GetGeoAncestors)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/geo/{year}/{geocode}/children", wrapper.GetGeoChildren)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/locate/{year}", wrapper.GetLocate)
+	})
 	r.Group(func(r chi.Router) {
 		r.Get(options.BaseURL+"/metadata/{year}", wrapper.GetMetadataYear)
 	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/metadata/{year}/categories/{code}", wrapper.GetMetadataCategory)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/metadata/{year}/search", wrapper.GetMetadataSearch)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/metadata/{year}/tables/{code}", wrapper.GetMetadataTable)
+	})
 	r.Group(func(r chi.Router) {
 		r.Get(options.BaseURL+"/msoa/{postcode}", wrapper.GetMsoaPostcode)
 	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/postcode/{postcode}", wrapper.GetPostcode)
+	})
 	r.Group(func(r chi.Router) {
 		r.Get(options.BaseURL+"/query/{year}", wrapper.GetQueryYear)
 	})
 	r.Group(func(r chi.Router) {
 		r.Get(options.BaseURL+"/query2/{year}", wrapper.GetQuery)
 	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/search/{year}", wrapper.GetSearch)
+	})
 	r.Group(func(r chi.Router) {
 		r.Get(options.BaseURL+"/swagger", wrapper.GetSwagger)
 	})
 	r.Group(func(r chi.Router) {
 		r.Get(options.BaseURL+"/swaggerui", wrapper.GetSwaggerui)
 	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/tiles/{year}/{geotype}/{z}/{x}/{y}.pbf", wrapper.GetTile)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/versions", wrapper.GetVersions)
+	})
 	r.Group(func(r chi.Router) {
 		r.Options(options.BaseURL+"/{path}/{year}", wrapper.Preflight)
 	})
@@ -9,7 +9,7 @@
 // a cache key before they are permitted to access the cache.
 //
 // General usage:
-// 1. application calls New to set up the cache manager
+// 1. application calls New or NewStore to set up the cache manager
 // 2. on each request:
 //	a. generate a cache key based on the request
 //	b. allocate an Entry for this cache key
@@ -17,35 +17,120 @@
 //	d. unlock the Entry after cache operations are complete, but before writing to client
 //	3. release the cache key entry
 //
+// Responses that are streamed to the client can't be cached in one Set.
+// Instead, the Entry stays locked while the response is written through a Tee,
+// which keeps a copy to save in the cache once the response is complete.
+// Since that includes the time taken to write to the client, other requests for the
+// key should wait with LockWithin, and go without the cache if it times out.
+//
+// The cache itself can be an in-process bigcache, a Redis-protocol server shared by
+// all instances, or a chain of the two with bigcache in front (see NewStore).
+//
 package cache
 
 import (
 	"context"
+	"fmt"
 	"sync"
 	"time"
 
+	"github.com/ONSdigital/dp-healthcheck/healthcheck"
 	"github.com/allegro/bigcache/v3"
 	"github.com/eko/gocache/v2/cache"
 	"github.com/eko/gocache/v2/store"
+	"github.com/go-redis/redis/v8"
+)
+
+// Store types accepted by NewStore.
+const (
+	StoreBigcache = "bigcache" // in-process cache, private to each instance
+	StoreRedis    = "redis"    // Redis-protocol server, shared by all instances
+	StoreChain    = "chain"    // bigcache in front of Redis
 )
 
+// Redis says how to reach the Redis-protocol server used by the redis and chain stores.
+type Redis struct {
+	Addr     string // host:port
+	Password string
+	DB       int
+}
+
 // A Manager manages the underlying cache and the dynamic set of Entries.
 type Manager struct {
-	cache      *cache.Cache      // underlying cache
-	sync.Mutex                   // protexts operations on locks and references below
-	entries    map[string]*Entry // cache access manager for each key; map index is the key
-	references map[string]int    // reference counts for each key; map index is the key
+	cache      cache.CacheInterface // underlying cache
+	local      cache.CacheInterface // in-process bigcache behind cache, if there is one
+	redis      *redis.Client        // client for the shared store, if there is one
+	maxEntry   int                  // largest value in bytes a Tee will save
+	sync.Mutex                      // protexts operations on locks and references below
+	entries    map[string]*Entry    // cache access manager for each key; map index is the key
+	references map[string]int       // reference counts for each key; map index is the key
 }
 
 // An Entry manages cache access and locking for a single cache key.
 type Entry struct {
-	key        string   // the cache key
-	manager    *Manager // our "parent" manager
-	sync.Mutex          // mutex to serialise cache operations related to this key
+	key     string        // the cache key
+	manager *Manager      // our "parent" manager
+	lock    chan struct{} // held to serialise cache operations related to this key
+}
+
+// New sets up a new cache and lock manager using an in-process bigcache.
+// maxEntryMegabytes limits the size of values saved through a Tee.
+func New(ttl time.Duration, megabytes, maxEntryMegabytes int) (*Manager, error) {
+	return NewStore(StoreBigcache, ttl, ttl, megabytes, maxEntryMegabytes, Redis{})
+}
+
+// NewStore sets up a new cache and lock manager using the storeType store.
+// megabytes sizes the bigcache of the bigcache and chain stores, and rds is only
+// used by the redis and chain stores.
+//
+// Clear on a redis or chain store flushes the Redis database rds.DB (not the other
+// databases on the server), so give the cache a database of its own.
+// In a chain, each instance's bigcache keeps its entries for localTTL (at most ttl),
+// even after another instance clears the cache, so keep localTTL short.
+//
+func NewStore(storeType string, ttl, localTTL time.Duration, megabytes, maxEntryMegabytes int, rds Redis) (*Manager, error) {
+	cm := &Manager{
+		maxEntry:   maxEntryMegabytes * 1024 * 1024,
+		entries:    map[string]*Entry{},
+		references: map[string]int{},
+	}
+
+	if (storeType == StoreRedis || storeType == StoreChain) && rds.Addr == "" {
+		return nil, fmt.Errorf("cache store %s needs a Redis address", storeType)
+	}
+
+	switch storeType {
+	case StoreBigcache:
+		bc, err := newBigcache(ttl, megabytes)
+		if err != nil {
+			return nil, err
+		}
+		cm.local = bc
+		cm.cache = bc
+	case StoreRedis:
+		cm.redis = newRedisClient(rds)
+		rc := cache.New(store.NewRedis(cm.redis, &store.Options{Expiration: ttl}))
+		cm.cache = rc
+	case StoreChain:
+		if localTTL <= 0 || localTTL > ttl {
+			localTTL = ttl
+		}
+		bc, err := newBigcache(localTTL, megabytes)
+		if err != nil {
+			return nil, err
+		}
+		cm.redis = newRedisClient(rds)
+		rc := cache.New(store.NewRedis(cm.redis, &store.Options{Expiration: ttl}))
+		cm.local = bc
+		cm.cache = cache.NewChain(bc, rc)
+	default:
+		return nil, fmt.Errorf("unknown cache store %q: want %s, %s or %s", storeType, StoreBigcache, StoreRedis, StoreChain)
+	}
+	return cm, nil
 }
 
-// New sets up a new cache and lock manager.
-func New(ttl time.Duration, megabytes int) (*Manager, error) {
+// newBigcache returns a single stage cache using an in-process bigcache.
+func newBigcache(ttl time.Duration, megabytes int) (*cache.Cache, error) {
 	// configure bigcache
 	config := bigcache.DefaultConfig(ttl)
 	config.HardMaxCacheSize = megabytes
@@ -57,21 +142,53 @@ func New(ttl time.Duration, megabytes int) (*Manager, error) {
 	}
 
 	// use bigcache client as a gocache store
-	bigcacheStore := store.NewBigcache(bigcacheClient, nil)
+	return cache.New(store.NewBigcache(bigcacheClient, nil)), nil
+}
 
-	// create single stage cache using bigcache
-	cacheManager := cache.New(bigcacheStore)
+// newRedisClient returns a client for the Redis-protocol server described by rds.
+func newRedisClient(rds Redis) *redis.Client {
+	return redis.NewClient(&redis.Options{
+		Addr:     rds.Addr,
+		Password: rds.Password,
+		DB:       rds.DB,
+	})
+}
 
-	return &Manager{
-		cache:      cacheManager,
-		entries:    map[string]*Entry{},
-		references: map[string]int{},
-	}, nil
+// Shared is true if the cache is shared with other instances through Redis.
+func (cm *Manager) Shared() bool {
+	return cm.redis != nil
 }
 
-// Clear removes all entries from the cache.
+// Clear removes all entries from the bigcache and the Redis database of the cache.
+// Both are cleared even if one fails; the first error is returned.
+//
+// Redis is flushed with FLUSHDB, because gocache's Clear runs FLUSHALL, which would
+// empty every database on the server.
+//
 func (cm *Manager) Clear(ctx context.Context) error {
-	return cm.cache.Clear(ctx)
+	var first error
+	if cm.local != nil {
+		first = cm.local.Clear(ctx)
+	}
+	if cm.redis != nil {
+		if err := cm.redis.FlushDB(ctx).Err(); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// Checker checks the shared cache can be reached.
+// An unshared cache is always healthy.
+func (cm *Manager) Checker(ctx context.Context, state *healthcheck.CheckState) error {
+	if cm.redis != nil {
+		if err := cm.redis.Ping(ctx).Err(); err != nil {
+			state.Update(healthcheck.StatusCritical, err.Error(), 0)
+			return nil
+		}
+	}
+	state.Update(healthcheck.StatusOK, "cache healthy", 0)
+	return nil
 }
 
 // AllocateEntry returns an object that may be locked to serialise
@@ -89,6 +206,7 @@ func (cm *Manager) AllocateEntry(key string) *Entry {
 		entry = &Entry{
 			key:     key,
 			manager: cm,
+			lock:    make(chan struct{}, 1),
 		}
 		cm.entries[key] = entry
 		cm.references[key] = 0
@@ -115,13 +233,46 @@ func (entry *Entry) Free() {
 	}
 }
 
+// Lock waits for the entry to be free and locks it.
+func (entry *Entry) Lock() {
+	entry.lock <- struct{}{}
+}
+
+// LockWithin locks the entry if it is free within d, and reports whether it did.
+// It lets a request give up waiting for another that is holding the entry for a long time,
+// such as while streaming to a slow client.
+//
+func (entry *Entry) LockWithin(d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case entry.lock <- struct{}{}:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
+
+// Unlock frees the entry for the next caller of Lock.
+func (entry *Entry) Unlock() {
+	<-entry.lock
+}
+
 // Get retrieves a value from the cache for key.
 func (entry *Entry) Get(ctx context.Context) ([]byte, error) {
 	v, err := entry.manager.cache.Get(ctx, entry.key)
 	if err != nil {
 		return nil, err
 	}
-	return v.([]byte), nil
+	// bigcache gives back []byte, but Redis gives back string
+	switch v := v.(type) {
+	case []byte:
+		return v, nil
+	case string:
+		return []byte(v), nil
+	default:
+		return nil, fmt.Errorf("unexpected %T value in cache for %s", v, entry.key)
+	}
 }
 
 // Set saves a new value in the cache for key.
@@ -8,7 +8,7 @@ import (
 )
 
 func Test_AllocateFree(t *testing.T) {
-	cm, err := New(5*time.Minute, 100)
+	cm, err := New(5*time.Minute, 100, 10)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -67,3 +67,19 @@ func Test_AllocateFree(t *testing.T) {
 	assert.Equal(t, len(cm.entries), 0, "must be no entries allocated")
 	assert.Equal(t, len(cm.references), 0, "must be no references allocated")
 }
+
+func Test_LockWithin(t *testing.T) {
+	cm, err := New(5*time.Minute, 100, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry := cm.AllocateEntry("some-key")
+	defer entry.Free()
+
+	assert.True(t, entry.LockWithin(time.Millisecond), "free entry must lock")
+	assert.False(t, entry.LockWithin(time.Millisecond), "locked entry must time out")
+
+	entry.Unlock()
+	assert.True(t, entry.LockWithin(time.Millisecond), "unlocked entry must lock again")
+	entry.Unlock()
+}
@@ -1,11 +1,19 @@
 package cache
 
-import "net/http"
+import (
+	"net/http"
+	"strings"
+)
 
-// cacheKey builds a cache key from an incoming HTTP request struct.
-// Right now it only looks at RequestURI, but it should probably
-// also look at certain headers to do with language, content-type,
-// encoding, etc.
-func CacheKey(req *http.Request) string {
-	return req.RequestURI
+// CacheKey builds a cache key from an incoming HTTP request struct and
+// the content type of the response that will be sent.
+// The content type is part of the key because the same RequestURI can
+// produce different bodies depending on the Accept header.
+// Any variants are also part of the key, for other headers that change the
+// body, such as the language negotiated from Accept-Language.
+func CacheKey(req *http.Request, contentType string, variant ...string) string {
+	if len(variant) == 0 {
+		return contentType + " " + req.RequestURI
+	}
+	return contentType + " " + strings.Join(variant, " ") + " " + req.RequestURI
 }
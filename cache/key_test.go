@@ -0,0 +1,30 @@
+package cache
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_CacheKey(t *testing.T) {
+	req := &http.Request{RequestURI: "/query2/2011?rows=E01000001&cols=QS101EW0001"}
+
+	csvKey := CacheKey(req, "text/csv")
+	jsonKey := CacheKey(req, "application/json")
+
+	assert.Equal(t, "text/csv /query2/2011?rows=E01000001&cols=QS101EW0001", csvKey)
+	assert.NotEqual(t, csvKey, jsonKey, "different formats must have different keys")
+	assert.Equal(t, csvKey, CacheKey(req, "text/csv"), "same request and format must have same key")
+}
+
+func Test_CacheKeyVariant(t *testing.T) {
+	req := &http.Request{RequestURI: "/metadata/2011"}
+
+	enKey := CacheKey(req, "application/json", "en")
+	cyKey := CacheKey(req, "application/json", "cy")
+
+	assert.Equal(t, "application/json en /metadata/2011", enKey)
+	assert.NotEqual(t, enKey, cyKey, "different variants must have different keys")
+	assert.NotEqual(t, CacheKey(req, "application/json"), enKey, "variant must be part of the key")
+}
@@ -0,0 +1,205 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ONSdigital/dp-healthcheck/healthcheck"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+// newRedisManager returns a Manager using storeType with an in-process Redis stand-in.
+func newRedisManager(t *testing.T, storeType string, mr *miniredis.Miniredis) *Manager {
+	cm, err := NewStore(storeType, 5*time.Minute, 5*time.Minute, 100, 1, Redis{Addr: mr.Addr()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cm
+}
+
+func Test_NewStoreUnknown(t *testing.T) {
+	_, err := NewStore("memcache", 5*time.Minute, 5*time.Minute, 100, 1, Redis{})
+	assert.Error(t, err)
+}
+
+func Test_NewStoreNoRedisAddr(t *testing.T) {
+	for _, storeType := range []string{StoreRedis, StoreChain} {
+		_, err := NewStore(storeType, 5*time.Minute, 5*time.Minute, 100, 1, Redis{})
+		assert.Error(t, err, storeType)
+	}
+}
+
+func Test_RedisStore(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+
+	// two instances sharing one Redis
+	cm1 := newRedisManager(t, StoreRedis, mr)
+	cm2 := newRedisManager(t, StoreRedis, mr)
+	assert.True(t, cm1.Shared())
+
+	entry1 := cm1.AllocateEntry("key")
+	defer entry1.Free()
+	entry2 := cm2.AllocateEntry("key")
+	defer entry2.Free()
+
+	// a value set by one instance can be got by the other, as []byte
+	assert.NoError(t, entry1.Set(ctx, []byte("value")))
+	got, err := entry2.Get(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("value"), got)
+
+	// entries expire after the ttl
+	mr.FastForward(6 * time.Minute)
+	_, err = entry2.Get(ctx)
+	assert.Error(t, err, "value must expire")
+
+	// clearing one instance clears the other
+	assert.NoError(t, entry1.Set(ctx, []byte("value")))
+	assert.NoError(t, cm2.Clear(ctx))
+	_, err = entry1.Get(ctx)
+	assert.Error(t, err, "value must be cleared")
+}
+
+func Test_ChainStore(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+
+	cm := newRedisManager(t, StoreChain, mr)
+	assert.True(t, cm.Shared())
+	assert.NotNil(t, cm.local)
+
+	entry := cm.AllocateEntry("key")
+	defer entry.Free()
+
+	// a value is saved in both tiers
+	assert.NoError(t, entry.Set(ctx, []byte("value")))
+	got, err := entry.Get(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("value"), got)
+
+	_, err = cm.local.Get(ctx, "key")
+	assert.NoError(t, err, "value must be in bigcache")
+	assert.True(t, mr.Exists("key"), "value must be in Redis")
+
+	// Clear empties both tiers
+	assert.NoError(t, cm.Clear(ctx))
+	_, err = cm.local.Get(ctx, "key")
+	assert.Error(t, err, "value must be cleared from bigcache")
+	assert.False(t, mr.Exists("key"), "value must be cleared from Redis")
+	_, err = entry.Get(ctx)
+	assert.Error(t, err)
+}
+
+func Test_ClearOnlyCacheDB(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+
+	// something else keeps a key in another database on the same server
+	mr.Select(1)
+	assert.NoError(t, mr.Set("other", "value"))
+	mr.Select(0)
+
+	cm := newRedisManager(t, StoreRedis, mr)
+	entry := cm.AllocateEntry("key")
+	defer entry.Free()
+	assert.NoError(t, entry.Set(ctx, []byte("value")))
+
+	assert.NoError(t, cm.Clear(ctx))
+	assert.False(t, mr.Exists("key"), "cache database must be flushed")
+	mr.Select(1)
+	assert.True(t, mr.Exists("other"), "other databases must survive Clear")
+}
+
+func Test_ChainStoreSharedValue(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+
+	// a value set by one instance is found in Redis by another
+	cm1 := newRedisManager(t, StoreChain, mr)
+	cm2 := newRedisManager(t, StoreChain, mr)
+
+	entry1 := cm1.AllocateEntry("key")
+	defer entry1.Free()
+	entry2 := cm2.AllocateEntry("key")
+	defer entry2.Free()
+
+	assert.NoError(t, entry1.Set(ctx, []byte("value")))
+	got, err := entry2.Get(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("value"), got)
+}
+
+func Test_ChainStoreLocalTTL(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+
+	cm1 := newRedisManager(t, StoreChain, mr)
+	cm2, err := NewStore(StoreChain, 5*time.Minute, time.Second, 100, 1, Redis{Addr: mr.Addr()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entry1 := cm1.AllocateEntry("key")
+	defer entry1.Free()
+	entry2 := cm2.AllocateEntry("key")
+	defer entry2.Free()
+
+	// the second instance copies the value into its bigcache
+	assert.NoError(t, entry1.Set(ctx, []byte("value")))
+	_, err = entry2.Get(ctx)
+	assert.NoError(t, err)
+
+	// after the first instance clears the cache, the copy only lasts localTTL
+	assert.NoError(t, cm1.Clear(ctx))
+	_, err = cm2.local.Get(ctx, "key")
+	assert.NoError(t, err, "copy kept until localTTL")
+	assert.Eventually(t, func() bool {
+		_, err := entry2.Get(ctx)
+		return err != nil
+	}, 5*time.Second, 100*time.Millisecond, "copy must expire after localTTL")
+}
+
+func Test_ClearReportsRedisError(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+
+	cm := newRedisManager(t, StoreChain, mr)
+	entry := cm.AllocateEntry("key")
+	defer entry.Free()
+	assert.NoError(t, entry.Set(ctx, []byte("value")))
+
+	// the bigcache tier is still cleared when Redis is down
+	mr.Close()
+	assert.Error(t, cm.Clear(ctx))
+	_, err := cm.local.Get(ctx, "key")
+	assert.Error(t, err, "bigcache tier must be cleared")
+}
+
+func Test_Checker(t *testing.T) {
+	ctx := context.Background()
+
+	// an unshared cache is always healthy
+	cm, err := New(5*time.Minute, 100, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.False(t, cm.Shared())
+	state := healthcheck.NewCheckState("cache")
+	assert.NoError(t, cm.Checker(ctx, state))
+	assert.Equal(t, healthcheck.StatusOK, state.Status())
+
+	// a shared cache is healthy while Redis is up
+	mr := miniredis.RunT(t)
+	cm = newRedisManager(t, StoreRedis, mr)
+	state = healthcheck.NewCheckState("cache")
+	assert.NoError(t, cm.Checker(ctx, state))
+	assert.Equal(t, healthcheck.StatusOK, state.Status())
+
+	mr.Close()
+	state = healthcheck.NewCheckState("cache")
+	assert.NoError(t, cm.Checker(ctx, state))
+	assert.Equal(t, healthcheck.StatusCritical, state.Status())
+}
@@ -0,0 +1,66 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"io"
+)
+
+// A Tee passes a response through to the client while keeping a copy to be cached.
+// It is used for responses that are streamed, so the whole body is never held by the caller.
+//
+// Once more than the Manager's maximum entry size has been written, the copy is dropped
+// and Save does nothing, so a very large response is streamed but not cached.
+type Tee struct {
+	entry    *Entry
+	w        io.Writer
+	buf      bytes.Buffer
+	overflow bool
+}
+
+// NewTee returns a Tee that writes to w and collects a copy for this entry's key.
+func (entry *Entry) NewTee(w io.Writer) *Tee {
+	return &Tee{
+		entry: entry,
+		w:     w,
+	}
+}
+
+// Write writes p to the underlying writer and adds it to the copy.
+func (t *Tee) Write(p []byte) (int, error) {
+	t.keep(p)
+	return t.w.Write(p)
+}
+
+// Prefix adds p to the copy without writing it to the underlying writer.
+// It lets callers cache data that goes with the response, such as its headers.
+// Call it before the first Write.
+func (t *Tee) Prefix(p []byte) {
+	t.keep(p)
+}
+
+// keep adds p to the copy, or drops the copy if it would grow too big.
+func (t *Tee) keep(p []byte) {
+	if t.overflow {
+		return
+	}
+	if t.buf.Len()+len(p) > t.entry.manager.maxEntry {
+		t.overflow = true
+		t.buf = bytes.Buffer{}
+		return
+	}
+	t.buf.Write(p)
+}
+
+// Overflowed is true if too much has been written to cache.
+func (t *Tee) Overflowed() bool {
+	return t.overflow
+}
+
+// Save stores the copy in the cache, unless it overflowed.
+func (t *Tee) Save(ctx context.Context) error {
+	if t.overflow {
+		return nil
+	}
+	return t.entry.Set(ctx, t.buf.Bytes())
+}
@@ -0,0 +1,62 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Tee(t *testing.T) {
+	ctx := context.Background()
+
+	cm, err := New(5*time.Minute, 100, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entry := cm.AllocateEntry("small")
+	defer entry.Free()
+
+	// a small response is passed through and cached
+	var client bytes.Buffer
+	tee := entry.NewTee(&client)
+	tee.Prefix([]byte("header\n"))
+	tee.Write([]byte("geography_code,QS101EW0001\n"))
+	tee.Write([]byte("E01000001,1465\n"))
+	assert.False(t, tee.Overflowed(), "small response must not overflow")
+	assert.NoError(t, tee.Save(ctx))
+
+	assert.Equal(t, "geography_code,QS101EW0001\nE01000001,1465\n", client.String(), "client must get full response")
+	cached, err := entry.Get(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "header\n"+client.String(), string(cached), "cached value must be prefix and response")
+}
+
+func Test_Tee_Overflow(t *testing.T) {
+	ctx := context.Background()
+
+	cm, err := New(5*time.Minute, 100, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entry := cm.AllocateEntry("big")
+	defer entry.Free()
+
+	// a response larger than the max entry size is passed through but not cached
+	var client bytes.Buffer
+	tee := entry.NewTee(&client)
+	chunk := bytes.Repeat([]byte("x"), 512*1024)
+	for i := 0; i < 3; i++ {
+		tee.Write(chunk)
+	}
+	assert.True(t, tee.Overflowed(), "big response must overflow")
+	assert.NoError(t, tee.Save(ctx))
+
+	assert.Equal(t, 3*len(chunk), client.Len(), "client must get full response")
+	_, err = entry.Get(ctx)
+	assert.Error(t, err, "big response must not be cached")
+}
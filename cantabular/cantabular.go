@@ -9,18 +9,31 @@ import (
 	"net/http"
 	"reflect"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/ONSdigital/dp-healthcheck/healthcheck"
 	"github.com/shurcooL/graphql"
+	"gorm.io/gorm"
 )
 
 const URL = "https://ftb-api-ext.ons.sensiblecode.io/graphql"
 
 //const URL = "http://127.0.0.1:8080"
 
+// DefaultDataSet is the dataset queried when none is given.
+const DefaultDataSet = "Usual-Residents"
+
 type Client struct {
-	url    string // graphql server we are querying
-	client *graphql.Client
+	url     string // graphql server we are querying
+	client  *graphql.Client
+	gdb     *gorm.DB // holds the Mapping; see SetMappingDB
+	mapping *Mapping // fixed Mapping used instead of gdb; see SetMapping
+	years   []int    // census years served; see SetYears
+
+	mu       sync.Mutex // protects loaded and loadedAt
+	loaded   *Mapping   // Mapping last read from gdb
+	loadedAt time.Time  // when loaded was read
 }
 
 type AuthTripper struct {
@@ -115,6 +128,21 @@ func New(url, user, pass string) *Client {
 	}
 }
 
+// SetYears sets the census years whose data the client serves.
+func (cant *Client) SetYears(years []int) {
+	cant.years = years
+}
+
+// Serves returns true if the client serves data for census year.
+func (cant *Client) Serves(year int) bool {
+	for _, y := range cant.years {
+		if y == year {
+			return true
+		}
+	}
+	return false
+}
+
 /*
 { dataset(name: "Usual-Residents") {
     table(
@@ -136,12 +164,30 @@ func New(url, user, pass string) *Client {
 // MetricFilter is a cli type query1
 // could be entrypoint for REST endpoint
 func (cant *Client) QueryMetricFilter(ctx context.Context, ds, geo, geoType, code string) (geoq, catsQL Pairs, values IntValues, err error) {
-	geos := strings.Split(geo, ",")
+	mapping, err := cant.Mapping(ctx)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	v, err := mapping.Table(code)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	geoVar, err := mapping.Geotype(geoType)
+	if err != nil {
+		return nil, nil, nil, err
+	}
 
-	if ds == "" {
-		ds = GetDataSet(code)
+	if ds != "" {
+		v.Dataset = ds
 	}
 
+	return cant.QueryVariableFilter(ctx, v, geoVar, strings.Split(geo, ","))
+}
+
+// QueryVariableFilter queries variable v for the geographies geos of geography variable geoVar.
+// Unlike QueryMetricFilter, the Cantabular names are given directly, so no Mapping is read.
+//
+func (cant *Client) QueryVariableFilter(ctx context.Context, v Variable, geoVar string, geos []string) (geoq, catsQL Pairs, values IntValues, err error) {
 	// 2011 cantabular geocodes have syn appended to the front
 	var geosQL []graphql.String
 	for _, v := range geos {
@@ -151,10 +197,10 @@ func (cant *Client) QueryMetricFilter(ctx context.Context, ds, geo, geoType, cod
 	var query MetricFilter
 
 	vars := map[string]interface{}{
-		"ds":      graphql.String(ds),
+		"ds":      graphql.String(v.Dataset),
 		"geos":    geosQL,
-		"geotype": graphql.String(GeoTypeMap()[geoType]),
-		"var":     graphql.String(ShortVarMap()[code]),
+		"geotype": graphql.String(geoVar),
+		"var":     graphql.String(v.Variable),
 	}
 
 	if err = cant.SendQueryVars(ctx, &query, vars); err != nil {
@@ -188,14 +234,27 @@ func (cant *Client) QueryMetricFilter(ctx context.Context, ds, geo, geoType, cod
 // QueryMetric is is a cli query2
 // could be entrypoint for REST endpoint
 func (cant *Client) QueryMetric(ctx context.Context, ds, geoType, code string) (geoq, catsQL Pairs, values IntValues, err error) {
-	if ds == "" {
-		ds = GetDataSet(code)
+	mapping, err := cant.Mapping(ctx)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	v, err := mapping.Table(code)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	geoVar, err := mapping.Geotype(geoType)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if ds != "" {
+		v.Dataset = ds
 	}
 
 	vars := map[string]interface{}{
-		"ds":      graphql.String(ds),
-		"geotype": graphql.String(GeoTypeMap()[geoType]),
-		"var":     graphql.String(ShortVarMap()[code]),
+		"ds":      graphql.String(v.Dataset),
+		"geotype": graphql.String(geoVar),
+		"var":     graphql.String(v.Variable),
 	}
 
 	var query Metric
@@ -210,72 +269,61 @@ func (cant *Client) QueryMetric(ctx context.Context, ds, geoType, code string) (
 	return geoq, catsQL, values, nil
 }
 
-// QueryMetaData does some multiple data queries to get data structure
+// QueryMetaData describes the census tables mapped to variables in ds, ordered by table, with
+// categories in Cantabular's order.
+// Codes are the census table codes if nomis is true, otherwise the Cantabular variable names.
 // XXX a poor work around for a lack of metadata.
-
+//
 func (cant *Client) QueryMetaData(ctx context.Context, ds string, nomis bool) (string, error) {
 	if ds == "" {
-		ds = GetDataSet("")
+		ds = DefaultDataSet
 	}
 
-	revMap := make(map[string]string)
-	for k, v := range ShortVarMap() {
-		revMap[v] = k
-
-	}
-
-	var query VariableCodes
-	vars := map[string]interface{}{
-		"ds": graphql.String(ds),
-	}
-	if err := cant.SendQueryVars(ctx, &query, vars); err != nil {
+	mapping, err := cant.Mapping(ctx)
+	if err != nil {
 		return "", err
 	}
 
-	var metadata []Metadata
-
-	for _, v := range query.Dataset.Variables.Edges {
-		if revMap[string(v.Node.Name)] == "" {
+	// one entry per variable, even if several tables are mapped to it
+	var codes []string
+	var vars []Variable
+	seen := map[Variable]bool{}
+	for _, code := range mapping.TableCodes() {
+		v := mapping.Tables[code]
+		if v.Dataset != ds || seen[v] {
 			continue
 		}
+		seen[v] = true
+		codes = append(codes, code)
+		vars = append(vars, v)
+	}
 
-		var name string
-		if nomis {
-			name = revMap[string(v.Node.Name)]
-		} else {
-			name = string(v.Node.Name)
+	infos, err := cant.Describe(ctx, vars)
+	if err != nil {
+		return "", err
+	}
 
-		}
+	metadata := []Metadata{}
+	for i, v := range vars {
 		md := Metadata{
-			Code: name,
-			//Code: string(v.Node.Name),
-			Name: string(v.Node.Label),
+			Code: v.Variable,
+			Name: infos[v].Label,
 		}
-		var query2 ClassCodes
-		vars := map[string]interface{}{
-			"ds":   graphql.String(ds),
-			"vars": graphql.String(v.Node.Name),
-		}
-		if err := cant.SendQueryVars(ctx, &query2, vars); err != nil {
-			return "", err
+		if nomis {
+			md.Code = codes[i]
 		}
-		for _, v2 := range query2.Dataset.Table.Dimensions {
-			for _, v3 := range v2.Categories { // XXX not ordered!
-				md.Categories = append(md.Categories, struct {
-					Code string
-					Name string
-				}{Code: string(v3.Code), Name: string(v3.Label)})
-
-			}
+		for _, cat := range infos[v].Categories {
+			md.Categories = append(md.Categories, struct {
+				Code string
+				Name string
+			}{Code: string(cat.Code), Name: string(cat.Label)})
 		}
-
 		metadata = append(metadata, md)
-
 	}
 
 	bs, err := json.Marshal(metadata)
 	if err != nil {
-		return "", nil
+		return "", err
 	}
 
 	return string(bs), nil
@@ -350,69 +398,30 @@ func ParseMetric(geo, cats Pairs, values IntValues) string {
 	return b.String()
 }
 
-func GeoTypeMap() map[string]string {
-
-	return map[string]string{
-		"Country": "Country",
-		"Region":  "Region",
-		"LAD":     "LA",
-		"MSOA":    "MSOA",
-	}
+// CategoryCode returns the NOMIS style long code standing for the i'th category (from 0)
+// of the variable behind census table code.
+// The codes follow the NOMIS layout, where 0001 is the table total, so Cantabular's
+// first category is 0002, eg QS501EW0002.
+//
+func CategoryCode(code string, i int) string {
+	return fmt.Sprintf("%s%04d", code, i+2)
 }
 
-func ShortVarMap() map[string]string {
-	// "matching" via command output and eg.
-	// SELECT  nd.name,nc.* FROM nomis_desc nd, nomis_category nc where nd.short_nomis_code='KS103EW' and nd.id=nc.nomis_desc_id and nc.measurement_unit='Count' and nc.long_nomis_code not like '%0001';
-
-	// these are syn2011 "keys" which we pretend, temporarily, are NOMIS short codes
-	return map[string]string{
-		"KS102EW": "AGE_T009A",
-		"KS103EW": "MARSTAT_T006A",
-		"KS202EW": "NATID_ALL_T009A",
-		"KS206EW": "WELSHPUK112_T007A",
-		"KS207WA": "WELSHPUK112_R003A",
-		"KS208WA": "WELSHPUK112_R003A",
-		"QS101EW": "RESIDTYPE",
-		"QS104EW": "SEX",
-		"QS201EW": "ETHPUK11_T009A",
-		"QS203EW": "COB_R010A",
-		"QS208EW": "RELIGIONEW",
-		"QS301EW": "CARER",
-		"QS302EW": "HEALTH_T004A", // HEALTH
-		"QS303EW": "DISABILITY_T003B",
-		"QS402EW": "TYPACCOM_T009A",
-		"QS403EW": "TENHUK11_T010A",
-		"QS406EW": "SIZHUK11_T007A",
-		"QS415EW": "CENHEATHUK11_T003A",
-		"QS416EW": "CARSNO_T004A",
-		"QS501EW": "HLQPUK11_T007A", // EDUCATION
-		"QS601EW": "ECOPUK11_R006A",
-		"QS604EW": "HOURS",
-		"QS605EW": "INDGPUK11_T009A",
-		"QS606EW": "OCCPUK113_T010A",
-		"QS701EW": "TRANSPORT_R005A",
-		"QS702EW": "AGGDTWPEW11_R010A",
-	}
-
-}
-
-//  GetDataSet maps variable code to dataset
-func GetDataSet(varCode string) string {
-
-	mappy := map[string]string{
-		"QS403EW": "People-Households",
-		"QS406EW": "People-Households",
-		"KS206EW": "People-Households",
-		"QS402EW": "People-Households",
-		"QS416EW": "People-Households",
-		"QS415EW": "People-Households",
-	}
-
-	if mappy[varCode] != "" {
-		return mappy[varCode]
+// CategoryCodes returns the CategoryCode of each of cats, the categories of the variable
+// behind census table code in Cantabular's order.
+// The NotApplicable category is not counted by NOMIS, so it is not numbered and its code is "".
+//
+func CategoryCodes(code string, cats Pairs) []string {
+	codes := make([]string, len(cats))
+	var i int
+	for j, cat := range cats {
+		if string(cat.Code) == NotApplicable {
+			continue
+		}
+		codes[j] = CategoryCode(code, i)
+		i++
 	}
-
-	return "Usual-Residents"
+	return codes
 }
 
 func (cant *Client) Checker(ctx context.Context, state *healthcheck.CheckState) error {
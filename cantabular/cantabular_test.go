@@ -10,6 +10,10 @@ import (
 	"os"
 	"strings"
 	"testing"
+
+	"github.com/ONSdigital/dp-find-insights-poc-api/pkg/database"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
 )
 
 var cant *Client
@@ -19,6 +23,13 @@ func init() {
 	log.SetFlags(log.LstdFlags | log.Lshortfile)
 	cant = New(URL, os.Getenv("CANT_USER"), os.Getenv("CANT_PW"))
 	ctx = context.Background()
+
+	// the census table and geotype mapping comes from the db
+	gdb, err := gorm.Open(postgres.Open(database.GetDSN()), &gorm.Config{})
+	if err != nil {
+		log.Fatal(err)
+	}
+	cant.SetMappingDB(gdb)
 }
 
 func TestQueryMetricFilters(t *testing.T) { // QUERY 1
@@ -99,7 +110,12 @@ func TestSpecificMetricsOtherDS(t *testing.T) { // QUERY 2
 
 // query all codes as a crude benchmark
 func TestRespFilterMetrics(t *testing.T) {
-	for code := range ShortVarMap() {
+	mapping, err := cant.Mapping(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, code := range mapping.TableCodes() {
 
 		geoq, catsq, values, err := cant.QueryMetricFilter(ctx, "", "E92000001", "Country", code)
 		if err != nil {
@@ -0,0 +1,143 @@
+package cantabular
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ONSdigital/dp-find-insights-poc-api/cantabular/fake"
+	"github.com/ONSdigital/dp-healthcheck/healthcheck"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeClient returns a Client querying a fake Cantabular, with a fixed Mapping for the fixtures.
+func fakeClient(t *testing.T) (*Client, *httptest.Server) {
+	srv, err := fake.NewServer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cant := New(srv.URL, "", "")
+	cant.SetMapping(&Mapping{
+		Tables: map[string]Variable{
+			"QS104EW": {Dataset: "Usual-Residents", Variable: "SEX"},
+			"QS301EW": {Dataset: "Usual-Residents", Variable: "CARER"},
+			"QS406EW": {Dataset: "People-Households", Variable: "SIZHUK11_T007A"},
+			"QS416EW": {Dataset: "People-Households", Variable: "CARSNO_T004A"},
+			"QS501EW": {Dataset: "Usual-Residents", Variable: "HLQPUK11_T007A"},
+		},
+		Geotypes: map[string]string{
+			"Country": "Country",
+			"Region":  "Region",
+			"LAD":     "LA",
+			"MSOA":    "MSOA",
+		},
+	})
+	return cant, srv
+}
+
+func TestFakeQueryMetricFilter(t *testing.T) {
+	cant, srv := fakeClient(t)
+	defer srv.Close()
+
+	geoq, catsq, values, err := cant.QueryMetricFilter(context.Background(), "", "E92000001,W92000004", "Country", "QS501EW")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "cantabular,No qualifications,Level 1 qualifications,Level 2 qualifications,Level 3 qualifications,Level 4 qualifications and above,Apprenticeships and other qualifications,Not applicable\ngeography_code,10,11,12,14,15,\"13,16\",-9\nE92000001,9539010,5680447,6571675,5306778,12117875,3952125,9710552\nW92000004,658065,327844,390605,304275,633755,201280,544600\n"
+	assert.Equal(t, want, ParseMetric(geoq, catsq, values))
+}
+
+func TestFakeQueryMetricFilterSubset(t *testing.T) {
+	cant, srv := fakeClient(t)
+	defer srv.Close()
+
+	// filtered geographies come back in Cantabular's order, not the order asked for
+	geoq, catsq, values, err := cant.QueryMetricFilter(context.Background(), "", "W06000001,E06000001", "LAD", "QS104EW")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "cantabular,Male,Female\ngeography_code,1,2\nE06000001,44838,47190\nW06000001,34236,35515\n"
+	assert.Equal(t, want, ParseMetric(geoq, catsq, values))
+}
+
+func TestFakeQueryMetricFilterUnknownGeocode(t *testing.T) {
+	cant, srv := fakeClient(t)
+	defer srv.Close()
+
+	_, _, _, err := cant.QueryMetricFilter(context.Background(), "", "E06000999", "LAD", "QS104EW")
+	assert.Error(t, err)
+}
+
+func TestFakeQueryMetric(t *testing.T) {
+	cant, srv := fakeClient(t)
+	defer srv.Close()
+
+	geoq, catsq, values, err := cant.QueryMetric(context.Background(), "", "Region", "QS416EW")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "cantabular,No cars or vans in household,1 car or van in household,2 or more cars or vans in household,Not applicable\ngeography_code,0,1,2-4,-9\nE12000001,620632,1005179,949070,49018\nE12000002,1456726,2688158,2860409,131208\nE12000003,1061663,2057408,2116106,108899\nE12000004,704466,1640205,2146475,98765\nE12000005,995417,2055221,2510158,102261\nE12000006,705831,2124298,2991478,111561\nE12000007,2734412,3428989,1986363,120091\nE12000008,1042660,3029708,4498032,207868\nE12000009,646812,1925768,2672339,125092\nW92000004,495272,1114274,1433202,59263\n"
+	assert.Equal(t, want, ParseMetric(geoq, catsq, values))
+}
+
+func TestFakeQueryMetricNoFixture(t *testing.T) {
+	cant, srv := fakeClient(t)
+	defer srv.Close()
+
+	_, _, _, err := cant.QueryMetric(context.Background(), "", "Country", "QS104EW")
+	assert.Error(t, err)
+}
+
+func TestFakeQueryMetaData(t *testing.T) {
+	cant, srv := fakeClient(t)
+	defer srv.Close()
+
+	got, err := cant.QueryMetaData(context.Background(), "People-Households", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `[{"Code":"QS406EW","Name":"Household size (7 categories)","categories":[{"Code":"1","Name":"1 person in household"},{"Code":"2","Name":"2 people in household"},{"Code":"3","Name":"3 people in household"},{"Code":"4","Name":"4 people in household"},{"Code":"5","Name":"5 people in household"},{"Code":"6-9","Name":"6 or more people in household"},{"Code":"-9","Name":"Not applicable"}]},{"Code":"QS416EW","Name":"Number of cars or vans in household (4 categories)","categories":[{"Code":"0","Name":"No cars or vans in household"},{"Code":"1","Name":"1 car or van in household"},{"Code":"2-4","Name":"2 or more cars or vans in household"},{"Code":"-9","Name":"Not applicable"}]}]`
+	assert.Equal(t, want, got)
+}
+
+func TestFakeChecker(t *testing.T) {
+	cant, srv := fakeClient(t)
+
+	state := healthcheck.NewCheckState("cantabular")
+	if err := cant.Checker(context.Background(), state); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, healthcheck.StatusOK, state.Status())
+
+	srv.Close()
+
+	state = healthcheck.NewCheckState("cantabular")
+	if err := cant.Checker(context.Background(), state); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, healthcheck.StatusCritical, state.Status())
+}
+
+func TestServes(t *testing.T) {
+	cant := New("", "", "")
+	assert.False(t, cant.Serves(2021), "no years until SetYears")
+
+	cant.SetYears([]int{2021, 2031})
+	assert.True(t, cant.Serves(2021))
+	assert.True(t, cant.Serves(2031))
+	assert.False(t, cant.Serves(2011))
+}
+
+func TestCategoryCodes(t *testing.T) {
+	cats := Pairs{
+		{Code: "1", Label: "Welsh"},
+		{Code: NotApplicable, Label: "Not applicable"},
+		{Code: "2", Label: "Not Welsh"},
+	}
+	assert.Equal(t, []string{"KS207WA0002", "", "KS207WA0003"}, CategoryCodes("KS207WA", cats))
+}
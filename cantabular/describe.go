@@ -0,0 +1,96 @@
+package cantabular
+
+import (
+	"context"
+	"sync"
+
+	"github.com/shurcooL/graphql"
+)
+
+// MaxConcurrentQueries bounds the Cantabular queries Describe makes at once.
+const MaxConcurrentQueries = 4
+
+// VariableInfo is a Cantabular variable's description and its categories.
+// Categories are in Cantabular's order, which is the order CategoryCode numbers them.
+type VariableInfo struct {
+	Label      string
+	Categories Pairs
+}
+
+// Describe returns the label and categories of each of vars.
+// Labels take a query per dataset and categories a query per variable, so the queries
+// are made concurrently, at most MaxConcurrentQueries at once.
+//
+func (cant *Client) Describe(ctx context.Context, vars []Variable) (map[Variable]VariableInfo, error) {
+	var mu sync.Mutex
+	labels := map[Variable]string{}
+	cats := map[Variable]Pairs{}
+
+	var jobs []func() error
+	datasets := map[string]bool{}
+	for _, v := range vars {
+		v := v
+		if !datasets[v.Dataset] {
+			datasets[v.Dataset] = true
+			jobs = append(jobs, func() error {
+				var query VariableCodes
+				if err := cant.SendQueryVars(ctx, &query, map[string]interface{}{"ds": graphql.String(v.Dataset)}); err != nil {
+					return err
+				}
+				mu.Lock()
+				defer mu.Unlock()
+				for _, edge := range query.Dataset.Variables.Edges {
+					labels[Variable{Dataset: v.Dataset, Variable: string(edge.Node.Name)}] = string(edge.Node.Label)
+				}
+				return nil
+			})
+		}
+		jobs = append(jobs, func() error {
+			c, err := cant.categories(ctx, v)
+			if err != nil {
+				return err
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			cats[v] = c
+			return nil
+		})
+	}
+
+	if err := runBounded(jobs, MaxConcurrentQueries); err != nil {
+		return nil, err
+	}
+
+	infos := map[Variable]VariableInfo{}
+	for _, v := range vars {
+		infos[v] = VariableInfo{Label: labels[v], Categories: cats[v]}
+	}
+	return infos, nil
+}
+
+// runBounded runs jobs concurrently, at most max at once, and returns the first error.
+func runBounded(jobs []func() error, max int) error {
+	sem := make(chan struct{}, max)
+	errs := make(chan error, len(jobs))
+
+	wg := new(sync.WaitGroup)
+	for _, job := range jobs {
+		job := job
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			errs <- job()
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
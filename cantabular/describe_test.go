@@ -0,0 +1,83 @@
+package cantabular
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFakeDescribe(t *testing.T) {
+	cant, srv := fakeClient(t)
+	defer srv.Close()
+
+	sex := Variable{Dataset: "Usual-Residents", Variable: "SEX"}
+	cars := Variable{Dataset: "People-Households", Variable: "CARSNO_T004A"}
+
+	infos, err := cant.Describe(context.Background(), []Variable{sex, cars})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "Sex", infos[sex].Label)
+	assert.Equal(t, Pairs{{Code: "1", Label: "Male"}, {Code: "2", Label: "Female"}}, infos[sex].Categories)
+
+	assert.Equal(t, "Number of cars or vans in household (4 categories)", infos[cars].Label)
+	var codes []string
+	for _, cat := range infos[cars].Categories {
+		codes = append(codes, string(cat.Code))
+	}
+	assert.Equal(t, []string{"0", "1", "2-4", "-9"}, codes)
+}
+
+func TestFakeDescribeUnknownVariable(t *testing.T) {
+	cant, srv := fakeClient(t)
+	defer srv.Close()
+
+	_, err := cant.Describe(context.Background(), []Variable{{Dataset: "Usual-Residents", Variable: "NOPE"}})
+	assert.Error(t, err)
+}
+
+func TestRunBounded(t *testing.T) {
+	const max = 3
+
+	var mu sync.Mutex
+	var running, peak, done int
+
+	var jobs []func() error
+	for i := 0; i < 20; i++ {
+		jobs = append(jobs, func() error {
+			mu.Lock()
+			running++
+			if running > peak {
+				peak = running
+			}
+			mu.Unlock()
+
+			mu.Lock()
+			running--
+			done++
+			mu.Unlock()
+			return nil
+		})
+	}
+
+	if err := runBounded(jobs, max); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 20, done)
+	assert.LessOrEqual(t, peak, max)
+}
+
+func TestRunBoundedError(t *testing.T) {
+	errBoom := errors.New("boom")
+	jobs := []func() error{
+		func() error { return nil },
+		func() error { return errBoom },
+		func() error { return nil },
+	}
+
+	assert.ErrorIs(t, runBounded(jobs, 2), errBoom)
+}
@@ -0,0 +1,265 @@
+package cantabular
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/ONSdigital/dp-find-insights-poc-api/model"
+	"github.com/shurcooL/graphql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// MinScore is the lowest score a variable needs to be a candidate for a census table.
+const MinScore = 0.5
+
+// NotApplicable is the code Cantabular uses for its "Not applicable" category,
+// which has no NOMIS equivalent.
+const NotApplicable = "-9"
+
+// geoSample is how many of a geography variable's categories are looked up in geo
+// to find its geotype.
+const geoSample = 10
+
+// Candidate is a Cantabular variable that might stand in for a census table.
+type Candidate struct {
+	ShortNomisCode string
+	Variable
+	Label string  // Cantabular's description of the variable
+	Score float64 // see Score
+}
+
+// GeotypeCandidate is a Cantabular variable whose categories are geographies of Geotype.
+type GeotypeCandidate struct {
+	Geotype string
+	Variable
+}
+
+// Discover suggests mappings by comparing the variables of datasets with the census tables
+// and geographies in the database.
+// A variable whose category codes are all "syn" prefixed geocodes is a geography variable,
+// and is matched to the geotype of those geocodes.
+// Other variables are scored against every census table, and the best scoring variable
+// of at least MinScore is the candidate for each table.
+//
+// Discover makes a Cantabular query for every variable, so it is slow.
+//
+func (cant *Client) Discover(ctx context.Context, gdb *gorm.DB, datasets []string) ([]Candidate, []GeotypeCandidate, error) {
+	var descs []model.NomisDesc
+	err := gdb.WithContext(ctx).Preload("NomisCategories").Find(&descs, "year = ?", 2011).Error
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var candidates []Candidate
+	var geotypes []GeotypeCandidate
+	for _, ds := range datasets {
+		var query VariableCodes
+		if err := cant.SendQueryVars(ctx, &query, map[string]interface{}{"ds": graphql.String(ds)}); err != nil {
+			return nil, nil, err
+		}
+
+		for _, edge := range query.Dataset.Variables.Edges {
+			v := Variable{Dataset: ds, Variable: string(edge.Node.Name)}
+
+			cats, err := cant.categories(ctx, v)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			if geocodes, ok := geographyCodes(cats); ok {
+				geotype, err := geotypeOf(ctx, gdb, geocodes)
+				if err != nil {
+					return nil, nil, err
+				}
+				if geotype != "" {
+					geotypes = append(geotypes, GeotypeCandidate{Geotype: geotype, Variable: v})
+				}
+				continue
+			}
+
+			for _, desc := range descs {
+				var names []string
+				for _, cat := range desc.NomisCategories {
+					names = append(names, cat.CategoryName)
+				}
+				score := Score(names, cats)
+				if score >= MinScore {
+					candidates = append(candidates, Candidate{
+						ShortNomisCode: desc.ShortNomisCode,
+						Variable:       v,
+						Label:          string(edge.Node.Label),
+						Score:          score,
+					})
+				}
+			}
+		}
+	}
+
+	return BestCandidates(candidates), firstGeotypes(geotypes), nil
+}
+
+// WriteMapping stores candidates and geotypes in the mapping tables, replacing any
+// existing mappings for the same census tables and geotypes.
+//
+func WriteMapping(ctx context.Context, gdb *gorm.DB, candidates []Candidate, geotypes []GeotypeCandidate) error {
+	return gdb.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, c := range candidates {
+			row := model.CantVariable{
+				ShortNomisCode: c.ShortNomisCode,
+				Dataset:        c.Dataset,
+				Variable:       c.Variable.Variable,
+			}
+			err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "short_nomis_code"}},
+				DoUpdates: clause.AssignmentColumns([]string{"dataset", "variable"}),
+			}).Create(&row).Error
+			if err != nil {
+				return err
+			}
+		}
+		for _, g := range geotypes {
+			row := model.CantGeotype{
+				Geotype:  g.Geotype,
+				Variable: g.Variable.Variable,
+			}
+			err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "geotype"}},
+				DoUpdates: clause.AssignmentColumns([]string{"variable"}),
+			}).Create(&row).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// categories returns the categories of variable v.
+func (cant *Client) categories(ctx context.Context, v Variable) (Pairs, error) {
+	var query ClassCodes
+	vars := map[string]interface{}{
+		"ds":   graphql.String(v.Dataset),
+		"vars": graphql.String(v.Variable),
+	}
+	if err := cant.SendQueryVars(ctx, &query, vars); err != nil {
+		return nil, err
+	}
+
+	var cats Pairs
+	for _, dim := range query.Dataset.Table.Dimensions {
+		cats = append(cats, dim.Categories...)
+	}
+	return cats, nil
+}
+
+// geotypeOf returns the geotype of a sample of geocodes, or "" if they are not
+// all of a single geotype in geo.
+func geotypeOf(ctx context.Context, gdb *gorm.DB, geocodes []string) (string, error) {
+	if len(geocodes) > geoSample {
+		geocodes = geocodes[:geoSample]
+	}
+
+	var names []string
+	err := gdb.WithContext(ctx).Raw(`
+SELECT DISTINCT geo_type.name
+FROM geo, geo_type
+WHERE geo_type.id = geo.type_id
+AND geo.code IN ?
+`, geocodes).Scan(&names).Error
+	if err != nil {
+		return "", err
+	}
+	if len(names) != 1 {
+		return "", nil
+	}
+	return names[0], nil
+}
+
+// geographyCodes returns the geocodes of cats if every category is a "syn" prefixed geocode.
+func geographyCodes(cats Pairs) ([]string, bool) {
+	if len(cats) == 0 {
+		return nil, false
+	}
+
+	geocodes := make([]string, 0, len(cats))
+	for _, cat := range cats {
+		code := string(cat.Code)
+		if !strings.HasPrefix(code, "syn") {
+			return nil, false
+		}
+		geocodes = append(geocodes, strings.TrimPrefix(code, "syn"))
+	}
+	return geocodes, true
+}
+
+// Score returns the fraction of a variable's categories whose labels match one of a census
+// table's category names, ignoring case and spacing.
+// Cantabular's "Not applicable" category has no NOMIS equivalent, so is not counted.
+//
+func Score(names []string, cats Pairs) float64 {
+	known := map[string]bool{}
+	for _, name := range names {
+		known[normaliseLabel(name)] = true
+	}
+
+	var total, matched int
+	for _, cat := range cats {
+		if string(cat.Code) == NotApplicable {
+			continue
+		}
+		total++
+		if known[normaliseLabel(string(cat.Label))] {
+			matched++
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return float64(matched) / float64(total)
+}
+
+// BestCandidates returns the best scoring candidate for each census table, ordered by table.
+// Ties go to the first candidate found.
+func BestCandidates(candidates []Candidate) []Candidate {
+	best := map[string]Candidate{}
+	var codes []string
+	for _, c := range candidates {
+		b, ok := best[c.ShortNomisCode]
+		if !ok {
+			codes = append(codes, c.ShortNomisCode)
+		}
+		if !ok || c.Score > b.Score {
+			best[c.ShortNomisCode] = c
+		}
+	}
+	sort.Strings(codes)
+
+	result := make([]Candidate, 0, len(codes))
+	for _, code := range codes {
+		result = append(result, best[code])
+	}
+	return result
+}
+
+// firstGeotypes returns the first variable found for each geotype, ordered by geotype.
+func firstGeotypes(geotypes []GeotypeCandidate) []GeotypeCandidate {
+	seen := map[string]bool{}
+	var result []GeotypeCandidate
+	for _, g := range geotypes {
+		if !seen[g.Geotype] {
+			seen[g.Geotype] = true
+			result = append(result, g)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Geotype < result[j].Geotype
+	})
+	return result
+}
+
+// normaliseLabel lower cases s and collapses its white space.
+func normaliseLabel(s string) string {
+	return strings.Join(strings.Fields(strings.ToLower(s)), " ")
+}
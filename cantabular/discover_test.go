@@ -0,0 +1,73 @@
+package cantabular
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScore(t *testing.T) {
+	names := []string{
+		"All categories: Sex",
+		"Males",
+		"Females",
+	}
+
+	var tests = map[string]struct {
+		cats Pairs
+		want float64
+	}{
+		"all match": {
+			cats: Pairs{{Code: "1", Label: "Males"}, {Code: "2", Label: "Females"}},
+			want: 1,
+		},
+		"case and spacing ignored": {
+			cats: Pairs{{Code: "1", Label: "males"}, {Code: "2", Label: " Females  "}},
+			want: 1,
+		},
+		"not applicable ignored": {
+			cats: Pairs{{Code: "1", Label: "Males"}, {Code: "2", Label: "Females"}, {Code: "-9", Label: "Not applicable"}},
+			want: 1,
+		},
+		"half match": {
+			cats: Pairs{{Code: "1", Label: "Males"}, {Code: "2", Label: "Other"}},
+			want: 0.5,
+		},
+		"no categories": {
+			cats: Pairs{{Code: "-9", Label: "Not applicable"}},
+			want: 0,
+		},
+	}
+
+	for name, test := range tests {
+		assert.Equal(t, test.want, Score(names, test.cats), name)
+	}
+}
+
+func TestBestCandidates(t *testing.T) {
+	candidates := []Candidate{
+		{ShortNomisCode: "QS104EW", Variable: Variable{Dataset: "Usual-Residents", Variable: "SEX"}, Score: 1},
+		{ShortNomisCode: "QS101EW", Variable: Variable{Dataset: "Usual-Residents", Variable: "RESIDTYPE"}, Score: 0.5},
+		{ShortNomisCode: "QS104EW", Variable: Variable{Dataset: "People-Households", Variable: "SEX"}, Score: 1},
+		{ShortNomisCode: "QS101EW", Variable: Variable{Dataset: "Usual-Residents", Variable: "RESIDTYPE_T003A"}, Score: 0.75},
+	}
+
+	want := []Candidate{
+		{ShortNomisCode: "QS101EW", Variable: Variable{Dataset: "Usual-Residents", Variable: "RESIDTYPE_T003A"}, Score: 0.75},
+		{ShortNomisCode: "QS104EW", Variable: Variable{Dataset: "Usual-Residents", Variable: "SEX"}, Score: 1},
+	}
+
+	assert.Equal(t, want, BestCandidates(candidates))
+}
+
+func TestGeographyCodes(t *testing.T) {
+	geocodes, ok := geographyCodes(Pairs{{Code: "synE92000001"}, {Code: "synW92000004"}})
+	assert.True(t, ok)
+	assert.Equal(t, []string{"E92000001", "W92000004"}, geocodes)
+
+	_, ok = geographyCodes(Pairs{{Code: "synE92000001"}, {Code: "-9"}})
+	assert.False(t, ok)
+
+	_, ok = geographyCodes(nil)
+	assert.False(t, ok)
+}
@@ -0,0 +1,358 @@
+// Package fake is a stand-in for the Cantabular GraphQL API, so the cantabular client
+// can be tested without the real server.
+//
+// It answers the dataset, table and variables queries cantabular.Client sends, from
+// fixture files describing each dataset's variables and tables.
+// It only understands the shapes of query the client makes; it is not a GraphQL server.
+//
+package fake
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// fixtures holds the fixtures used by NewServer, one file per dataset.
+//go:embed fixtures/*.json
+var fixtures embed.FS
+
+// Dataset is the fixture for one Cantabular dataset.
+type Dataset struct {
+	Name      string     `json:"name"`
+	Variables []Variable `json:"variables"`
+	Tables    []Table    `json:"tables"`
+}
+
+// Variable is a Cantabular variable and its categories, in Cantabular's order.
+type Variable struct {
+	Name       string     `json:"name"`
+	Label      string     `json:"label"`
+	Categories []Category `json:"categories"`
+}
+
+// Category is one category of a Variable.
+type Category struct {
+	Code  string `json:"code"`
+	Label string `json:"label"`
+}
+
+// Table holds the counts for a combination of variables.
+// Values are in row-major order: the last variable's categories vary fastest.
+type Table struct {
+	Variables []string `json:"variables"`
+	Values    []int    `json:"values"`
+}
+
+// Handler answers Cantabular GraphQL queries from datasets.
+type Handler struct {
+	datasets map[string]*Dataset
+}
+
+var (
+	tableRE  = regexp.MustCompile(`table\(variables: \[([^\]]*)\](?:,filters: \[(.*?)\])?\)`)
+	filterRE = regexp.MustCompile(`\{variable: ([^,]+), codes: ([^}]+)\}`)
+)
+
+// NewServer starts a fake Cantabular serving the built in fixtures.
+// Point a client at its URL, and Close it when done.
+func NewServer() (*httptest.Server, error) {
+	h, err := New(fixtures, "fixtures")
+	if err != nil {
+		return nil, err
+	}
+	return httptest.NewServer(h), nil
+}
+
+// New returns a Handler serving the datasets in the .json fixture files in dir of fsys.
+func New(fsys fs.FS, dir string) (*Handler, error) {
+	names, err := fs.Glob(fsys, path.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	h := &Handler{datasets: map[string]*Dataset{}}
+	for _, name := range names {
+		buf, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+		var ds Dataset
+		if err := json.Unmarshal(buf, &ds); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		if err := ds.check(); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		h.datasets[ds.Name] = &ds
+	}
+	return h, nil
+}
+
+// request is the body of a GraphQL request.
+type request struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables"`
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req request
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	data, err := h.answer(req)
+	w.Header().Set("Content-Type", "application/json")
+	if err != nil {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"errors": []map[string]string{{"message": err.Error()}},
+		})
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{"data": data})
+}
+
+// answer works out which kind of query req is and returns its data.
+func (h *Handler) answer(req request) (interface{}, error) {
+	q := req.Query
+	switch {
+	case strings.Contains(q, "__typename"):
+		return map[string]string{"__typename": "Query"}, nil
+	case strings.Contains(q, "datasets{name}"):
+		return h.datasetList(), nil
+	}
+
+	ds, err := h.dataset(req)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case strings.Contains(q, "variables{edges"):
+		return map[string]interface{}{"dataset": ds.variableList()}, nil
+	case tableRE.MatchString(q):
+		table, err := ds.table(req, strings.Contains(q, "values"))
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"dataset": map[string]interface{}{"table": table}}, nil
+	}
+	return nil, fmt.Errorf("fake cantabular does not understand query %s", q)
+}
+
+// datasetList answers {datasets{name}}.
+func (h *Handler) datasetList() interface{} {
+	names := make([]string, 0, len(h.datasets))
+	for name := range h.datasets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	list := []map[string]string{}
+	for _, name := range names {
+		list = append(list, map[string]string{"name": name})
+	}
+	return map[string]interface{}{"datasets": list}
+}
+
+// dataset returns the dataset named by the $ds variable of req.
+func (h *Handler) dataset(req request) (*Dataset, error) {
+	name, _ := req.Variables["ds"].(string)
+	ds, ok := h.datasets[name]
+	if !ok {
+		return nil, fmt.Errorf("dataset %q not found", name)
+	}
+	return ds, nil
+}
+
+// variableList answers the variables of a dataset.
+func (ds *Dataset) variableList() interface{} {
+	edges := []interface{}{}
+	for _, v := range ds.Variables {
+		edges = append(edges, map[string]interface{}{
+			"node": map[string]string{"name": v.Name, "label": v.Label},
+		})
+	}
+	return map[string]interface{}{"variables": map[string]interface{}{"edges": edges}}
+}
+
+// table answers a table query, with the counts only if withValues.
+// Filtered variables keep their own category order, whatever the order of the filter codes.
+func (ds *Dataset) table(req request, withValues bool) (interface{}, error) {
+	m := tableRE.FindStringSubmatch(req.Query)
+
+	var names []string
+	for _, ref := range strings.Split(m[1], ",") {
+		name, err := resolveString(ref, req.Variables)
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+
+	filters := map[string]map[string]bool{}
+	for _, fm := range filterRE.FindAllStringSubmatch(m[2], -1) {
+		name, err := resolveString(fm[1], req.Variables)
+		if err != nil {
+			return nil, err
+		}
+		codes, err := resolveStrings(fm[2], req.Variables)
+		if err != nil {
+			return nil, err
+		}
+		filters[name] = map[string]bool{}
+		for _, code := range codes {
+			filters[name][code] = true
+		}
+	}
+
+	// the indexes of the selected categories of each variable
+	var dims []interface{}
+	var selected [][]int
+	for _, name := range names {
+		v, err := ds.variable(name)
+		if err != nil {
+			return nil, err
+		}
+		filter := filters[name]
+		if err := v.checkCodes(filter); err != nil {
+			return nil, err
+		}
+
+		cats := []Category{}
+		var indexes []int
+		for i, cat := range v.Categories {
+			if filter == nil || filter[cat.Code] {
+				cats = append(cats, cat)
+				indexes = append(indexes, i)
+			}
+		}
+		dims = append(dims, map[string]interface{}{"categories": cats})
+		selected = append(selected, indexes)
+	}
+
+	result := map[string]interface{}{"dimensions": dims}
+	if !withValues {
+		return result, nil
+	}
+
+	t, err := ds.findTable(names)
+	if err != nil {
+		return nil, err
+	}
+	values := []int{}
+	ds.collect(t, names, selected, 0, 0, &values)
+	result["values"] = values
+	return result, nil
+}
+
+// collect appends the values of t for the selected categories of names[n:] to values,
+// where offset is the index into t.Values of the categories already chosen for names[:n].
+func (ds *Dataset) collect(t *Table, names []string, selected [][]int, n, offset int, values *[]int) {
+	if n == len(names) {
+		*values = append(*values, t.Values[offset])
+		return
+	}
+
+	stride := 1
+	for _, name := range names[n+1:] {
+		v, _ := ds.variable(name)
+		stride *= len(v.Categories)
+	}
+	for _, i := range selected[n] {
+		ds.collect(t, names, selected, n+1, offset+i*stride, values)
+	}
+}
+
+// variable returns the variable called name.
+func (ds *Dataset) variable(name string) (*Variable, error) {
+	for i := range ds.Variables {
+		if ds.Variables[i].Name == name {
+			return &ds.Variables[i], nil
+		}
+	}
+	return nil, fmt.Errorf("variable %q not found in dataset %q", name, ds.Name)
+}
+
+// findTable returns the table of variables names.
+func (ds *Dataset) findTable(names []string) (*Table, error) {
+	for i := range ds.Tables {
+		if strings.Join(ds.Tables[i].Variables, ",") == strings.Join(names, ",") {
+			return &ds.Tables[i], nil
+		}
+	}
+	return nil, fmt.Errorf("no fixture for table %v in dataset %q", names, ds.Name)
+}
+
+// checkCodes returns an error if filter has codes that are not categories of v,
+// as Cantabular does.
+func (v *Variable) checkCodes(filter map[string]bool) error {
+	known := map[string]bool{}
+	for _, cat := range v.Categories {
+		known[cat.Code] = true
+	}
+	for code := range filter {
+		if !known[code] {
+			return fmt.Errorf("unknown code %q for variable %q", code, v.Name)
+		}
+	}
+	return nil
+}
+
+// check returns an error if the tables of ds do not match its variables.
+func (ds *Dataset) check() error {
+	for _, t := range ds.Tables {
+		n := 1
+		for _, name := range t.Variables {
+			v, err := ds.variable(name)
+			if err != nil {
+				return err
+			}
+			n *= len(v.Categories)
+		}
+		if len(t.Values) != n {
+			return fmt.Errorf("table %v has %d values, want %d", t.Variables, len(t.Values), n)
+		}
+	}
+	return nil
+}
+
+// resolveString returns the value of a GraphQL string argument, either a $variable or a literal.
+func resolveString(arg string, vars map[string]interface{}) (string, error) {
+	arg = strings.TrimSpace(arg)
+	if strings.HasPrefix(arg, "$") {
+		s, ok := vars[arg[1:]].(string)
+		if !ok {
+			return "", fmt.Errorf("variable %s is not a string", arg)
+		}
+		return s, nil
+	}
+	return strings.Trim(arg, `"`), nil
+}
+
+// resolveStrings returns the value of a GraphQL list of strings argument, which must be a $variable.
+func resolveStrings(arg string, vars map[string]interface{}) ([]string, error) {
+	arg = strings.TrimSpace(arg)
+	list, ok := vars[strings.TrimPrefix(arg, "$")].([]interface{})
+	if !strings.HasPrefix(arg, "$") || !ok {
+		return nil, fmt.Errorf("argument %s is not a list variable", arg)
+	}
+
+	var strs []string
+	for _, item := range list {
+		s, ok := item.(string)
+		if !ok {
+			return nil, fmt.Errorf("variable %s is not a list of strings", arg)
+		}
+		strs = append(strs, s)
+	}
+	return strs, nil
+}
@@ -0,0 +1,43 @@
+package fake
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewFixtures(t *testing.T) {
+	h, err := New(fixtures, "fixtures")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Contains(t, h.datasets, "Usual-Residents")
+	assert.Contains(t, h.datasets, "People-Households")
+}
+
+func TestNewBadTable(t *testing.T) {
+	fsys := fstest.MapFS{
+		"fixtures/bad.json": {Data: []byte(`{
+			"name": "bad",
+			"variables": [{"name": "SEX", "categories": [{"code": "1"}, {"code": "2"}]}],
+			"tables": [{"variables": ["SEX"], "values": [1]}]
+		}`)},
+	}
+
+	_, err := New(fsys, "fixtures")
+	assert.Error(t, err)
+}
+
+func TestAnswerUnknownQuery(t *testing.T) {
+	h, err := New(fixtures, "fixtures")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = h.answer(request{
+		Query:     "query($ds:String!){dataset(name: $ds){rules{name}}}",
+		Variables: map[string]interface{}{"ds": "Usual-Residents"},
+	})
+	assert.Error(t, err)
+}
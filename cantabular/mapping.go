@@ -0,0 +1,132 @@
+package cantabular
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/ONSdigital/dp-find-insights-poc-api/model"
+	"github.com/ONSdigital/dp-find-insights-poc-api/sentinel"
+	"gorm.io/gorm"
+)
+
+// Variable is a Cantabular variable within a dataset.
+type Variable struct {
+	Dataset  string
+	Variable string
+}
+
+// Mapping says which Cantabular variables stand in for census tables and geotypes.
+// The 2011 Cantabular variables are not NOMIS tables, so we pretend, using the
+// cant_variable and cant_geotype tables written by cmd/cantabular -discover.
+//
+type Mapping struct {
+	Tables   map[string]Variable // by census table, eg QS501EW
+	Geotypes map[string]string   // Cantabular geography variable by geotype, eg LAD -> LA
+}
+
+// LoadMapping reads the Mapping from the database.
+func LoadMapping(ctx context.Context, gdb *gorm.DB) (*Mapping, error) {
+	var variables []model.CantVariable
+	if err := gdb.WithContext(ctx).Find(&variables).Error; err != nil {
+		return nil, err
+	}
+
+	var geotypes []model.CantGeotype
+	if err := gdb.WithContext(ctx).Find(&geotypes).Error; err != nil {
+		return nil, err
+	}
+
+	mapping := &Mapping{
+		Tables:   map[string]Variable{},
+		Geotypes: map[string]string{},
+	}
+	for _, v := range variables {
+		mapping.Tables[v.ShortNomisCode] = Variable{Dataset: v.Dataset, Variable: v.Variable}
+	}
+	for _, g := range geotypes {
+		mapping.Geotypes[g.Geotype] = g.Variable
+	}
+	return mapping, nil
+}
+
+// Table returns the Cantabular variable standing in for census table code.
+func (m *Mapping) Table(code string) (Variable, error) {
+	v, ok := m.Tables[code]
+	if !ok {
+		return Variable{}, fmt.Errorf("%w: census table %s is not available from cantabular", sentinel.ErrInvalidParams, code)
+	}
+	return v, nil
+}
+
+// Geotype returns the Cantabular geography variable for geotype.
+func (m *Mapping) Geotype(geotype string) (string, error) {
+	v, ok := m.Geotypes[geotype]
+	if !ok {
+		return "", fmt.Errorf("%w: geotype %s is not available from cantabular", sentinel.ErrInvalidParams, geotype)
+	}
+	return v, nil
+}
+
+// TableCodes returns the mapped census tables in order.
+func (m *Mapping) TableCodes() []string {
+	codes := make([]string, 0, len(m.Tables))
+	for code := range m.Tables {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// GeotypeNames returns the mapped geotypes in order.
+func (m *Mapping) GeotypeNames() []string {
+	names := make([]string, 0, len(m.Geotypes))
+	for name := range m.Geotypes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SetMappingDB sets the database the client reads its Mapping from.
+func (cant *Client) SetMappingDB(gdb *gorm.DB) {
+	cant.gdb = gdb
+}
+
+// SetMapping fixes the client's Mapping, instead of reading it from the database.
+// It is meant for tests and the fake Cantabular.
+func (cant *Client) SetMapping(mapping *Mapping) {
+	cant.mapping = mapping
+}
+
+// mappingTTL is how long the client keeps a Mapping read from the database.
+var mappingTTL = time.Minute
+
+// loadMapping is LoadMapping; tests replace it to count reads.
+var loadMapping = LoadMapping
+
+// Mapping returns the client's current Mapping.
+// A Mapping read from the database is kept for mappingTTL, so changes to the mapping
+// tables take effect without a deploy, but requests don't each query the database.
+func (cant *Client) Mapping(ctx context.Context) (*Mapping, error) {
+	if cant.mapping != nil {
+		return cant.mapping, nil
+	}
+	if cant.gdb == nil {
+		return nil, fmt.Errorf("%w: cantabular mapping needs the database", sentinel.ErrNotSupported)
+	}
+
+	cant.mu.Lock()
+	defer cant.mu.Unlock()
+	if cant.loaded != nil && time.Since(cant.loadedAt) < mappingTTL {
+		return cant.loaded, nil
+	}
+	mapping, err := loadMapping(ctx, cant.gdb)
+	if err != nil {
+		return nil, err
+	}
+	cant.loaded = mapping
+	cant.loadedAt = time.Now()
+	return mapping, nil
+}
@@ -0,0 +1,51 @@
+package cantabular
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestMappingKeptForTTL(t *testing.T) {
+	reads := 0
+	saved := loadMapping
+	defer func() { loadMapping = saved }()
+	loadMapping = func(ctx context.Context, gdb *gorm.DB) (*Mapping, error) {
+		reads++
+		return &Mapping{Tables: map[string]Variable{"QS501EW": {Dataset: "Teaching-Dataset", Variable: "Highest_qual"}}}, nil
+	}
+
+	cant := New("", "", "")
+	cant.SetMappingDB(&gorm.DB{})
+
+	for i := 0; i < 3; i++ {
+		mapping, err := cant.Mapping(context.Background())
+		assert.NoError(t, err)
+		assert.Contains(t, mapping.Tables, "QS501EW")
+	}
+	assert.Equal(t, 1, reads, "mapping read once within the TTL")
+
+	cant.loadedAt = time.Now().Add(-mappingTTL)
+	_, err := cant.Mapping(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, reads, "mapping read again after the TTL")
+}
+
+func TestMappingLoadError(t *testing.T) {
+	saved := loadMapping
+	defer func() { loadMapping = saved }()
+	loadMapping = func(ctx context.Context, gdb *gorm.DB) (*Mapping, error) {
+		return nil, errors.New("database down")
+	}
+
+	cant := New("", "", "")
+	cant.SetMappingDB(&gorm.DB{})
+
+	_, err := cant.Mapping(context.Background())
+	assert.Error(t, err)
+	assert.Nil(t, cant.loaded, "failed read not kept")
+}
@@ -8,9 +8,14 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strings"
 
 	"github.com/ONSdigital/dp-find-insights-poc-api/cantabular"
+	"github.com/ONSdigital/dp-find-insights-poc-api/cantabular/fake"
+	"github.com/ONSdigital/dp-find-insights-poc-api/pkg/database"
 	"github.com/shurcooL/graphql"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
 )
 
 func main() {
@@ -29,6 +34,11 @@ func main() {
 	variables := flag.Bool("variables", false, "list variables, results eg. 'AGE_T022A : Age of individual (21 categories)' (like old short codes)")
 	cmetadata := flag.Bool("cmetadata", false, "display cant-like tactical metadata slowly")
 	nmetadata := flag.Bool("nmetadata", false, "display NOMIS-like tactical metadata slowly")
+	discover := flag.Bool("discover", false, "suggest census table and geotype mappings for -dslist slowly")
+	dslist := flag.String("dslist", "Usual-Residents,People-Households", "discover: comma separated datasets to search")
+	write := flag.Bool("write", false, "discover: write the suggested mappings to the db")
+	url := flag.String("url", cantabular.URL, "cantabular graphql url")
+	useFake := flag.Bool("fake", false, "query a local fake cantabular serving the test fixtures instead of -url")
 	flag.Parse()
 
 	if *nmetadata && *cmetadata {
@@ -38,7 +48,43 @@ func main() {
 
 	ctx := context.Background()
 
-	cant := cantabular.New(cantabular.URL, os.Getenv("CANT_USER"), os.Getenv("CANT_PW"))
+	if *useFake {
+		srv, err := fake.NewServer()
+		if err != nil {
+			log.Fatal(err)
+		}
+		*url = srv.URL // the fake stops when we exit
+	}
+
+	cant := cantabular.New(*url, os.Getenv("CANT_USER"), os.Getenv("CANT_PW"))
+
+	// only the queries which need the census table and geotype mapping need the db
+	var gdb *gorm.DB
+	if *nmetadata || *cmetadata || *query1 || *query2 || *discover {
+		gdb = openDB()
+		cant.SetMappingDB(gdb)
+	}
+
+	if *discover {
+		candidates, geotypes, err := cant.Discover(ctx, gdb, strings.Split(*dslist, ","))
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, c := range candidates {
+			fmt.Printf("%s\t%s\t%s\t%.2f\t%s\n", c.ShortNomisCode, c.Dataset, c.Variable.Variable, c.Score, c.Label)
+		}
+		for _, g := range geotypes {
+			fmt.Printf("%s\t%s\t%s\n", g.Geotype, g.Dataset, g.Variable.Variable)
+		}
+		if *write {
+			if err := cantabular.WriteMapping(ctx, gdb, candidates, geotypes); err != nil {
+				log.Fatal(err)
+			}
+			fmt.Printf("wrote %d census table and %d geotype mappings\n", len(candidates), len(geotypes))
+		}
+		os.Exit(0)
+	}
+
 	if *nmetadata {
 		buf, err := cant.QueryMetaData(ctx, *ds, true)
 		if err != nil {
@@ -60,7 +106,7 @@ func main() {
 	// MetricFilter type query
 	if *query1 {
 
-		checkParams(*code, *geotype)
+		checkParams(ctx, cant, *code, *geotype)
 		if *code == "" || *geo == "" || *geotype == "" {
 			fmt.Println("must define -code, -geo and -geotype")
 			os.Exit(1)
@@ -79,7 +125,7 @@ func main() {
 
 	// Pure Metric query
 	if *query2 {
-		checkParams(*code, *geotype)
+		checkParams(ctx, cant, *code, *geotype)
 
 		if *code == "" || *geotype == "" {
 			fmt.Println("must define -code and -geotype")
@@ -137,24 +183,30 @@ func main() {
 
 }
 
-func checkParams(code, geoType string) {
-	shorts := cantabular.ShortVarMap()
-	if shorts[code] == "" {
-		fmt.Println("err: use -code from following list")
-		for k := range shorts {
-			fmt.Print(k + " ")
+// openDB opens the db holding the census table and geotype mapping.
+func openDB() *gorm.DB {
+	gdb, err := gorm.Open(postgres.Open(database.GetDSN()), &gorm.Config{})
+	if err != nil {
+		log.Fatal(err)
+	}
+	return gdb
+}
 
-		}
+func checkParams(ctx context.Context, cant *cantabular.Client, code, geoType string) {
+	mapping, err := cant.Mapping(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if _, err := mapping.Table(code); err != nil {
+		fmt.Println("err: use -code from following list")
+		fmt.Println(strings.Join(mapping.TableCodes(), " "))
 		os.Exit(1)
 	}
 
-	gts := cantabular.GeoTypeMap()
-	if gts[geoType] == "" {
+	if _, err := mapping.Geotype(geoType); err != nil {
 		fmt.Println("err: use -geotype from following list")
-		for k := range gts {
-			fmt.Print(k + " ")
-
-		}
+		fmt.Println(strings.Join(mapping.GeotypeNames(), " "))
 		os.Exit(1)
 	}
 
@@ -11,8 +11,10 @@ import (
 
 	"github.com/ONSdigital/dp-find-insights-poc-api/cantabular"
 	"github.com/ONSdigital/dp-find-insights-poc-api/metadata"
+	"github.com/ONSdigital/dp-find-insights-poc-api/model"
 	"github.com/ONSdigital/dp-find-insights-poc-api/pkg/database"
 	geodata "github.com/ONSdigital/dp-find-insights-poc-api/pkg/geodata"
+	"github.com/ONSdigital/dp-find-insights-poc-api/pkg/table"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
@@ -20,7 +22,7 @@ import (
 func main() {
 	maxmetrics := flag.Int("maxmetrics", 0, "max number of rows to accept from db query (default 0 means no limit)")
 	flag.Usage = func() {
-		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [command-options] query|ckmeans|ckmeansratio|metadata [subcommand-options]\n", filepath.Base(os.Args[0]))
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [command-options] query|ckmeans|breaks|ckmeansratio|metadata [subcommand-options]\n", filepath.Base(os.Args[0]))
 		flag.PrintDefaults()
 	}
 	flag.Parse()
@@ -44,6 +46,7 @@ func main() {
 	if err != nil {
 		log.Fatalln(err)
 	}
+	cant.SetMappingDB(gdb)
 	md, err := metadata.New(gdb)
 	if err != nil {
 		log.Fatalln(err)
@@ -55,6 +58,8 @@ func main() {
 		query(ctx, app, flag.Args()[1:])
 	case "ckmeans":
 		ckmeans(ctx, app, flag.Args()[1:])
+	case "breaks":
+		breaks(ctx, app, flag.Args()[1:])
 	case "metadata":
 		mdquery(ctx, md, flag.Args()[1:])
 	default:
@@ -69,6 +74,7 @@ func query(ctx context.Context, app *geodata.Geodata, argv []string) {
 	flagset := flag.NewFlagSet("original", flag.ExitOnError)
 
 	year := flagset.Int("year", 2011, "census year")
+	version := flagset.String("version", "", "data version (default latest public version)")
 	bbox := flagset.String("bbox", "", "bounding box lon1,lat1,lon2,lat2 (any two opposite corners)")
 	location := flagset.String("location", "", "central point for radius queries")
 	radius := flagset.Int("radius", 0, "radius in meters")
@@ -77,14 +83,18 @@ func query(ctx context.Context, app *geodata.Geodata, argv []string) {
 	flagset.Var(&geotypes, "geotype", "geography types (LSOA, LAD, etc)")
 	flagset.Var(&rows, "rows", "row or row range")
 	flagset.Var(&cols, "cols", "column name(s) to return")
+	format := flagset.String("format", "csv", "output format (csv, json or ndjson)")
 	flagset.Parse(argv)
 
-	body, err := app.Query(ctx, *year, *bbox, *location, *radius, *polygon, geotypes, rows, cols, *censustable)
+	tf, err := table.ParseFormat(*format)
 	if err != nil {
 		log.Fatalln(err)
 	}
 
-	fmt.Printf("%s", body)
+	err = app.QueryStream(ctx, os.Stdout, *year, resolveVersion(ctx, app, *year, *version), *bbox, *location, *radius, *polygon, geotypes, rows, cols, *censustable, tf)
+	if err != nil {
+		log.Fatalln(err)
+	}
 }
 
 func ckmeans(ctx context.Context, app *geodata.Geodata, argv []string) {
@@ -93,13 +103,14 @@ func ckmeans(ctx context.Context, app *geodata.Geodata, argv []string) {
 	flagset := flag.NewFlagSet("ckmeans", flag.ExitOnError)
 
 	year := flagset.Int("year", 2011, "census year")
+	version := flagset.String("version", "", "data version (default latest public version)")
 	flagset.Var(&cat, "cat", "category code(s) to provide ckmeans for")
 	flagset.Var(&geotype, "geotype", "geography types (LSOA, LAD, etc)")
 	k := flagset.Int("k", 5, "number of clusters/bins")
 	divide_by := flagset.String("divide_by", "", "category code to divide all other categories by (optional)")
 	flagset.Parse(argv)
 
-	breaks, err := app.CKmeans(ctx, *year, cat, geotype, *k, *divide_by)
+	breaks, err := app.CKmeans(ctx, *year, resolveVersion(ctx, app, *year, *version), cat, geotype, *k, *divide_by)
 	if err != nil {
 		log.Fatalln(err)
 	}
@@ -110,14 +121,64 @@ func ckmeans(ctx context.Context, app *geodata.Geodata, argv []string) {
 	fmt.Print(string(append(buf, "\n"...)))
 }
 
+func breaks(ctx context.Context, app *geodata.Geodata, argv []string) {
+	var cat, geotype, rows, within multiFlag
+
+	flagset := flag.NewFlagSet("breaks", flag.ExitOnError)
+
+	year := flagset.Int("year", 2011, "census year")
+	version := flagset.String("version", "", "data version (default latest public version)")
+	flagset.Var(&cat, "cat", "category code(s) to provide breaks for")
+	flagset.Var(&geotype, "geotype", "geography types (LSOA, LAD, etc)")
+	method := flagset.String("method", geodata.ClassifyCkmeans, "classification method (ckmeans, quantile, equal, stddev or headtail)")
+	k := flagset.Int("k", 5, "number of classes")
+	divide_by := flagset.String("divide_by", "", "category code to divide all other categories by (optional)")
+	bbox := flagset.String("bbox", "", "bounding box lon1,lat1,lon2,lat2 (any two opposite corners)")
+	location := flagset.String("location", "", "central point for radius queries")
+	radius := flagset.Int("radius", 0, "radius in meters")
+	polygon := flagset.String("polygon", "", "polygon x1,y1,...,x1,y1 (closed linestring)")
+	flagset.Var(&rows, "rows", "row or row range")
+	flagset.Var(&within, "within", "geography code(s) to find breaks within")
+	flagset.Parse(argv)
+
+	sel := geodata.Selection{
+		Geos:     rows,
+		BBox:     *bbox,
+		Location: *location,
+		Radius:   *radius,
+		Polygon:  *polygon,
+		Within:   within,
+	}
+	breaks, err := app.Breaks(ctx, *year, resolveVersion(ctx, app, *year, *version), sel, cat, geotype, *method, *k, *divide_by)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	buf, err := json.MarshalIndent(breaks, "", "    ")
+	if err != nil {
+		log.Fatalln(err)
+	}
+	fmt.Print(string(append(buf, "\n"...)))
+}
+
+// resolveVersion returns the data version to query for year.
+// Non-public versions can be used, since anyone running this has the database.
+func resolveVersion(ctx context.Context, app *geodata.Geodata, year int, version string) string {
+	version, err := app.ResolveVersion(ctx, year, version, true)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	return version
+}
+
 func mdquery(ctx context.Context, md *metadata.Metadata, argv []string) {
 	flagset := flag.NewFlagSet("metadata", flag.ExitOnError)
 
 	year := flagset.Int("year", 2011, "census year")
 	filtertotals := flagset.Bool("filtertotals", false, "include totals")
+	lang := flagset.String("lang", model.LangEnglish, "language of names (en or cy)")
 	flagset.Parse(argv)
 
-	result, err := md.Get(ctx, *year, *filtertotals)
+	result, err := md.Get(ctx, *year, *filtertotals, *lang)
 	if err != nil {
 		log.Fatalln(err)
 	}
@@ -17,6 +17,8 @@ func DoSQL(t *testing.T, db *database.Database, sql string) {
 func ClearDB(db *database.Database) error {
 	// order of delete-froms matters!
 	tables := []string{
+		"breaks",
+		"geo_parent",
 		"geo_metric",
 		"geo",
 		"nomis_category",
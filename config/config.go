@@ -15,13 +15,21 @@ type Config struct {
 	EnableDatabase             bool          `envconfig:"ENABLE_DATABASE"`
 	MaxMetrics                 int           `envconfig:"MAX_METRICS"`
 	WriteTimeout               time.Duration `envconfig:"WRITE_TIMEOUT"`
+	StreamTimeout              time.Duration `envconfig:"STREAM_TIMEOUT"`
 	APIToken                   string        `envconfig:"API_TOKEN"`
 	EnableHeaderAuth           bool          `envconfig:"ENABLE_HEADER_AUTH"`
 	CacheSize                  int           `envconfig:"CACHE_SIZE"`
 	CacheTTL                   time.Duration `envconfig:"CACHE_TTL"`
+	CacheLocalTTL              time.Duration `envconfig:"CACHE_LOCAL_TTL"`
+	CacheMaxEntrySize          int           `envconfig:"CACHE_MAX_ENTRY_SIZE"`
+	CacheStore                 string        `envconfig:"CACHE_STORE"`
+	RedisAddr                  string        `envconfig:"REDIS_ADDR"`
+	RedisPassword              string        `envconfig:"REDIS_PASSWORD"`
+	RedisDB                    int           `envconfig:"REDIS_DB"`
 	EnableCantabular           bool          `envconfig:"ENABLE_CANTABULAR"`
 	CantabularURL              string        `envconfig:"CANT_URL"`
 	CantabularUser             string        `envconfig:"CANT_USER"`
+	CantabularYears            []int         `envconfig:"CANT_YEARS"`
 }
 
 var cfg *Config
@@ -40,10 +48,15 @@ func Get() (*Config, error) {
 		HealthCheckCriticalTimeout: 90 * time.Second,
 		MaxMetrics:                 200000,           // max number of rows to accept from "geo" table queries
 		WriteTimeout:               30 * time.Second, // http WriteTimeout
+		StreamTimeout:              5 * time.Minute,  // max duration of streamed responses
 		APIToken:                   "",
 		EnableHeaderAuth:           false,
 		CacheSize:                  200,            // memory cache size in MB
 		CacheTTL:                   12 * time.Hour, // cache entry TTL
+		CacheLocalTTL:              time.Minute,    // bigcache entry TTL in a chain, so clears reach every instance
+		CacheMaxEntrySize:          20,             // streamed responses larger than this many MB are not cached
+		CacheStore:                 "bigcache",     // bigcache, redis or chain (bigcache in front of redis)
+		// Redis is only used by the redis and chain cache stores, so no defaults
 		// Cantabular defaults to disabled, so no defaults
 	}
 
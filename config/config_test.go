@@ -29,8 +29,12 @@ func TestConfig(t *testing.T) {
 					EnableDatabase:             false,
 					MaxMetrics:                 200000,
 					WriteTimeout:               30 * time.Second,
+					StreamTimeout:              5 * time.Minute,
 					CacheSize:                  200,
 					CacheTTL:                   12 * time.Hour,
+					CacheLocalTTL:              time.Minute,
+					CacheMaxEntrySize:          20,
+					CacheStore:                 "bigcache",
 				})
 			})
 
@@ -301,6 +301,45 @@ func (di *dataIngest) addClassificationData() {
 	}
 }
 
+// addWelshNames reads optional Welsh translations of topic, table and category names
+// from a CSV with "code" and "welsh_name" columns.
+// Codes are matched against topic (eg QS1), table (eg QS101EW) and category (eg QS101EW0001) codes.
+// Names without a translation are left empty, and fall back to English in the API.
+func (di *dataIngest) addWelshNames(fn string) {
+	if _, err := os.Stat(fn); os.IsNotExist(err) {
+		log.Printf("no Welsh translations in %s", fn)
+		return
+	}
+
+	recs := readCsvFile(fn)
+	if len(recs) < 1 || len(recs[0]) < 2 || recs[0][0] != "code" || recs[0][1] != "welsh_name" {
+		log.Fatalf("%s: want code,welsh_name header", fn)
+	}
+
+	for _, rec := range recs[1:] {
+		code, name := strings.TrimSpace(rec[0]), strings.TrimSpace(rec[1])
+		if code == "" || name == "" {
+			continue
+		}
+
+		var res *gorm.DB
+		switch {
+		case len(code) <= 3:
+			res = di.gdb.Model(&model.NomisTopic{}).Where("top_nomis_code = ?", code).Update("welsh_name", name)
+		case len(code) <= 7:
+			res = di.gdb.Model(&model.NomisDesc{}).Where("short_nomis_code = ?", code).Update("welsh_name", name)
+		default:
+			res = di.gdb.Model(&model.NomisCategory{}).Where("long_nomis_code = ?", code).Update("welsh_name", name)
+		}
+		if res.Error != nil {
+			log.Fatal(res.Error)
+		}
+		if res.RowsAffected == 0 {
+			log.Printf("%s: unknown code %s", fn, code)
+		}
+	}
+}
+
 func readCsvFile(filePath string) (records [][]string) {
 
 	func() {
@@ -363,6 +402,7 @@ func main() {
 	di.addGeoTypes()
 	di.addClassificationData()
 	longToCatid := di.addCategoryData()
+	di.addWelshNames(dataPref + "welsh.csv")
 	di.addGeoGeoMetricData(longToCatid)
 	di.popTopLevelGeoNames()
 	di.putVersion()
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"time"
+
+	"github.com/ONSdigital/dp-find-insights-poc-api/model"
+	"github.com/ONSdigital/dp-find-insights-poc-api/pkg/database"
+	"github.com/ONSdigital/dp-find-insights-poc-api/pkg/geodata"
+)
+
+// populates the breaks table with ckmeans breaks for every category, so /ckmeans doesn't have to compute them
+func main() {
+	year := flag.Int("year", 2011, "census year")
+	version := flag.String("version", "", "data version (default latest public version)")
+	geotypes := flag.String("geotypes", "LAD,MSOA,LSOA", "comma-separated geotypes to compute breaks for")
+	kmin := flag.Int("kmin", 3, "smallest k")
+	kmax := flag.Int("kmax", 9, "largest k")
+	flag.Parse()
+
+	if *kmin < 1 || *kmax < *kmin {
+		log.Fatal("need 1 <= kmin <= kmax")
+	}
+	var ks []int
+	for k := *kmin; k <= *kmax; k++ {
+		ks = append(ks, k)
+	}
+
+	db, err := database.Open("pgx", database.GetDSN())
+	if err != nil {
+		log.Fatal(err)
+	}
+	app, err := geodata.New(db, nil, 0)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ctx := context.Background()
+	ver, err := app.ResolveVersion(ctx, *year, *version, true)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	tables, err := tableCategories(db, *year)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	t0 := time.Now()
+	for table, cats := range tables {
+		// raw values
+		// StoreBreaks saves every break it can, so carry on to the ratios if some were skipped
+		if err := app.StoreBreaks(ctx, *year, ver, cats, []string{*geotypes}, ks, ""); err != nil {
+			log.Printf("%s: %s", table, err)
+		}
+
+		// ratios to the table's total
+		total := model.TotalCat(cats[0])
+		var ratioCats []string
+		for _, cat := range cats {
+			if !model.IsTotalCat(cat) {
+				ratioCats = append(ratioCats, cat)
+			}
+		}
+		if len(ratioCats) != 0 {
+			if err := app.StoreBreaks(ctx, *year, ver, ratioCats, []string{*geotypes}, ks, total); err != nil {
+				log.Printf("%s divided by %s: %s", table, total, err)
+			}
+		}
+		log.Printf("%s: %d categories", table, len(cats))
+	}
+	log.Printf("%d tables for version %s in %s", len(tables), ver, time.Since(t0))
+}
+
+// tableCategories returns the category codes in each table for year.
+func tableCategories(db *database.Database, year int) (map[string][]string, error) {
+	rows, err := db.DB().Query(`
+SELECT
+	nomis_desc.short_nomis_code,
+	nomis_category.long_nomis_code
+FROM
+	nomis_desc,
+	nomis_category
+WHERE nomis_category.nomis_desc_id = nomis_desc.id
+AND nomis_category.year = $1
+ORDER BY nomis_category.long_nomis_code
+`, year)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tables := map[string][]string{}
+	for rows.Next() {
+		var table, cat string
+		if err := rows.Scan(&table, &cat); err != nil {
+			return nil, err
+		}
+		tables[table] = append(tables[table], cat)
+	}
+	return tables, rows.Err()
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"log"
+
+	"github.com/ONSdigital/dp-find-insights-poc-api/pkg/database"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// levels lists each geotype with the geotypes that can be its parent, nearest first.
+// Welsh LADs have no region, so fall through to Country.
+var levels = []struct {
+	child   string
+	parents []string
+}{
+	{"LSOA", []string{"MSOA"}},
+	{"MSOA", []string{"LAD"}},
+	{"LAD", []string{"Region", "Country"}},
+	{"Region", []string{"Country"}},
+	{"Country", []string{"EW"}},
+}
+
+// populates geo_parent from the boundaries in geo.wkb_geometry
+func main() {
+	db, err := gorm.Open(postgres.Open(database.GetDSN()), &gorm.Config{})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := db.Exec("DELETE FROM geo_parent").Error; err != nil {
+		log.Fatal(err)
+	}
+
+	// A geography's parent is the geography of the next level up whose boundary covers
+	// a point inside the child. Boundaries are generalised, so they don't nest exactly,
+	// but a point on the child's surface is well inside its parent.
+	// Where overlapping parents both cover the point, the one sharing most of the child's
+	// area is chosen, since a child can only have one parent.
+	for _, level := range levels {
+		for _, parent := range level.parents {
+			res := db.Exec(`
+INSERT INTO geo_parent (geo_id, parent_id)
+SELECT DISTINCT ON (child.id) child.id, parent.id
+FROM
+	geo AS child,
+	geo_type AS child_type,
+	geo AS parent,
+	geo_type AS parent_type
+WHERE child.valid
+AND child_type.id = child.type_id
+AND child_type.name = ?
+AND parent.valid
+AND parent_type.id = parent.type_id
+AND parent_type.name = ?
+AND child.wkb_geometry IS NOT NULL
+AND parent.wkb_geometry IS NOT NULL
+AND ST_Covers(parent.wkb_geometry, ST_PointOnSurface(child.wkb_geometry))
+AND NOT EXISTS (SELECT 1 FROM geo_parent WHERE geo_parent.geo_id = child.id)
+ORDER BY child.id, ST_Area(ST_Intersection(parent.wkb_geometry, child.wkb_geometry)) DESC
+`, level.child, parent)
+			if res.Error != nil {
+				log.Fatal(res.Error)
+			}
+			log.Printf("%s -> %s: %d", level.child, parent, res.RowsAffected)
+		}
+	}
+
+	// Top level geographies may not have boundaries, so fall back to codes:
+	// E codes are in England, W codes are in Wales, and both are in England and Wales.
+	for _, fallback := range []struct {
+		child  string
+		prefix string
+		parent string
+	}{
+		{"Region", "E", "E92000001"},
+		{"LAD", "E", "E92000001"},
+		{"LAD", "W", "W92000004"},
+		{"Country", "", "K04000001"},
+	} {
+		res := db.Exec(`
+INSERT INTO geo_parent (geo_id, parent_id)
+SELECT child.id, parent.id
+FROM
+	geo AS child,
+	geo_type AS child_type,
+	geo AS parent
+WHERE child.valid
+AND child_type.id = child.type_id
+AND child_type.name = ?
+AND child.code LIKE ?
+AND parent.code = ?
+AND NOT EXISTS (SELECT 1 FROM geo_parent WHERE geo_parent.geo_id = child.id)
+`, fallback.child, fallback.prefix+"%", fallback.parent)
+		if res.Error != nil {
+			log.Fatal(res.Error)
+		}
+		log.Printf("%s %s* -> %s: %d", fallback.child, fallback.prefix, fallback.parent, res.RowsAffected)
+	}
+
+	var orphans int64
+	db.Raw(`
+SELECT COUNT(*)
+FROM geo, geo_type
+WHERE geo.valid
+AND geo_type.id = geo.type_id
+AND geo_type.name <> 'EW'
+AND NOT EXISTS (SELECT 1 FROM geo_parent WHERE geo_parent.geo_id = geo.id)
+`).Scan(&orphans)
+	log.Printf("geographies without a parent: %d", orphans)
+}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/ONSdigital/dp-find-insights-poc-api/model"
 	"github.com/ONSdigital/dp-find-insights-poc-api/pkg/database"
+	"github.com/spf13/cast"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
@@ -64,7 +65,16 @@ func parsePostcodeCSV(db *gorm.DB, file string) {
 		field[k] = i
 	}
 
-	var j int32
+	// the MAY20 file has no coordinates, but other postcode files (eg ONSPD) do
+	latField, hasLat := field["lat"]
+	longField, hasLong := field["long"]
+	hasCoords := hasLat && hasLong
+
+	lsoas := geoIDs(db, "LSOA")
+	msoas := geoIDs(db, "MSOA")
+	lads := geoIDs(db, "LAD")
+
+	var j, missingLAD int32
 
 	sem := make(chan int, MAX)
 
@@ -79,22 +89,35 @@ func parsePostcodeCSV(db *gorm.DB, file string) {
 		// Scotland isn't of interest nor Northern Ireland nor Channel Islands nor Isle of Man
 		if !strings.HasPrefix(msoa11cd, "S") && !strings.HasPrefix(msoa11cd, "N") && !strings.HasPrefix(msoa11cd, "L") && !strings.HasPrefix(msoa11cd, "M") && msoa11cd != "" {
 
+			pc := model.PostCode{
+				Pcds:   pcds,
+				Oa11cd: line[field["oa11cd"]],
+				GeoID:  msoas[msoa11cd],
+				LsoaID: lsoas[line[field["lsoa11cd"]]],
+				LadID:  lads[line[field["ladcd"]]],
+			}
+			if pc.GeoID == 0 {
+				log.Fatalf("not found: %s", msoa11cd)
+			}
+			if pc.LsoaID == 0 {
+				log.Fatalf("not found: %s", line[field["lsoa11cd"]])
+			}
+			// LAD codes are from 2020, so some merged LADs won't be in geo
+			if pc.LadID == 0 {
+				atomic.AddInt32(&missingLAD, 1)
+			}
+			if hasCoords {
+				pc.Lat = cast.ToFloat64(line[latField])
+				pc.Long = cast.ToFloat64(line[longField])
+			}
+
 			sem <- 1
 			wg.Add(1)
-			go func(pcds, msoa11cd string) {
+			go func(pc model.PostCode) {
 				defer func() {
 					wg.Done()
 					<-sem
 				}()
-				var geos model.Geo
-				db.Where("type_id = 5 and code=?", msoa11cd).Find(&geos) // limit by MSOA
-				if geos.ID == 0 {
-					log.Fatalf("not found: %s", msoa11cd)
-				}
-
-				var pc model.PostCode
-				pc.GeoID = geos.ID
-				pc.Pcds = pcds
 				db.Save(&pc)
 
 				atomic.AddInt32(&j, 1)
@@ -102,11 +125,25 @@ func parsePostcodeCSV(db *gorm.DB, file string) {
 				if j%100000 == 0 {
 					fmt.Printf("~%.1f%% ... ", (float64(j)/2300000)*100)
 				}
-			}(pcds, msoa11cd)
+			}(pc)
 		}
 
 	}
 
 	wg.Wait()
-	fmt.Printf("%d rows\n", j)
+	fmt.Printf("%d rows, %d without a LAD\n", j, missingLAD)
+}
+
+// geoIDs returns a map of geography code to geo.id for every geography of geotype.
+func geoIDs(db *gorm.DB, geotype string) map[string]int32 {
+	var geos []model.Geo
+	if err := db.Joins("JOIN geo_type ON geo_type.id = geo.type_id").Where("geo_type.name = ?", geotype).Find(&geos).Error; err != nil {
+		log.Fatal(err)
+	}
+
+	ids := make(map[string]int32, len(geos))
+	for _, g := range geos {
+		ids[g.Code] = g.ID
+	}
+	return ids
 }
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/ONSdigital/dp-find-insights-poc-api/api"
+	"github.com/ONSdigital/dp-find-insights-poc-api/pkg/geodata"
+	"github.com/ONSdigital/dp-find-insights-poc-api/pkg/where"
+)
+
+func (svr *Server) GetAggregate(w http.ResponseWriter, r *http.Request, year int, params api.GetAggregateParams) {
+	if !svr.assertAuthorized(w, r) || !svr.assertDatabaseEnabled(w, r) {
+		return
+	}
+
+	version, err := svr.queryVersion(r, year, params.Version)
+	if err != nil {
+		sendRespondError(r.Context(), w, err)
+		return
+	}
+
+	generate := func() ([]byte, error) {
+		var rows, cols, geotype []string
+		var bbox, location, polygon, censustable, method string
+		var radius int
+		if params.Rows != nil {
+			rows = *params.Rows
+		}
+		if params.Cols != nil {
+			cols = *params.Cols
+		}
+		if params.Geotype != nil {
+			geotype = *params.Geotype
+		}
+		if params.Bbox != nil {
+			bbox = *params.Bbox
+		}
+		if params.Location != nil {
+			location = *params.Location
+		}
+		if params.Radius != nil {
+			radius = *params.Radius
+		}
+		if params.Polygon != nil {
+			polygon = *params.Polygon
+		}
+		if params.Censustable != nil {
+			censustable = *params.Censustable
+		}
+		if params.Method != nil {
+			method = *params.Method
+		}
+
+		catset, err := where.ParseMultiArgs(cols)
+		if err != nil {
+			return nil, err
+		}
+		// geography_code and friends make no sense in a sum
+		_, catset, err = geodata.ExtractSpecialCols(catset)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := svr.querygeodata.Aggregate(r.Context(), year, version, bbox, location, radius, polygon, geotype, rows, catset, censustable, method)
+		if err != nil {
+			return nil, err
+		}
+		return toJSON(resp)
+	}
+
+	svr.respond(w, r, mimeJSON, generate, version)
+}
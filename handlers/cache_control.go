@@ -1,32 +1,59 @@
 package handlers
 
 import (
+	"bufio"
+	"bytes"
+	"context"
 	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"net/textproto"
 	"strings"
+	"time"
 
 	"github.com/ONSdigital/dp-find-insights-poc-api/cache"
 	"github.com/ONSdigital/dp-find-insights-poc-api/sentinel"
 	"github.com/ONSdigital/log.go/v2/log"
+	"github.com/go-chi/chi/v5"
 )
 
 const (
-	mimeCSV  = "text/csv"
-	mimeJSON = "application/json"
+	mimeCSV     = "text/csv"
+	mimeJSON    = "application/json"
+	mimeNDJSON  = "application/x-ndjson"
+	mimeGeoJSON = "application/geo+json"
+	mimeMVT     = "application/vnd.mapbox-vector-tile"
 )
 
 type generateFunc func() ([]byte, error)
 
+// streamFunc writes a response body to w as it is generated.
+// Response headers may be added to header before the first write to w;
+// they are cached along with the body.
+type streamFunc func(w io.Writer, header http.Header) error
+
+// streamLockWait is how long a streamed request waits for an identical one to finish before
+// going to the db itself.
+// The first request holds the cache key while it writes to its client, so a slow client
+// must not hold up every other request for the same data.
+var streamLockWait = 5 * time.Second
+
 // respond returns cached data if it is available, or generates and caches new data.
-func (svr *Server) respond(w http.ResponseWriter, r *http.Request, contentType string, generate generateFunc) {
+// variant is added to the cache key for responses that depend on headers other than Accept.
+//
+// The cache key is only locked while data is generated, not while it is sent to the client.
+//
+func (svr *Server) respond(w http.ResponseWriter, r *http.Request, contentType string, generate generateFunc, variant ...string) {
 
 	// add CORS header
 	w.Header().Set("Access-Control-Allow-Origin", "*")
 
 	var err error
+	var header http.Header
 	var body []byte
 
-	key := cache.CacheKey(r)
+	key := cache.CacheKey(r, contentType, variant...)
 
 	// allocate a serialiser for this cache key
 	ser := svr.cm.AllocateEntry(key)
@@ -40,8 +67,10 @@ func (svr *Server) respond(w http.ResponseWriter, r *http.Request, contentType s
 		defer ser.Unlock()
 
 		if !noCache(r) {
-			body, err = ser.Get(ctx)
+			var cached []byte
+			cached, err = ser.Get(ctx)
 			if err == nil {
+				header, body, err = decodeCached(cached)
 				return
 			}
 		}
@@ -51,27 +80,195 @@ func (svr *Server) respond(w http.ResponseWriter, r *http.Request, contentType s
 			return
 		}
 
-		// if there is a problem saving response in cache, log it, but still send to client
-		err = ser.Set(ctx, body)
-		if err != nil {
-			log.Warn(ctx, "cannot cache", log.Data{"message": err.Error(), "uri": key, "size": len(body)})
-			err = nil
+		// cache it the same way as streamed responses, which may share the cache key
+		cerr := ser.Set(ctx, append(encodeHeader(http.Header{}), body...))
+		if cerr != nil {
+			log.Warn(ctx, "cannot cache", log.Data{"message": cerr.Error(), "uri": key, "size": len(body)})
 		}
 	}()
 
-	if err == nil {
-		w.Header().Add("Content-Type", contentType)
-		w.Write(body)
+	if err != nil {
+		sendRespondError(ctx, w, err)
 		return
 	}
+	copyHeader(w.Header(), header)
+	w.Header().Add("Content-Type", contentType)
+	w.Write(body)
+}
+
+// streamedRoutes are the routes, as registered by api.HandlerWithOptions, whose handlers
+// stream their responses with respondStream.
+var streamedRoutes = map[string]bool{
+	"/query/{year}":  true,
+	"/query2/{year}": true,
+}
+
+// IsStreamed is true if r has been routed to a handler that streams its response.
+// Routing happens inside api.HandlerWithOptions, so call IsStreamed from one of its
+// Middlewares.
+func IsStreamed(r *http.Request) bool {
+	rctx := chi.RouteContext(r.Context())
+	return rctx != nil && streamedRoutes[rctx.RoutePattern()]
+}
+
+// respondStream returns cached data if it is available, or streams new data to the client.
+// The new data is teed into the cache, unless it grows too big.
+//
+// The cache key stays locked while data is streamed, so identical requests wait
+// for this one to finish rather than all querying the db.
+// They only wait for streamLockWait, and then stream from the db without the cache.
+//
+func (svr *Server) respondStream(w http.ResponseWriter, r *http.Request, contentType string, stream streamFunc, variant ...string) {
+
+	// add CORS header
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	key := cache.CacheKey(r, contentType, variant...)
+
+	// allocate a serialiser for this cache key
+	ser := svr.cm.AllocateEntry(key)
+	defer ser.Free()
+
+	ctx := r.Context()
+
+	// lock cache key before doing any cache operations
+	locked := ser.LockWithin(streamLockWait)
+	if !locked {
+		log.Info(ctx, "cache key busy, streaming without cache", log.Data{"uri": key})
+	}
+
+	if locked && !noCache(r) {
+		cached, err := ser.Get(ctx)
+		if err == nil {
+			ser.Unlock()
+			header, body, err := decodeCached(cached)
+			if err != nil {
+				sendError(ctx, w, http.StatusInternalServerError, err.Error())
+				return
+			}
+			copyHeader(w.Header(), header)
+			w.Header().Add("Content-Type", contentType)
+			w.Write(body)
+			return
+		}
+	}
+
+	if locked {
+		defer ser.Unlock()
+	}
+
+	tee := ser.NewTee(w)
+	sw := &streamWriter{w: w, tee: tee, contentType: contentType, header: http.Header{}}
+	err := stream(sw, sw.header)
+
+	if err != nil {
+		if !sw.started {
+			sendRespondError(ctx, w, err)
+			return
+		}
+		// Too late to send an error status, so abort the response to let the client
+		// know it is incomplete.
+		log.Error(ctx, "error while streaming response", err, log.Data{"uri": key})
+		panic(http.ErrAbortHandler)
+	}
+
+	// make sure headers are sent even if the body was empty
+	sw.start()
+
+	// only the holder of the cache key may save to it
+	if !locked {
+		return
+	}
+
+	if tee.Overflowed() {
+		log.Info(ctx, "response too big to cache", log.Data{"uri": key})
+		return
+	}
+
+	// if there is a problem saving response in cache, log it; the client already has it
+	err = tee.Save(ctx)
+	if err != nil {
+		log.Warn(ctx, "cannot cache", log.Data{"message": err.Error(), "uri": key})
+	}
+}
+
+// writeAll writes a fully generated body to w, unless err is set.
+// It lets streamFuncs return results that can't be streamed.
+func writeAll(w io.Writer, body []byte, err error) error {
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(body)
+	return err
+}
+
+// streamWriter sends headers before the first write to the client.
+// The headers are also saved at the start of the cached copy, so they can be
+// sent again with cached responses.
+// started records whether anything has been sent, since an error status can only be
+// sent before that.
+type streamWriter struct {
+	w           http.ResponseWriter
+	tee         *cache.Tee
+	contentType string
+	header      http.Header // headers added by the streamFunc
+	started     bool
+}
+
+func (sw *streamWriter) start() {
+	if !sw.started {
+		copyHeader(sw.w.Header(), sw.header)
+		sw.w.Header().Add("Content-Type", sw.contentType)
+		sw.w.WriteHeader(http.StatusOK)
+		sw.tee.Prefix(encodeHeader(sw.header))
+		sw.started = true
+	}
+}
+
+func (sw *streamWriter) Write(p []byte) (int, error) {
+	sw.start()
+	return sw.tee.Write(p)
+}
+
+// encodeHeader encodes header as in an HTTP message, ending with a blank line.
+func encodeHeader(header http.Header) []byte {
+	var buf bytes.Buffer
+	header.Write(&buf)
+	buf.WriteString("\r\n")
+	return buf.Bytes()
+}
+
+// decodeCached splits a cached value into the headers saved by encodeHeader and the body.
+func decodeCached(cached []byte) (http.Header, []byte, error) {
+	r := textproto.NewReader(bufio.NewReader(bytes.NewReader(cached)))
+	mime, err := r.ReadMIMEHeader()
+	if err != nil && !(err == io.EOF && len(mime) == 0) {
+		return nil, nil, fmt.Errorf("corrupt cache entry: %w", err)
+	}
+	body, err := io.ReadAll(r.R)
+	if err != nil {
+		return nil, nil, err
+	}
+	return http.Header(mime), body, nil
+}
+
+func copyHeader(dst, src http.Header) {
+	for k, values := range src {
+		for _, v := range values {
+			dst.Add(k, v)
+		}
+	}
+}
 
+// sendRespondError sends err with a status code that depends on its type.
+func sendRespondError(ctx context.Context, w http.ResponseWriter, err error) {
 	code := http.StatusInternalServerError
 	switch {
 	case errors.Is(err, sentinel.ErrMissingParams), errors.Is(err, sentinel.ErrInvalidParams):
 		code = http.StatusBadRequest
 	case errors.Is(err, sentinel.ErrTooManyMetrics):
 		code = http.StatusForbidden
-	case errors.Is(err, sentinel.ErrNotSupported):
+	case errors.Is(err, sentinel.ErrNotSupported), errors.Is(err, sentinel.ErrNotFound):
 		code = http.StatusNotFound
 	}
 	sendError(ctx, w, code, err.Error())
@@ -1,8 +1,19 @@
 package handlers
 
 import (
+	"errors"
+	"io"
 	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/ONSdigital/dp-find-insights-poc-api/api"
+	"github.com/ONSdigital/dp-find-insights-poc-api/cache"
+	"github.com/ONSdigital/dp-find-insights-poc-api/sentinel"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
 )
 
 func Test_noCache(t *testing.T) {
@@ -50,3 +61,233 @@ func Test_noCache(t *testing.T) {
 		}
 	}
 }
+
+func Test_respondStream(t *testing.T) {
+	cm, err := cache.New(5*time.Minute, 100, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	svr := &Server{cm: cm}
+
+	get := func(uri string, stream streamFunc) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", uri, nil)
+		rec := httptest.NewRecorder()
+		svr.respondStream(rec, req, mimeCSV, stream)
+		return rec
+	}
+
+	t.Run("streamed response is sent and cached", func(t *testing.T) {
+		calls := 0
+		stream := func(w io.Writer, header http.Header) error {
+			calls++
+			io.WriteString(w, "geography_code\n")
+			io.WriteString(w, "E01000001\n")
+			return nil
+		}
+
+		for i := 0; i < 2; i++ {
+			rec := get("/query2/2011?rows=E01000001", stream)
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, mimeCSV, rec.Header().Get("Content-Type"))
+			assert.Equal(t, "geography_code\nE01000001\n", rec.Body.String())
+		}
+		assert.Equal(t, 1, calls, "second response must come from cache")
+	})
+
+	t.Run("headers from stream are sent and cached", func(t *testing.T) {
+		calls := 0
+		stream := func(w io.Writer, header http.Header) error {
+			calls++
+			header.Set("X-Next-Cursor", "abc")
+			io.WriteString(w, "geography_code\n")
+			return nil
+		}
+
+		for i := 0; i < 2; i++ {
+			rec := get("/query2/2011?rows=ALL&limit=1", stream)
+			assert.Equal(t, "abc", rec.Header().Get("X-Next-Cursor"))
+			assert.Equal(t, mimeCSV, rec.Header().Get("Content-Type"))
+			assert.Equal(t, "geography_code\n", rec.Body.String())
+		}
+		assert.Equal(t, 1, calls, "second response must come from cache")
+	})
+
+	t.Run("empty body is cached", func(t *testing.T) {
+		calls := 0
+		stream := func(w io.Writer, header http.Header) error {
+			calls++
+			return nil
+		}
+
+		for i := 0; i < 2; i++ {
+			rec := get("/query2/2011?rows=none", stream)
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, 0, rec.Body.Len())
+		}
+		assert.Equal(t, 1, calls, "second response must come from cache")
+	})
+
+	t.Run("error before anything is written gets error status", func(t *testing.T) {
+		rec := get("/query2/2011?rows=bad", func(w io.Writer, header http.Header) error {
+			return sentinel.ErrInvalidParams
+		})
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("error after streaming starts aborts the response", func(t *testing.T) {
+		assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+			get("/query2/2011?rows=ALL", func(w io.Writer, header http.Header) error {
+				io.WriteString(w, "geography_code\n")
+				return errors.New("connection lost")
+			})
+		})
+	})
+
+	t.Run("big response is sent but not cached", func(t *testing.T) {
+		big := strings.Repeat("x", 2*1024*1024)
+		calls := 0
+		stream := func(w io.Writer, header http.Header) error {
+			calls++
+			_, err := io.WriteString(w, big)
+			return err
+		}
+
+		for i := 0; i < 2; i++ {
+			rec := get("/query2/2011?rows=ALL&cols=QS101EW0001", stream)
+			assert.Equal(t, len(big), rec.Body.Len())
+		}
+		assert.Equal(t, 2, calls, "big response must not be cached")
+	})
+}
+
+// blockingWriter is a ResponseWriter for a client that stalls until released.
+type blockingWriter struct {
+	*httptest.ResponseRecorder
+	writing chan struct{}
+	release chan struct{}
+}
+
+func (bw *blockingWriter) Write(p []byte) (int, error) {
+	close(bw.writing)
+	<-bw.release
+	return bw.ResponseRecorder.Write(p)
+}
+
+func Test_respondSlowClient(t *testing.T) {
+	cm, err := cache.New(5*time.Minute, 100, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	svr := &Server{cm: cm}
+
+	calls := 0
+	generate := func() ([]byte, error) {
+		calls++
+		return []byte("geography_code\nE01000001\n"), nil
+	}
+
+	// a client stalls while its response is written
+	slow := &blockingWriter{
+		ResponseRecorder: httptest.NewRecorder(),
+		writing:          make(chan struct{}),
+		release:          make(chan struct{}),
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		svr.respond(slow, httptest.NewRequest("GET", "/metadata/2011", nil), mimeJSON, generate)
+	}()
+	<-slow.writing
+
+	// an identical request is answered from the cache without waiting for it
+	rec := httptest.NewRecorder()
+	svr.respond(rec, httptest.NewRequest("GET", "/metadata/2011", nil), mimeJSON, generate)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, mimeJSON, rec.Header().Get("Content-Type"))
+	assert.Equal(t, "geography_code\nE01000001\n", rec.Body.String())
+	assert.Equal(t, 1, calls, "second response must come from cache")
+
+	close(slow.release)
+	<-done
+	assert.Equal(t, "geography_code\nE01000001\n", slow.Body.String())
+}
+
+func Test_respondStreamBusyKey(t *testing.T) {
+	cm, err := cache.New(5*time.Minute, 100, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	svr := &Server{cm: cm}
+
+	saved := streamLockWait
+	streamLockWait = 10 * time.Millisecond
+	defer func() { streamLockWait = saved }()
+
+	calls := 0
+	stream := func(w io.Writer, header http.Header) error {
+		calls++
+		io.WriteString(w, "geography_code\nE01000001\n")
+		return nil
+	}
+	get := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/query2/2011?rows=E01000001", nil)
+		rec := httptest.NewRecorder()
+		svr.respondStream(rec, req, mimeCSV, stream)
+		return rec
+	}
+
+	// another request holds the cache key, eg while streaming to a slow client
+	req := httptest.NewRequest("GET", "/query2/2011?rows=E01000001", nil)
+	busy := cm.AllocateEntry(cache.CacheKey(req, mimeCSV))
+	busy.Lock()
+
+	// a request for the same key gives up waiting and streams from the db
+	rec := get()
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "geography_code\nE01000001\n", rec.Body.String())
+	assert.Equal(t, 1, calls)
+
+	// without caching what it streamed, since it didn't hold the key
+	busy.Unlock()
+	busy.Free()
+	get()
+	assert.Equal(t, 2, calls, "response streamed without the key must not be cached")
+	get()
+	assert.Equal(t, 2, calls, "third response must come from cache")
+}
+
+func Test_IsStreamed(t *testing.T) {
+	// record what IsStreamed says after routing, without running the handlers
+	var streamed bool
+	record := func(h http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			streamed = IsStreamed(r)
+		}
+	}
+	router := api.HandlerWithOptions(&Server{}, api.ChiServerOptions{Middlewares: []api.MiddlewareFunc{record}})
+
+	var tests = map[string]bool{
+		"/query/2011":    true,
+		"/query2/2011":   true,
+		"/metadata/2011": false,
+		"/geo/2011":      false,
+	}
+	for path, want := range tests {
+		streamed = !want
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, want, streamed, path)
+	}
+
+	// every streamed route must be in the route table
+	routes := map[string]bool{}
+	err := chi.Walk(router.(chi.Routes), func(method, route string, handler http.Handler, middlewares ...func(http.Handler) http.Handler) error {
+		routes[route] = true
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for route := range streamedRoutes {
+		assert.True(t, routes[route], route)
+	}
+}
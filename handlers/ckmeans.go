@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"github.com/ONSdigital/dp-find-insights-poc-api/api"
+	"github.com/ONSdigital/dp-find-insights-poc-api/pkg/geodata"
 	"github.com/ONSdigital/dp-find-insights-poc-api/sentinel"
 )
 
@@ -13,6 +14,12 @@ func (svr *Server) GetCkmeansYear(w http.ResponseWriter, r *http.Request, year i
 		return
 	}
 
+	version, err := svr.queryVersion(r, year, params.Version)
+	if err != nil {
+		sendRespondError(r.Context(), w, err)
+		return
+	}
+
 	generate := func() ([]byte, error) {
 		var cat, geotype []string
 		var divideBy string
@@ -33,15 +40,136 @@ func (svr *Server) GetCkmeansYear(w http.ResponseWriter, r *http.Request, year i
 			return nil, fmt.Errorf("%w: cat, geotype and k required", sentinel.ErrMissingParams)
 		}
 
+		sel := querySelection(params.Rows, params.Bbox, params.Location, params.Radius, params.Polygon, params.Within)
+
+		ctx := r.Context()
+		breaks, err := svr.querygeodata.Breaks(ctx, year, version, sel, cat, geotype, geodata.ClassifyCkmeans, k, divideBy)
+		if err != nil {
+			return nil, err
+		}
+		return toJSON(breaks)
+	}
+
+	svr.respond(w, r, mimeJSON, generate, version)
+}
+
+func (svr *Server) GetBreaks(w http.ResponseWriter, r *http.Request, year int, params api.GetBreaksParams) {
+	if !svr.assertAuthorized(w, r) || !svr.assertDatabaseEnabled(w, r) {
+		return
+	}
+
+	version, err := svr.queryVersion(r, year, params.Version)
+	if err != nil {
+		sendRespondError(r.Context(), w, err)
+		return
+	}
+
+	generate := func() ([]byte, error) {
+		var cat, geotype []string
+		var divideBy, method string
+		var k int
+		if params.Cat != nil {
+			cat = *params.Cat
+		}
+		if params.Geotype != nil {
+			geotype = *params.Geotype
+		}
+		if params.K != nil {
+			k = *params.K
+		}
+		if params.DivideBy != nil {
+			divideBy = *params.DivideBy
+		}
+		if params.Method != nil {
+			method = *params.Method
+		}
+		if cat == nil || geotype == nil || k == 0 {
+			return nil, fmt.Errorf("%w: cat, geotype and k required", sentinel.ErrMissingParams)
+		}
+
+		sel := querySelection(params.Rows, params.Bbox, params.Location, params.Radius, params.Polygon, params.Within)
+
 		ctx := r.Context()
-		breaks, err := svr.querygeodata.CKmeans(ctx, year, cat, geotype, k, divideBy)
+		breaks, err := svr.querygeodata.Breaks(ctx, year, version, sel, cat, geotype, method, k, divideBy)
 		if err != nil {
 			return nil, err
 		}
 		return toJSON(breaks)
 	}
 
-	svr.respond(w, r, mimeJSON, generate)
+	svr.respond(w, r, mimeJSON, generate, version)
+}
+
+func (svr *Server) GetDistribution(w http.ResponseWriter, r *http.Request, year int, params api.GetDistributionParams) {
+	if !svr.assertAuthorized(w, r) || !svr.assertDatabaseEnabled(w, r) {
+		return
+	}
+
+	version, err := svr.queryVersion(r, year, params.Version)
+	if err != nil {
+		sendRespondError(r.Context(), w, err)
+		return
+	}
+
+	generate := func() ([]byte, error) {
+		var cat, geotype, breaks []string
+		var divideBy string
+		var bins int
+		if params.Cat != nil {
+			cat = *params.Cat
+		}
+		if params.Geotype != nil {
+			geotype = *params.Geotype
+		}
+		if params.Bins != nil {
+			bins = *params.Bins
+		}
+		if params.Breaks != nil {
+			breaks = *params.Breaks
+		}
+		if params.DivideBy != nil {
+			divideBy = *params.DivideBy
+		}
+		if cat == nil || geotype == nil {
+			return nil, fmt.Errorf("%w: cat and geotype required", sentinel.ErrMissingParams)
+		}
+
+		sel := querySelection(params.Rows, params.Bbox, params.Location, params.Radius, params.Polygon, params.Within)
+
+		ctx := r.Context()
+		dists, err := svr.querygeodata.Distribution(ctx, year, version, sel, cat, geotype, bins, breaks, divideBy)
+		if err != nil {
+			return nil, err
+		}
+		return toJSON(dists)
+	}
+
+	svr.respond(w, r, mimeJSON, generate, version)
+}
+
+// querySelection collects /query2 style geography selectors into a geodata.Selection.
+// The selectors are part of the request URI, so each selection is cached separately.
+func querySelection(rows *[]string, bbox, location *string, radius *int, polygon *string, within *[]string) geodata.Selection {
+	var sel geodata.Selection
+	if rows != nil {
+		sel.Geos = *rows
+	}
+	if bbox != nil {
+		sel.BBox = *bbox
+	}
+	if location != nil {
+		sel.Location = *location
+	}
+	if radius != nil {
+		sel.Radius = *radius
+	}
+	if polygon != nil {
+		sel.Polygon = *polygon
+	}
+	if within != nil {
+		sel.Within = *within
+	}
+	return sel
 }
 
 // !!!! DEPRECATED CKMEANSRATIO TO BE REMOVED WHEN FRONT END REMOVES DEPENDENCY ON IT !!!!
@@ -51,6 +179,12 @@ func (svr *Server) GetCkmeansratioYear(w http.ResponseWriter, r *http.Request, y
 		return
 	}
 
+	version, err := svr.queryVersion(r, year, params.Version)
+	if err != nil {
+		sendRespondError(r.Context(), w, err)
+		return
+	}
+
 	generate := func() ([]byte, error) {
 		var cat1, cat2, geotype string
 		var k int
@@ -71,12 +205,12 @@ func (svr *Server) GetCkmeansratioYear(w http.ResponseWriter, r *http.Request, y
 		}
 
 		ctx := r.Context()
-		breaks, err := svr.querygeodata.CKmeansRatio(ctx, year, cat1, cat2, geotype, k)
+		breaks, err := svr.querygeodata.CKmeansRatio(ctx, year, version, cat1, cat2, geotype, k)
 		if err != nil {
 			return nil, err
 		}
 		return toJSON(breaks)
 	}
 
-	svr.respond(w, r, mimeJSON, generate)
+	svr.respond(w, r, mimeJSON, generate, version)
 }
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ONSdigital/dp-find-insights-poc-api/pkg/table"
+	"github.com/ONSdigital/dp-find-insights-poc-api/sentinel"
+)
+
+// tableFormats are the formats any table.Table can be generated in.
+var tableFormats = []table.Format{table.FormatCSV, table.FormatJSON, table.FormatNDJSON}
+
+// query2Formats are the formats /query2 can produce; it can add boundaries to its results.
+var query2Formats = []table.Format{table.FormatCSV, table.FormatJSON, table.FormatNDJSON, table.FormatGeoJSON}
+
+// formatMime is the Content-Type sent for each table format.
+var formatMime = map[table.Format]string{
+	table.FormatCSV:     mimeCSV,
+	table.FormatJSON:    mimeJSON,
+	table.FormatNDJSON:  mimeNDJSON,
+	table.FormatGeoJSON: mimeGeoJSON,
+}
+
+// acceptFormat maps media types found in Accept headers to table formats.
+var acceptFormat = map[string]table.Format{
+	mimeCSV:                 table.FormatCSV,
+	mimeJSON:                table.FormatJSON,
+	mimeNDJSON:              table.FormatNDJSON,
+	"application/ndjson":    table.FormatNDJSON,
+	"application/jsonl":     table.FormatNDJSON,
+	"application/x-jsonl":   table.FormatNDJSON,
+	"application/jsonlines": table.FormatNDJSON,
+	mimeGeoJSON:             table.FormatGeoJSON,
+}
+
+// queryFormat chooses the output format and Content-Type for a /query or /query2 request.
+// allowed lists the formats the endpoint can produce.
+//
+// An explicit format= parameter wins, and is an error if it isn't allowed. Otherwise the
+// most preferred allowed media type in the Accept header is used.
+// CSV is the default, so clients that send no Accept header, or only types we can't
+// produce (eg a browser's text/html), still get CSV as before.
+func queryFormat(r *http.Request, param *string, allowed []table.Format) (table.Format, string, error) {
+	if param != nil && *param != "" {
+		format, err := table.ParseFormat(*param)
+		if err != nil {
+			return "", "", err
+		}
+		if !isAllowed(format, allowed) {
+			return "", "", fmt.Errorf("%w: format %q is not available from this endpoint", sentinel.ErrInvalidParams, format)
+		}
+		return format, formatMime[format], nil
+	}
+
+	format := negotiateFormat(r.Header.Values("Accept"), allowed)
+	return format, formatMime[format], nil
+}
+
+// negotiateFormat picks the allowed format with the highest q value from Accept header values.
+// Ties go to the type listed first. Wildcards are ignored, since they leave the choice to us
+// and we would pick CSV anyway.
+func negotiateFormat(accept []string, allowed []table.Format) table.Format {
+	best := table.FormatCSV
+	bestq := 0.0
+	for _, value := range accept {
+		for _, mediaRange := range strings.Split(value, ",") {
+			mediatype, params, err := mime.ParseMediaType(mediaRange)
+			if err != nil {
+				continue
+			}
+			format, ok := acceptFormat[mediatype]
+			if !ok || !isAllowed(format, allowed) {
+				continue
+			}
+			q := 1.0
+			if s, ok := params["q"]; ok {
+				q, err = strconv.ParseFloat(s, 64)
+				if err != nil {
+					continue
+				}
+			}
+			if q > bestq {
+				best = format
+				bestq = q
+			}
+		}
+	}
+	return best
+}
+
+func isAllowed(format table.Format, allowed []table.Format) bool {
+	for _, f := range allowed {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/ONSdigital/dp-find-insights-poc-api/pkg/table"
+	"github.com/ONSdigital/dp-find-insights-poc-api/sentinel"
+)
+
+func Test_queryFormat(t *testing.T) {
+	strp := func(s string) *string { return &s }
+
+	var tests = map[string]struct {
+		accept   []string
+		param    *string
+		allowed  []table.Format
+		want     table.Format
+		wantMime string
+		wantErr  error
+	}{
+		"no Accept header or format param": {
+			want:     table.FormatCSV,
+			wantMime: mimeCSV,
+		},
+		"empty format param": {
+			param:    strp(""),
+			want:     table.FormatCSV,
+			wantMime: mimeCSV,
+		},
+		"browser Accept header": {
+			accept:   []string{"text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8"},
+			want:     table.FormatCSV,
+			wantMime: mimeCSV,
+		},
+		"Accept json": {
+			accept:   []string{"application/json"},
+			want:     table.FormatJSON,
+			wantMime: mimeJSON,
+		},
+		"Accept ndjson": {
+			accept:   []string{"application/x-ndjson"},
+			want:     table.FormatNDJSON,
+			wantMime: mimeNDJSON,
+		},
+		"Accept ndjson alias": {
+			accept:   []string{"application/jsonl"},
+			want:     table.FormatNDJSON,
+			wantMime: mimeNDJSON,
+		},
+		"Accept highest q wins": {
+			accept:   []string{"text/csv;q=0.5, application/json;q=0.9"},
+			want:     table.FormatJSON,
+			wantMime: mimeJSON,
+		},
+		"Accept ties go to first listed": {
+			accept:   []string{"application/x-ndjson, application/json"},
+			want:     table.FormatNDJSON,
+			wantMime: mimeNDJSON,
+		},
+		"Accept q=0 means not acceptable": {
+			accept:   []string{"application/json;q=0"},
+			want:     table.FormatCSV,
+			wantMime: mimeCSV,
+		},
+		"Accept over several headers": {
+			accept:   []string{"text/csv;q=0.1", "application/json"},
+			want:     table.FormatJSON,
+			wantMime: mimeJSON,
+		},
+		"format param overrides Accept": {
+			accept:   []string{"application/json"},
+			param:    strp("ndjson"),
+			want:     table.FormatNDJSON,
+			wantMime: mimeNDJSON,
+		},
+		"unrecognised format param": {
+			param:   strp("xml"),
+			wantErr: sentinel.ErrInvalidParams,
+		},
+		"geojson format param where allowed": {
+			param:    strp("geojson"),
+			allowed:  query2Formats,
+			want:     table.FormatGeoJSON,
+			wantMime: mimeGeoJSON,
+		},
+		"geojson format param where not allowed": {
+			param:   strp("geojson"),
+			wantErr: sentinel.ErrInvalidParams,
+		},
+		"Accept geojson where allowed": {
+			accept:   []string{"application/geo+json"},
+			allowed:  query2Formats,
+			want:     table.FormatGeoJSON,
+			wantMime: mimeGeoJSON,
+		},
+		"Accept geojson where not allowed falls back": {
+			accept:   []string{"application/geo+json, application/json;q=0.5"},
+			want:     table.FormatJSON,
+			wantMime: mimeJSON,
+		},
+	}
+
+	for name, test := range tests {
+		if test.allowed == nil {
+			test.allowed = tableFormats
+		}
+		req := &http.Request{Header: http.Header{}}
+		for _, accept := range test.accept {
+			req.Header.Add("Accept", accept)
+		}
+
+		got, gotMime, err := queryFormat(req, test.param, test.allowed)
+		if !errors.Is(err, test.wantErr) {
+			t.Errorf("%s: error %v, want %v", name, err, test.wantErr)
+			continue
+		}
+		if got != test.want || gotMime != test.wantMime {
+			t.Errorf("%s: %q %q, want %q %q", name, got, gotMime, test.want, test.wantMime)
+		}
+	}
+}
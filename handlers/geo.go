@@ -24,8 +24,15 @@ func (svr *Server) GetGeo(w http.ResponseWriter, r *http.Request, year int, para
 		return
 	}
 
+	lang, err := queryLanguage(r, params.Lang)
+	if err != nil {
+		sendRespondError(r.Context(), w, err)
+		return
+	}
+	setLanguage(w, lang)
+
 	generate := func() ([]byte, error) {
-		resp, err := svr.querygeodata.Geo(r.Context(), year, geocode, geoname)
+		resp, err := svr.querygeodata.Geo(r.Context(), year, geocode, geoname, lang)
 		if err != nil {
 			return nil, err
 		}
@@ -36,5 +43,93 @@ func (svr *Server) GetGeo(w http.ResponseWriter, r *http.Request, year int, para
 		return []byte(buf), err
 	}
 
+	svr.respond(w, r, mimeJSON, generate, lang)
+}
+
+func (svr *Server) GetGeoChildren(w http.ResponseWriter, r *http.Request, year int, geocode string, params api.GetGeoChildrenParams) {
+	if !svr.assertAuthorized(w, r) || !svr.assertDatabaseEnabled(w, r) {
+		return
+	}
+
+	generate := func() ([]byte, error) {
+		var geotype string
+		if params.Geotype != nil {
+			geotype = *params.Geotype
+		}
+		children, err := svr.querygeodata.Children(r.Context(), geocode, geotype)
+		if err != nil {
+			return nil, err
+		}
+		return toJSON(children)
+	}
+
 	svr.respond(w, r, mimeJSON, generate)
 }
+
+func (svr *Server) GetGeoAncestors(w http.ResponseWriter, r *http.Request, year int, geocode string) {
+	if !svr.assertAuthorized(w, r) || !svr.assertDatabaseEnabled(w, r) {
+		return
+	}
+
+	generate := func() ([]byte, error) {
+		ancestors, err := svr.querygeodata.Ancestors(r.Context(), geocode)
+		if err != nil {
+			return nil, err
+		}
+		return toJSON(ancestors)
+	}
+
+	svr.respond(w, r, mimeJSON, generate)
+}
+
+func (svr *Server) GetLocate(w http.ResponseWriter, r *http.Request, year int, params api.GetLocateParams) {
+	if !svr.assertAuthorized(w, r) || !svr.assertDatabaseEnabled(w, r) {
+		return
+	}
+
+	generate := func() ([]byte, error) {
+		areas, err := svr.querygeodata.Locate(r.Context(), params.Location)
+		if err != nil {
+			return nil, err
+		}
+		return toJSON(areas)
+	}
+
+	svr.respond(w, r, mimeJSON, generate)
+}
+
+func (svr *Server) GetSearch(w http.ResponseWriter, r *http.Request, year int, params api.GetSearchParams) {
+	if !svr.assertAuthorized(w, r) || !svr.assertDatabaseEnabled(w, r) {
+		return
+	}
+
+	lang, err := queryLanguage(r, params.Lang)
+	if err != nil {
+		sendRespondError(r.Context(), w, err)
+		return
+	}
+	setLanguage(w, lang)
+
+	generate := func() ([]byte, error) {
+		var geotype []string
+		var bbox string
+		var limit int
+		if params.Geotype != nil {
+			geotype = *params.Geotype
+		}
+		if params.Bbox != nil {
+			bbox = *params.Bbox
+		}
+		if params.Limit != nil {
+			limit = *params.Limit
+		}
+
+		results, err := svr.querygeodata.Search(r.Context(), params.Q, geotype, bbox, limit, lang)
+		if err != nil {
+			return nil, err
+		}
+		return toJSON(results)
+	}
+
+	svr.respond(w, r, mimeJSON, generate, lang)
+}
@@ -3,8 +3,11 @@ package handlers
 import (
 	"bytes"
 	"encoding/csv"
+	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
 
 	"github.com/ONSdigital/dp-find-insights-poc-api/api"
 	"github.com/ONSdigital/dp-find-insights-poc-api/pkg/geodata"
@@ -18,7 +21,28 @@ func (svr *Server) GetQuery(w http.ResponseWriter, r *http.Request, year int, pa
 		return
 	}
 
-	generate := func() ([]byte, error) {
+	// only 2011 data comes from postgres; years in CANT_YEARS come from cantabular, which has no data versions
+	var version string
+	if year == 2011 {
+		var err error
+		version, err = svr.queryVersion(r, year, params.Version)
+		if err != nil {
+			sendRespondError(r.Context(), w, err)
+			return
+		}
+	} else if !svr.querygeodata.CantabularYear(year) {
+		sendRespondError(r.Context(), w, fmt.Errorf("%w: no data for %d", sentinel.ErrNotFound, year))
+		return
+	}
+
+	format, contentType, err := queryFormat(r, params.Format, query2Formats)
+	if err != nil {
+		sendError(r.Context(), w, http.StatusBadRequest, err.Error())
+		return
+	}
+	w.Header().Set("Vary", "Accept")
+
+	stream := func(w io.Writer, header http.Header) error {
 		var rows []string
 		var bbox string
 		var geotype []string
@@ -44,9 +68,28 @@ func (svr *Server) GetQuery(w http.ResponseWriter, r *http.Request, year int, pa
 			polygon = *params.Polygon
 		}
 
-		geocodes, err := svr.querygeodata.Query2(r.Context(), year, bbox, location, radius, polygon, geotype, rows)
+		var within []string
+		if params.Within != nil {
+			within = *params.Within
+		}
+
+		var cursor string
+		var limit int
+		if params.Cursor != nil {
+			cursor = *params.Cursor
+		}
+		if params.Limit != nil {
+			limit = *params.Limit
+		}
+
+		geocodes, next, err := svr.querygeodata.Query2(r.Context(), year, bbox, location, radius, polygon, geotype, rows, within, cursor, limit)
 		if err != nil {
-			return nil, err
+			return err
+		}
+		if next != "" {
+			header.Set("Link", fmt.Sprintf("<%s>; rel=\"next\"", nextPageURL(r.URL, next)))
+			header.Set("X-Next-Cursor", next)
+			header.Set("Access-Control-Expose-Headers", "Link, X-Next-Cursor")
 		}
 
 		var cols []string
@@ -61,35 +104,83 @@ func (svr *Server) GetQuery(w http.ResponseWriter, r *http.Request, year int, pa
 		// parse cols query strings into a ValueSet
 		catset, err := where.ParseMultiArgs(cols)
 		if err != nil {
-			return nil, err
+			return err
 		}
 
 		// extract special column names from ValueSet
 		include, catset, err := geodata.ExtractSpecialCols(catset)
 		if err != nil {
-			return nil, err
+			return err
+		}
+
+		var derived geodata.Derived
+		if params.DivideBy != nil {
+			derived.DivideBy = *params.DivideBy
+		}
+		if params.PercentOf != nil {
+			derived.PercentOf = *params.PercentOf
 		}
 
 		// special case for dev: explicit cols="geocode" and no census table means just print geocodes column
 		// (would just allow cols=geography_code, but that already means all columns)
 		if len(catset.Singles) == 0 && len(catset.Ranges) == 0 && len(include) == 1 && include[0] == table.ColGeocodes && censustable == "" {
-			return geocodeCSV(geocodes)
+			body, err := geocodeList(geocodes, format)
+			return writeAll(w, body, err)
 		}
 
-		if year == 2011 {
-			return svr.querygeodata.PGMetrics(r.Context(), year, geocodes, catset, include, censustable)
-		}
-		if len(geotype) != 1 {
-			return nil, fmt.Errorf("%w: cantabular queries require a single geotype", sentinel.ErrInvalidParams)
+		if format == table.FormatGeoJSON {
+			if year != 2011 {
+				return fmt.Errorf("%w: geojson output is only available for 2011", sentinel.ErrInvalidParams)
+			}
+			var simplify float64
+			if params.Simplify != nil {
+				simplify = *params.Simplify
+			}
+			body, err := svr.querygeodata.PGMetricsGeoJSON(r.Context(), year, version, geocodes, catset, censustable, derived, simplify)
+			return writeAll(w, body, err)
 		}
 
-		return svr.querygeodata.CantabularMetrics(r.Context(), geocodes, catset, geotype[0])
+		if year == 2011 {
+			return svr.querygeodata.PGMetricsStream(r.Context(), w, year, version, geocodes, catset, include, censustable, derived, format)
+		}
+		body, err := svr.querygeodata.CantabularMetrics(r.Context(), geocodes, catset, include, censustable, derived, format)
+		return writeAll(w, body, err)
 	}
 
-	svr.respond(w, r, mimeCSV, generate)
+	svr.respondStream(w, r, contentType, stream, version)
 }
 
-func geocodeCSV(geocodes []string) ([]byte, error) {
+// nextPageURL returns the relative URL of the page after the one requested in u.
+func nextPageURL(u *url.URL, cursor string) string {
+	q := u.Query()
+	q.Set("cursor", cursor)
+	next := url.URL{Path: u.Path, RawQuery: q.Encode()}
+	return next.String()
+}
+
+// geocodeList generates the bare list of geocodes in format.
+// CSV has a single geocode column, JSON is an array of strings, and NDJSON
+// is one string per line.
+func geocodeList(geocodes []string, format table.Format) ([]byte, error) {
+	switch format {
+	case table.FormatGeoJSON:
+		return nil, fmt.Errorf("%w: geojson output needs at least one category in cols", sentinel.ErrInvalidParams)
+	case table.FormatJSON:
+		if geocodes == nil {
+			geocodes = []string{}
+		}
+		return json.Marshal(geocodes)
+	case table.FormatNDJSON:
+		var body bytes.Buffer
+		enc := json.NewEncoder(&body)
+		for _, geocode := range geocodes {
+			if err := enc.Encode(geocode); err != nil {
+				return nil, err
+			}
+		}
+		return body.Bytes(), nil
+	}
+
 	var body bytes.Buffer
 	cw := csv.NewWriter(&body)
 	cw.Write([]string{"geocode"})
@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_nextPageURL(t *testing.T) {
+	u, err := url.Parse("/query2/2011?rows=ALL&geotype=LSOA&cols=QS101EW0001&limit=100&cursor=old")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := url.Parse(nextPageURL(u, "new"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "/query2/2011", got.Path)
+	assert.Equal(t, url.Values{
+		"rows":    {"ALL"},
+		"geotype": {"LSOA"},
+		"cols":    {"QS101EW0001"},
+		"limit":   {"100"},
+		"cursor":  {"new"},
+	}, got.Query())
+}
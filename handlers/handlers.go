@@ -5,6 +5,7 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"io"
 	"net/http"
 
 	"github.com/ONSdigital/dp-find-insights-poc-api/api"
@@ -69,6 +70,13 @@ func (svr *Server) GetMetadataYear(w http.ResponseWriter, r *http.Request, year
 		return
 	}
 
+	lang, err := queryLanguage(r, params.Lang)
+	if err != nil {
+		sendRespondError(r.Context(), w, err)
+		return
+	}
+	setLanguage(w, lang)
+
 	generate := func() ([]byte, error) {
 		var filtertotals bool
 		if params.Filtertotals != nil {
@@ -77,10 +85,14 @@ func (svr *Server) GetMetadataYear(w http.ResponseWriter, r *http.Request, year
 			filtertotals = false
 		}
 
-		return svr.md.Get(r.Context(), year, filtertotals)
+		// only 2011 metadata is in postgres; years in CANT_YEARS come from cantabular
+		if year != 2011 {
+			return svr.querygeodata.CantabularMetadata(r.Context(), year, filtertotals, lang)
+		}
+		return svr.md.Get(r.Context(), year, filtertotals, lang)
 	}
 
-	svr.respond(w, r, mimeCSV, generate)
+	svr.respond(w, r, mimeCSV, generate, lang)
 }
 
 func (svr *Server) GetMsoaPostcode(w http.ResponseWriter, r *http.Request, pc string) {
@@ -90,19 +102,49 @@ func (svr *Server) GetMsoaPostcode(w http.ResponseWriter, r *http.Request, pc st
 		if err != nil {
 			return nil, err
 		}
-		// JSON?
+		// see GetPostcode for JSON with every geography
 		return []byte(code + ", " + name + "\r\n"), nil
 	}
 
 	svr.respond(w, r, mimeCSV, generate)
 }
 
+func (svr *Server) GetPostcode(w http.ResponseWriter, r *http.Request, pc string) {
+	if svr.pc == nil {
+		sendError(r.Context(), w, http.StatusNotImplemented, "database not enabled")
+		return
+	}
+
+	generate := func() ([]byte, error) {
+		lookup, err := svr.pc.Get(pc)
+		if err != nil {
+			return nil, err
+		}
+		return toJSON(lookup)
+	}
+
+	svr.respond(w, r, mimeJSON, generate)
+}
+
 func (svr *Server) GetQueryYear(w http.ResponseWriter, r *http.Request, year int, params api.GetQueryYearParams) {
 	if !svr.assertAuthorized(w, r) || !svr.assertDatabaseEnabled(w, r) {
 		return
 	}
 
-	generate := func() ([]byte, error) {
+	version, err := svr.queryVersion(r, year, params.Version)
+	if err != nil {
+		sendRespondError(r.Context(), w, err)
+		return
+	}
+
+	format, contentType, err := queryFormat(r, params.Format, tableFormats)
+	if err != nil {
+		sendError(r.Context(), w, http.StatusBadRequest, err.Error())
+		return
+	}
+	w.Header().Set("Vary", "Accept")
+
+	stream := func(w io.Writer, header http.Header) error {
 		var rows []string
 		var cols []string
 		var bbox string
@@ -137,11 +179,10 @@ func (svr *Server) GetQueryYear(w http.ResponseWriter, r *http.Request, year int
 		}
 
 		ctx := r.Context()
-		csv, err := svr.querygeodata.Query(ctx, year, bbox, location, radius, polygon, geotype, rows, cols, censustable)
-		return []byte(csv), err
+		return svr.querygeodata.QueryStream(ctx, w, year, version, bbox, location, radius, polygon, geotype, rows, cols, censustable, format)
 	}
 
-	svr.respond(w, r, mimeCSV, generate)
+	svr.respondStream(w, r, contentType, stream, version)
 }
 
 func (svr *Server) GetClearCache(w http.ResponseWriter, r *http.Request) {
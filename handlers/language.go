@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ONSdigital/dp-find-insights-poc-api/model"
+	"github.com/ONSdigital/dp-find-insights-poc-api/sentinel"
+)
+
+// languages are the languages names can be returned in.
+var languages = []string{model.LangEnglish, model.LangWelsh}
+
+// queryLanguage chooses the language of names in a /metadata, /geo or /search response.
+//
+// An explicit lang= parameter wins, and is an error if it isn't a language we have.
+// Otherwise the most preferred language in the Accept-Language header is used.
+// English is the default.
+func queryLanguage(r *http.Request, param *string) (string, error) {
+	if param != nil && *param != "" {
+		lang := strings.ToLower(*param)
+		if !isLanguage(lang) {
+			return "", fmt.Errorf("%w: lang must be one of %s", sentinel.ErrInvalidParams, strings.Join(languages, ", "))
+		}
+		return lang, nil
+	}
+	return negotiateLanguage(r.Header.Values("Accept-Language")), nil
+}
+
+// negotiateLanguage picks the language with the highest q value from Accept-Language header values.
+// Only the primary subtag is compared, so cy-GB is Welsh. Ties go to the language listed first.
+// Wildcards are ignored, since they leave the choice to us and we would pick English anyway.
+func negotiateLanguage(accept []string) string {
+	best := model.LangEnglish
+	bestq := 0.0
+	for _, value := range accept {
+		for _, languageRange := range strings.Split(value, ",") {
+			fields := strings.Split(languageRange, ";")
+			tag := strings.ToLower(strings.TrimSpace(fields[0]))
+			if i := strings.IndexByte(tag, '-'); i >= 0 {
+				tag = tag[:i]
+			}
+			if !isLanguage(tag) {
+				continue
+			}
+			q := 1.0
+			for _, param := range fields[1:] {
+				param = strings.TrimSpace(param)
+				if !strings.HasPrefix(param, "q=") {
+					continue
+				}
+				var err error
+				q, err = strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64)
+				if err != nil {
+					q = 0
+				}
+			}
+			if q > bestq {
+				best = tag
+				bestq = q
+			}
+		}
+	}
+	return best
+}
+
+// setLanguage sets the headers telling clients and caches which language a response is in,
+// and that it depends on Accept-Language.
+func setLanguage(w http.ResponseWriter, lang string) {
+	w.Header().Set("Content-Language", lang)
+	w.Header().Add("Vary", "Accept-Language")
+}
+
+func isLanguage(lang string) bool {
+	for _, l := range languages {
+		if l == lang {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/ONSdigital/dp-find-insights-poc-api/sentinel"
+)
+
+func Test_queryLanguage(t *testing.T) {
+	strp := func(s string) *string { return &s }
+
+	var tests = map[string]struct {
+		accept  []string
+		param   *string
+		want    string
+		wantErr error
+	}{
+		"no Accept-Language header or lang param": {
+			want: "en",
+		},
+		"empty lang param": {
+			param: strp(""),
+			want:  "en",
+		},
+		"lang param": {
+			param: strp("cy"),
+			want:  "cy",
+		},
+		"lang param wins": {
+			accept: []string{"cy"},
+			param:  strp("en"),
+			want:   "en",
+		},
+		"lang param is case insensitive": {
+			param: strp("CY"),
+			want:  "cy",
+		},
+		"unknown lang param": {
+			param:   strp("fr"),
+			wantErr: sentinel.ErrInvalidParams,
+		},
+		"Accept-Language cy": {
+			accept: []string{"cy"},
+			want:   "cy",
+		},
+		"Accept-Language with region": {
+			accept: []string{"cy-GB,en-GB;q=0.8"},
+			want:   "cy",
+		},
+		"Accept-Language prefers English": {
+			accept: []string{"cy;q=0.5, en;q=0.9"},
+			want:   "en",
+		},
+		"Accept-Language without languages we have": {
+			accept: []string{"fr-FR,de;q=0.7,*;q=0.5"},
+			want:   "en",
+		},
+		"Accept-Language cy not acceptable": {
+			accept: []string{"fr,cy;q=0"},
+			want:   "en",
+		},
+		"multiple Accept-Language headers": {
+			accept: []string{"fr", "cy;q=0.9"},
+			want:   "cy",
+		},
+	}
+
+	for name, test := range tests {
+		req := &http.Request{Header: http.Header{}}
+		for _, accept := range test.accept {
+			req.Header.Add("Accept-Language", accept)
+		}
+
+		got, err := queryLanguage(req, test.param)
+		if !errors.Is(err, test.wantErr) {
+			t.Errorf("%s: error %v, want %v", name, err, test.wantErr)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("%s: got %q, want %q", name, got, test.want)
+		}
+	}
+}
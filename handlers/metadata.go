@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/ONSdigital/dp-find-insights-poc-api/api"
+)
+
+func (svr *Server) GetMetadataTable(w http.ResponseWriter, r *http.Request, year int, code string, params api.GetMetadataTableParams) {
+	if !svr.assertAuthorized(w, r) || !svr.assertDatabaseEnabled(w, r) {
+		return
+	}
+
+	lang, err := queryLanguage(r, params.Lang)
+	if err != nil {
+		sendRespondError(r.Context(), w, err)
+		return
+	}
+	setLanguage(w, lang)
+
+	generate := func() ([]byte, error) {
+		return svr.md.Table(r.Context(), year, code, lang)
+	}
+
+	svr.respond(w, r, mimeJSON, generate, lang)
+}
+
+func (svr *Server) GetMetadataCategory(w http.ResponseWriter, r *http.Request, year int, code string, params api.GetMetadataCategoryParams) {
+	if !svr.assertAuthorized(w, r) || !svr.assertDatabaseEnabled(w, r) {
+		return
+	}
+
+	lang, err := queryLanguage(r, params.Lang)
+	if err != nil {
+		sendRespondError(r.Context(), w, err)
+		return
+	}
+	setLanguage(w, lang)
+
+	generate := func() ([]byte, error) {
+		return svr.md.Category(r.Context(), year, code, lang)
+	}
+
+	svr.respond(w, r, mimeJSON, generate, lang)
+}
+
+func (svr *Server) GetMetadataSearch(w http.ResponseWriter, r *http.Request, year int, params api.GetMetadataSearchParams) {
+	if !svr.assertAuthorized(w, r) || !svr.assertDatabaseEnabled(w, r) {
+		return
+	}
+
+	lang, err := queryLanguage(r, params.Lang)
+	if err != nil {
+		sendRespondError(r.Context(), w, err)
+		return
+	}
+	setLanguage(w, lang)
+
+	generate := func() ([]byte, error) {
+		var limit int
+		if params.Limit != nil {
+			limit = *params.Limit
+		}
+		return svr.md.Search(r.Context(), year, params.Q, limit, lang)
+	}
+
+	svr.respond(w, r, mimeJSON, generate, lang)
+}
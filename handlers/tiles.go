@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/ONSdigital/dp-find-insights-poc-api/api"
+)
+
+func (svr *Server) GetTile(w http.ResponseWriter, r *http.Request, year int, geotype string, z int, x int, y int, params api.GetTileParams) {
+	if !svr.assertAuthorized(w, r) || !svr.assertDatabaseEnabled(w, r) {
+		return
+	}
+
+	version, err := svr.queryVersion(r, year, params.Version)
+	if err != nil {
+		sendRespondError(r.Context(), w, err)
+		return
+	}
+
+	generate := func() ([]byte, error) {
+		var cols []string
+		if params.Cols != nil {
+			cols = *params.Cols
+		}
+		return svr.querygeodata.Tile(r.Context(), year, version, geotype, z, x, y, cols)
+	}
+
+	svr.respond(w, r, mimeMVT, generate, version)
+}
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/ONSdigital/dp-find-insights-poc-api/config"
+)
+
+const (
+	// cache variants for /versions, since the list depends on who is asking
+	versionsPublic  = "public"
+	versionsPrivate = "private"
+)
+
+func (svr *Server) GetVersions(w http.ResponseWriter, r *http.Request) {
+	if !svr.assertAuthorized(w, r) || !svr.assertDatabaseEnabled(w, r) {
+		return
+	}
+
+	private := hasAPIToken(r)
+	variant := versionsPublic
+	if private {
+		variant = versionsPrivate
+	}
+
+	generate := func() ([]byte, error) {
+		versions, err := svr.querygeodata.Versions(r.Context(), private)
+		if err != nil {
+			return nil, err
+		}
+		return toJSON(versions)
+	}
+
+	svr.respond(w, r, mimeJSON, generate, variant)
+}
+
+// queryVersion resolves the version query parameter to the data version to use for year.
+// No version means the latest public version.
+// Non-public versions are only found for callers presenting the API token.
+//
+// The version is resolved before the response cache is consulted, so cached
+// responses for non-public versions are never served to other callers, and a new
+// latest version takes effect immediately.
+//
+func (svr *Server) queryVersion(r *http.Request, year int, param *string) (string, error) {
+	var version string
+	if param != nil {
+		version = *param
+	}
+	return svr.querygeodata.ResolveVersion(r.Context(), year, version, hasAPIToken(r))
+}
+
+// hasAPIToken is true if the request's Authorization header holds the API token.
+// Unlike assertAuthorized, it is false if there is no API token configured.
+func hasAPIToken(r *http.Request) bool {
+	c, _ := config.Get()
+	return c.APIToken != "" && r.Header.Get("Authorization") == c.APIToken
+}
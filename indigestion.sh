@@ -18,6 +18,8 @@ cd dataingest/geoname && go run .
 cd ../spatial && ./lad2011ish.sh && go build ./geo2sql.go && ./import.sh
 cd longlatgeom  && go run .    
 cd ../../postcode  && go run . 
+cd ../hierarchy  && go run .
+cd ../breaks  && go run .
 delta=$((SECONDS-otime))
 echo "about" $((delta/60)) "min(s) elapsed"
 cd ../../dataingest && make test
@@ -3,7 +3,6 @@ package metadata
 import (
 	"context"
 	"encoding/json"
-	"strings"
 
 	"github.com/ONSdigital/dp-find-insights-poc-api/api"
 	"github.com/ONSdigital/dp-find-insights-poc-api/model"
@@ -34,15 +33,30 @@ func New(dbs ...*gorm.DB) (*Metadata, error) {
 	return &Metadata{gdb: dbs[0]}, err
 }
 
-func (md *Metadata) Get(ctx context.Context, year int, filterTotals bool) ([]byte, error) {
+// Get returns the topics, tables and categories for year as JSON.
+// Names are in lang (model.LangEnglish or model.LangWelsh), falling back to English
+// where there is no translation. Slugs are always made from the English names.
+//
+// The whole tree is loaded with one query per level (topics, tables, categories)
+// and assembled in memory.
+//
+func (md *Metadata) Get(ctx context.Context, year int, filterTotals bool, lang string) ([]byte, error) {
 	var topics []model.NomisTopic
 
-	md.gdb.Preload(
+	err := md.gdb.WithContext(ctx).Preload(
 		"NomisDescs",
 		func(gdb *gorm.DB) *gorm.DB {
 			return gdb.Order("short_nomis_code").Where("year = ?", year)
 		},
-	).Find(&topics)
+	).Preload(
+		"NomisDescs.NomisCategories",
+		func(gdb *gorm.DB) *gorm.DB {
+			return gdb.Order("long_nomis_code").Where("year = ?", year)
+		},
+	).Find(&topics).Error
+	if err != nil {
+		return nil, err
+	}
 
 	var mdr api.MetadataResponse
 
@@ -54,27 +68,19 @@ func (md *Metadata) Get(ctx context.Context, year int, filterTotals bool) ([]byt
 		}
 
 		var newTabs api.Tables
-		var nd model.NomisDesc
-
-		for _, nd = range topic.NomisDescs {
-			md.gdb.Preload(
-				"NomisCategories",
-				func(gdb *gorm.DB) *gorm.DB {
-					return gdb.Order("long_nomis_code").Where("year = ?", year)
-				},
-			).Find(&nd)
 
+		for _, nd := range topic.NomisDescs {
 			// partially populate table here to allow optional inclusion of Total if filterTotals == true
 			table := api.Table{
-				Name: spointer(nd.Name),
+				Name: spointer(model.Localise(lang, nd.Name, nd.WelshName)),
 				Slug: spointer(slug.Make(nd.Name)),
 				Code: spointer(nd.ShortNomisCode),
 			}
 
 			var cats api.Categories
 			for _, trip := range nd.NomisCategories {
-				cat := api.Triplet{Code: spointer(trip.LongNomisCode), Name: spointer(trip.CategoryName), Slug: spointer(slug.Make(trip.CategoryName))}
-				if filterTotals && isTotalCat(trip.LongNomisCode) {
+				cat := api.Triplet{Code: spointer(trip.LongNomisCode), Name: spointer(model.Localise(lang, trip.CategoryName, trip.WelshName)), Slug: spointer(slug.Make(trip.CategoryName))}
+				if filterTotals && model.IsTotalCat(trip.LongNomisCode) {
 					table.Total = &cat
 				} else {
 					cats = append(cats, cat)
@@ -86,7 +92,7 @@ func (md *Metadata) Get(ctx context.Context, year int, filterTotals bool) ([]byt
 
 		mdr = append(mdr, api.Metadata{
 			Code:   spointer(topic.TopNomisCode),
-			Name:   spointer(topic.Name),
+			Name:   spointer(model.Localise(lang, topic.Name, topic.WelshName)),
 			Slug:   spointer(slug.Make(topic.Name)),
 			Tables: &newTabs,
 		})
@@ -121,7 +127,3 @@ func (md *Metadata) Checker(ctx context.Context, state *healthcheck.CheckState)
 	conn.Close()
 	return nil
 }
-
-func isTotalCat(catName string) bool {
-	return strings.HasSuffix(catName, "0001")
-}
@@ -5,11 +5,13 @@ package metadata
 
 import (
 	"context"
+	"errors"
 	"log"
 	"testing"
 
 	"github.com/ONSdigital/dp-find-insights-poc-api/comptests"
 	"github.com/ONSdigital/dp-find-insights-poc-api/model"
+	"github.com/ONSdigital/dp-find-insights-poc-api/sentinel"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
@@ -48,7 +50,7 @@ func TestMetaDataTest(t *testing.T) {
 		md, _ := New(tx)
 
 		filterTotals := false
-		b, err := md.Get(context.Background(), 2011, filterTotals)
+		b, err := md.Get(context.Background(), 2011, filterTotals, model.LangEnglish)
 		if err != nil {
 			t.Error(err)
 		}
@@ -81,7 +83,7 @@ func TestMetaDataFiltertotals(t *testing.T) {
 		md, _ := New(tx)
 
 		filterTotals := true
-		b, err := md.Get(context.Background(), 2011, filterTotals)
+		b, err := md.Get(context.Background(), 2011, filterTotals, model.LangEnglish)
 		if err != nil {
 			t.Error(err)
 		}
@@ -96,3 +98,62 @@ func TestMetaDataFiltertotals(t *testing.T) {
 func resultFilterTotals() string {
 	return `[{"code":"QS1","name":"Population Basics","slug":"population-basics","tables":[{"categories":[{"code":"QS118EW0002","name":"foo blah etc","slug":"foo-blah-etc"}],"code":"QS118EW","name":"Families with dependent children","slug":"families-with-dependent-children","total":{"code":"QS118EW0001","name":"All categories: Dependent children in family","slug":"all-categories-dependent-children-in-family"}}]}]`
 }
+
+func TestMetaDataTable(t *testing.T) {
+	// inside transaction rolled back
+	func() {
+		tx := db.Begin()
+		defer tx.Rollback()
+
+		tx.Exec("INSERT INTO NOMIS_DESC (id,name,pop_stat,short_nomis_code,year,nomis_topic_id) VALUES (15,'Families with dependent children','All families in households','QS118EW',2011,1)")
+		tx.Exec("INSERT INTO NOMIS_CATEGORY (id,nomis_desc_id,category_name,measurement_unit,stat_unit,long_nomis_code,year) VALUES (211,15,'All categories: Dependent children in family','Count','Family','QS118EW0001',2011)")
+
+		md, _ := New(tx)
+
+		b, err := md.Table(context.Background(), 2011, "QS118EW", model.LangEnglish)
+		if err != nil {
+			t.Error(err)
+		}
+		if string(b) != resultTable() {
+			println(string(b))
+			t.Fail()
+		}
+
+		b, err = md.Category(context.Background(), 2011, "QS118EW0001", model.LangEnglish)
+		if err != nil {
+			t.Error(err)
+		}
+		if string(b) != resultCategory() {
+			println(string(b))
+			t.Fail()
+		}
+
+		_, err = md.Table(context.Background(), 2011, "QS999EW", model.LangEnglish)
+		if !errors.Is(err, sentinel.ErrNotFound) {
+			t.Errorf("unknown table: got %v", err)
+		}
+
+		tx.Exec("INSERT INTO NOMIS_CATEGORY (id,nomis_desc_id,category_name,measurement_unit,stat_unit,long_nomis_code,year) VALUES (212,15,'foo blah etc','Count','Family','QS118EW0002',2011)")
+
+		b, err = md.Search(context.Background(), 2011, "blah", 0, model.LangEnglish)
+		if err != nil {
+			t.Error(err)
+		}
+		if string(b) != resultSearch() {
+			println(string(b))
+			t.Fail()
+		}
+	}()
+}
+
+func resultTable() string {
+	return `{"categories":[{"code":"QS118EW0001","measurement_unit":"Count","name":"All categories: Dependent children in family","slug":"all-categories-dependent-children-in-family","stat_unit":"Family"}],"code":"QS118EW","name":"Families with dependent children","pop_stat":"All families in households","slug":"families-with-dependent-children","topic":{"code":"QS1","name":"Population Basics","slug":"population-basics"}}`
+}
+
+func resultCategory() string {
+	return `{"code":"QS118EW0001","measurement_unit":"Count","name":"All categories: Dependent children in family","slug":"all-categories-dependent-children-in-family","stat_unit":"Family","table":{"code":"QS118EW","name":"Families with dependent children","pop_stat":"All families in households","slug":"families-with-dependent-children","topic":{"code":"QS1","name":"Population Basics","slug":"population-basics"}}}`
+}
+
+func resultSearch() string {
+	return `[{"code":"QS118EW0002","name":"foo blah etc","slug":"foo-blah-etc","table_code":"QS118EW","type":"category"}]`
+}
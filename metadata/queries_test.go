@@ -0,0 +1,89 @@
+package metadata
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ONSdigital/dp-find-insights-poc-api/model"
+	"github.com/ONSdigital/dp-find-insights-poc-api/pkg/database"
+	"github.com/cockroachdb/copyist"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// wantGetQueries is the number of queries Get should make, however many
+// topics and tables there are: one each for topics, tables and categories.
+const wantGetQueries = 3
+
+func init() {
+	copyist.Register("postgres")
+}
+
+// recordedMetadata returns a Metadata using the recorded DB responses, and a
+// pointer to a count of the queries it makes.
+// "go test -v ../metadata -run TestGetQueries -record" to create new
+//
+func recordedMetadata(tb testing.TB) (*Metadata, *int) {
+	dsn := database.GetDSN() + "?sslmode=disable"
+
+	gdb, err := gorm.Open(postgres.New(postgres.Config{DriverName: "copyist_postgres", DSN: dsn}), &gorm.Config{})
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	var queries int
+	err = gdb.Callback().Query().After("gorm:query").Register("count_queries", func(*gorm.DB) {
+		queries++
+	})
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	md, err := New(gdb)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return md, &queries
+}
+
+func TestGetQueries(t *testing.T) {
+	defer copyist.Open(t).Close()
+
+	md, queries := recordedMetadata(t)
+
+	b, err := md.Get(context.Background(), 2011, true, model.LangEnglish)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if *queries != wantGetQueries {
+		t.Errorf("got %d queries, want %d", *queries, wantGetQueries)
+	}
+
+	if string(b) != resultGetQueries() {
+		println(string(b))
+		t.Fail()
+	}
+}
+
+func BenchmarkGet(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		func() {
+			defer copyist.OpenNamed(b, "testdata/queries_test.copyist", "TestGetQueries").Close()
+
+			md, queries := recordedMetadata(b)
+
+			if _, err := md.Get(context.Background(), 2011, true, model.LangEnglish); err != nil {
+				b.Fatal(err)
+			}
+
+			if *queries != wantGetQueries {
+				b.Fatalf("got %d queries, want %d", *queries, wantGetQueries)
+			}
+		}()
+	}
+}
+
+func resultGetQueries() string {
+	return `[{"code":"QS1","name":"Population Basics","slug":"population-basics","tables":[{"categories":[{"code":"QS101EW0002","name":"Lives in a household","slug":"lives-in-a-household"},{"code":"QS101EW0003","name":"Lives in a communal establishment","slug":"lives-in-a-communal-establishment"}],"code":"QS101EW","name":"Residence type","slug":"residence-type","total":{"code":"QS101EW0001","name":"All categories: Residence type","slug":"all-categories-residence-type"}},{"categories":[{"code":"QS118EW0002","name":"No dependent children in family","slug":"no-dependent-children-in-family"}],"code":"QS118EW","name":"Families with dependent children","slug":"families-with-dependent-children","total":{"code":"QS118EW0001","name":"All categories: Dependent children in family","slug":"all-categories-dependent-children-in-family"}}]},{"code":"QS2","name":"Origins \u0026 Beliefs","slug":"origins-and-beliefs","tables":[{"categories":[{"code":"QS210EW0002","name":"Christian","slug":"christian"}],"code":"QS210EW","name":"Religion (detailed)","slug":"religion-detailed","total":{"code":"QS210EW0001","name":"All categories: Religion","slug":"all-categories-religion"}}]}]`
+}
@@ -0,0 +1,122 @@
+package metadata
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ONSdigital/dp-find-insights-poc-api/api"
+	"github.com/ONSdigital/dp-find-insights-poc-api/model"
+	"github.com/ONSdigital/dp-find-insights-poc-api/sentinel"
+	"github.com/gosimple/slug"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+)
+
+// searchSQL full text searches table and category names.
+// English names are stemmed (so "households" matches "Household"); Welsh names
+// are matched word for word since Postgres has no Welsh dictionary.
+// Tables come before categories of equal rank.
+//
+const searchSQL = `
+WITH query AS (
+    SELECT plainto_tsquery('english', @q) AS english, plainto_tsquery('simple', @q) AS simple
+)
+SELECT type, code, name, welsh_name, table_code
+FROM (
+    SELECT
+        'table' AS type,
+        nomis_desc.short_nomis_code AS code,
+        nomis_desc.name,
+        nomis_desc.welsh_name,
+        nomis_desc.short_nomis_code AS table_code,
+        GREATEST(
+            ts_rank(to_tsvector('english', nomis_desc.name), query.english),
+            ts_rank(to_tsvector('simple', nomis_desc.welsh_name), query.simple)
+        ) AS rank
+    FROM nomis_desc, query
+    WHERE nomis_desc.year = @year
+    AND (
+        to_tsvector('english', nomis_desc.name) @@ query.english
+        OR to_tsvector('simple', nomis_desc.welsh_name) @@ query.simple
+    )
+    UNION ALL
+    SELECT
+        'category' AS type,
+        nomis_category.long_nomis_code AS code,
+        nomis_category.category_name AS name,
+        nomis_category.welsh_name,
+        nomis_desc.short_nomis_code AS table_code,
+        GREATEST(
+            ts_rank(to_tsvector('english', nomis_category.category_name), query.english),
+            ts_rank(to_tsvector('simple', nomis_category.welsh_name), query.simple)
+        ) AS rank
+    FROM nomis_category
+    JOIN nomis_desc ON nomis_desc.id = nomis_category.nomis_desc_id, query
+    WHERE nomis_category.year = @year
+    AND (
+        to_tsvector('english', nomis_category.category_name) @@ query.english
+        OR to_tsvector('simple', nomis_category.welsh_name) @@ query.simple
+    )
+) results
+ORDER BY rank DESC, type DESC, code
+LIMIT @limit
+`
+
+type searchRow struct {
+	Type      string
+	Code      string
+	Name      string
+	WelshName string
+	TableCode string
+}
+
+// Search returns the tables and categories whose names match q as JSON, best first.
+// limit is the most results to return; zero means the default of 20.
+//
+func (md *Metadata) Search(ctx context.Context, year int, q string, limit int, lang string) ([]byte, error) {
+	q, limit, err := searchArgs(q, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []searchRow
+	err = md.gdb.WithContext(ctx).Raw(
+		searchSQL,
+		map[string]interface{}{"q": q, "year": year, "limit": limit},
+	).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	results := api.MetadataSearchResults{}
+	for _, row := range rows {
+		results = append(results, api.MetadataSearchResult{
+			Type:      spointer(row.Type),
+			Code:      spointer(row.Code),
+			Name:      spointer(model.Localise(lang, row.Name, row.WelshName)),
+			Slug:      spointer(slug.Make(row.Name)),
+			TableCode: spointer(row.TableCode),
+		})
+	}
+
+	return marshal(ctx, results)
+}
+
+// searchArgs validates the search text and limit, and applies the default limit.
+func searchArgs(q string, limit int) (string, int, error) {
+	q = strings.TrimSpace(q)
+	if q == "" {
+		return "", 0, fmt.Errorf("%w: q", sentinel.ErrMissingParams)
+	}
+	if limit < 0 || limit > maxSearchLimit {
+		return "", 0, fmt.Errorf("%w: limit must be 1..%d", sentinel.ErrInvalidParams, maxSearchLimit)
+	}
+	if limit == 0 {
+		limit = defaultSearchLimit
+	}
+	return q, limit, nil
+}
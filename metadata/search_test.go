@@ -0,0 +1,62 @@
+package metadata
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ONSdigital/dp-find-insights-poc-api/sentinel"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchArgs(t *testing.T) {
+	var tests = map[string]struct {
+		q         string
+		limit     int
+		wantQ     string
+		wantLimit int
+		wantErr   error
+	}{
+		"empty q": {
+			q:       "",
+			wantErr: sentinel.ErrMissingParams,
+		},
+		"blank q": {
+			q:       "   ",
+			wantErr: sentinel.ErrMissingParams,
+		},
+		"negative limit": {
+			q:       "travel",
+			limit:   -1,
+			wantErr: sentinel.ErrInvalidParams,
+		},
+		"limit too big": {
+			q:       "travel",
+			limit:   maxSearchLimit + 1,
+			wantErr: sentinel.ErrInvalidParams,
+		},
+		"default limit": {
+			q:         " travel to work ",
+			wantQ:     "travel to work",
+			wantLimit: defaultSearchLimit,
+		},
+		"explicit limit": {
+			q:         "household",
+			limit:     5,
+			wantQ:     "household",
+			wantLimit: 5,
+		},
+	}
+
+	for name, test := range tests {
+		q, limit, err := searchArgs(test.q, test.limit)
+		if !errors.Is(err, test.wantErr) {
+			t.Errorf("%s: got error %v, want %v", name, err, test.wantErr)
+			continue
+		}
+		if test.wantErr != nil {
+			continue
+		}
+		assert.Equal(t, test.wantQ, q, name)
+		assert.Equal(t, test.wantLimit, limit, name)
+	}
+}
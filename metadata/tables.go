@@ -0,0 +1,120 @@
+package metadata
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ONSdigital/dp-find-insights-poc-api/api"
+	"github.com/ONSdigital/dp-find-insights-poc-api/model"
+	"github.com/ONSdigital/dp-find-insights-poc-api/sentinel"
+	"github.com/ONSdigital/log.go/v2/log"
+	"github.com/gosimple/slug"
+	"gorm.io/gorm"
+)
+
+// Table returns the metadata for the table with code as JSON, including its topic,
+// population statistic and the units of each of its categories.
+//
+func (md *Metadata) Table(ctx context.Context, year int, code, lang string) ([]byte, error) {
+	var nd model.NomisDesc
+	res := md.gdb.WithContext(ctx).Preload(
+		"NomisCategories",
+		func(gdb *gorm.DB) *gorm.DB {
+			return gdb.Order("long_nomis_code").Where("year = ?", year)
+		},
+	).Where("year = ? AND short_nomis_code = ?", year, code).Limit(1).Find(&nd)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil, fmt.Errorf("%w: table %s", sentinel.ErrNotFound, code)
+	}
+
+	table, err := md.tableMetadata(ctx, nd, lang)
+	if err != nil {
+		return nil, err
+	}
+
+	var cats []api.CategoryMetadata
+	for _, cat := range nd.NomisCategories {
+		cats = append(cats, categoryMetadata(cat, lang))
+	}
+	table.Categories = &cats
+
+	return marshal(ctx, table)
+}
+
+// Category returns the metadata for the category with code as JSON, including
+// the table it belongs to.
+//
+func (md *Metadata) Category(ctx context.Context, year int, code, lang string) ([]byte, error) {
+	var cat model.NomisCategory
+	res := md.gdb.WithContext(ctx).Where("year = ? AND long_nomis_code = ?", year, code).Limit(1).Find(&cat)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil, fmt.Errorf("%w: category %s", sentinel.ErrNotFound, code)
+	}
+
+	var nd model.NomisDesc
+	if err := md.gdb.WithContext(ctx).Where("id = ?", cat.NomisDescID).Limit(1).Find(&nd).Error; err != nil {
+		return nil, err
+	}
+
+	table, err := md.tableMetadata(ctx, nd, lang)
+	if err != nil {
+		return nil, err
+	}
+
+	result := categoryMetadata(cat, lang)
+	result.Table = &table
+
+	return marshal(ctx, result)
+}
+
+// tableMetadata fills in everything about nd except its categories.
+func (md *Metadata) tableMetadata(ctx context.Context, nd model.NomisDesc, lang string) (api.TableMetadata, error) {
+	table := api.TableMetadata{
+		Code:    spointer(nd.ShortNomisCode),
+		Name:    spointer(model.Localise(lang, nd.Name, nd.WelshName)),
+		Slug:    spointer(slug.Make(nd.Name)),
+		PopStat: spointer(nd.PopStat),
+	}
+
+	var topic model.NomisTopic
+	res := md.gdb.WithContext(ctx).Where("id = ?", nd.NomisTopicID).Limit(1).Find(&topic)
+	if res.Error != nil {
+		return table, res.Error
+	}
+	// id 0 is the dummy topic used on import
+	if res.RowsAffected != 0 && topic.ID != 0 {
+		table.Topic = &api.Triplet{
+			Code: spointer(topic.TopNomisCode),
+			Name: spointer(model.Localise(lang, topic.Name, topic.WelshName)),
+			Slug: spointer(slug.Make(topic.Name)),
+		}
+	}
+
+	return table, nil
+}
+
+func categoryMetadata(cat model.NomisCategory, lang string) api.CategoryMetadata {
+	return api.CategoryMetadata{
+		Code:            spointer(cat.LongNomisCode),
+		Name:            spointer(model.Localise(lang, cat.CategoryName, cat.WelshName)),
+		Slug:            spointer(slug.Make(cat.CategoryName)),
+		MeasurementUnit: spointer(cat.MeasurementUnit),
+		StatUnit:        spointer(cat.StatUnit),
+	}
+}
+
+func marshal(ctx context.Context, v interface{}) ([]byte, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		log.Error(ctx, "json marshal", err)
+		return nil, err
+	}
+	return b, nil
+}
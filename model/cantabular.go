@@ -0,0 +1,54 @@
+package model
+
+// initialCantVariables is the census table to Cantabular variable mapping worked out by hand
+// for the syn2011 instance, "matching" via cmd/cantabular output and eg.
+// SELECT  nd.name,nc.* FROM nomis_desc nd, nomis_category nc where nd.short_nomis_code='KS103EW' and nd.id=nc.nomis_desc_id and nc.measurement_unit='Count' and nc.long_nomis_code not like '%0001';
+//
+// It seeds cant_variable; later changes are made in the database.
+//
+func initialCantVariables() []CantVariable {
+	const (
+		usualResidents   = "Usual-Residents"
+		peopleHouseholds = "People-Households"
+	)
+
+	return []CantVariable{
+		{ShortNomisCode: "KS102EW", Dataset: usualResidents, Variable: "AGE_T009A"},
+		{ShortNomisCode: "KS103EW", Dataset: usualResidents, Variable: "MARSTAT_T006A"},
+		{ShortNomisCode: "KS202EW", Dataset: usualResidents, Variable: "NATID_ALL_T009A"},
+		{ShortNomisCode: "KS206EW", Dataset: peopleHouseholds, Variable: "WELSHPUK112_T007A"},
+		{ShortNomisCode: "KS207WA", Dataset: usualResidents, Variable: "WELSHPUK112_R003A"},
+		{ShortNomisCode: "KS208WA", Dataset: usualResidents, Variable: "WELSHPUK112_R003A"},
+		{ShortNomisCode: "QS101EW", Dataset: usualResidents, Variable: "RESIDTYPE"},
+		{ShortNomisCode: "QS104EW", Dataset: usualResidents, Variable: "SEX"},
+		{ShortNomisCode: "QS201EW", Dataset: usualResidents, Variable: "ETHPUK11_T009A"},
+		{ShortNomisCode: "QS203EW", Dataset: usualResidents, Variable: "COB_R010A"},
+		{ShortNomisCode: "QS208EW", Dataset: usualResidents, Variable: "RELIGIONEW"},
+		{ShortNomisCode: "QS301EW", Dataset: usualResidents, Variable: "CARER"},
+		{ShortNomisCode: "QS302EW", Dataset: usualResidents, Variable: "HEALTH_T004A"},
+		{ShortNomisCode: "QS303EW", Dataset: usualResidents, Variable: "DISABILITY_T003B"},
+		{ShortNomisCode: "QS402EW", Dataset: peopleHouseholds, Variable: "TYPACCOM_T009A"},
+		{ShortNomisCode: "QS403EW", Dataset: peopleHouseholds, Variable: "TENHUK11_T010A"},
+		{ShortNomisCode: "QS406EW", Dataset: peopleHouseholds, Variable: "SIZHUK11_T007A"},
+		{ShortNomisCode: "QS415EW", Dataset: peopleHouseholds, Variable: "CENHEATHUK11_T003A"},
+		{ShortNomisCode: "QS416EW", Dataset: peopleHouseholds, Variable: "CARSNO_T004A"},
+		{ShortNomisCode: "QS501EW", Dataset: usualResidents, Variable: "HLQPUK11_T007A"},
+		{ShortNomisCode: "QS601EW", Dataset: usualResidents, Variable: "ECOPUK11_R006A"},
+		{ShortNomisCode: "QS604EW", Dataset: usualResidents, Variable: "HOURS"},
+		{ShortNomisCode: "QS605EW", Dataset: usualResidents, Variable: "INDGPUK11_T009A"},
+		{ShortNomisCode: "QS606EW", Dataset: usualResidents, Variable: "OCCPUK113_T010A"},
+		{ShortNomisCode: "QS701EW", Dataset: usualResidents, Variable: "TRANSPORT_R005A"},
+		{ShortNomisCode: "QS702EW", Dataset: usualResidents, Variable: "AGGDTWPEW11_R010A"},
+	}
+}
+
+// initialCantGeotypes is the geotype to Cantabular geography variable mapping for the syn2011 instance.
+// It seeds cant_geotype.
+func initialCantGeotypes() []CantGeotype {
+	return []CantGeotype{
+		{Geotype: "Country", Variable: "Country"},
+		{Geotype: "Region", Variable: "Region"},
+		{Geotype: "LAD", Variable: "LA"},
+		{Geotype: "MSOA", Variable: "MSOA"},
+	}
+}
@@ -2,7 +2,9 @@ package model
 
 import (
 	"database/sql"
+	"strings"
 
+	"github.com/lib/pq"
 	"gorm.io/gorm"
 )
 
@@ -85,6 +87,20 @@ func (Geo) TableName() string {
 	return "geo"
 }
 
+// GeoParent links a geography to the geography directly above it in the hierarchy
+// LSOA -> MSOA -> LAD -> Region -> Country -> EW.
+// Welsh LADs have no region, so their parent is Wales.
+type GeoParent struct {
+	ID       int32 `gorm:"primaryKey"`
+	GeoID    int32 `gorm:"uniqueIndex"`
+	ParentID int32 `gorm:"index"`
+}
+
+// don't pluralise table name
+func (GeoParent) TableName() string {
+	return "geo_parent"
+}
+
 type GeoMetric struct {
 	ID         int32 `gorm:"primaryKey"`
 	GeoID      int32 `gorm:"index"`
@@ -98,11 +114,30 @@ func (GeoMetric) TableName() string {
 	return "geo_metric"
 }
 
+// Breaks holds ckmeans breaks precomputed over all the geographies of a geotype,
+// so /ckmeans doesn't have to scan geo_metric. It is populated by dataingest/breaks.
+type Breaks struct {
+	ID         int32           `gorm:"primaryKey"`
+	DataVerID  int32           `gorm:"uniqueIndex:breaks_key"`
+	CategoryID int32           `gorm:"uniqueIndex:breaks_key"`
+	DivideByID int32           `gorm:"uniqueIndex:breaks_key"` // category divided by, or 0 for raw values
+	TypeID     int32           `gorm:"uniqueIndex:breaks_key"` // geotype
+	K          int32           `gorm:"uniqueIndex:breaks_key"`
+	Breaks     pq.Float64Array `gorm:"type:double precision[]"`
+	MinMax     pq.Float64Array `gorm:"type:double precision[]"`
+}
+
+// don't pluralise table name
+func (Breaks) TableName() string {
+	return "breaks"
+}
+
 type NomisCategory struct {
 	// why do we need uniqueIndex? composite key!
 	ID              int32 `gorm:"uniqueIndex;primaryKey"`
 	NomisDescID     int32 `gorm:"primaryKey"`
 	CategoryName    string
+	WelshName       string // empty if there is no translation
 	MeasurementUnit string
 	StatUnit        string
 	LongNomisCode   string `gorm:"uniqueIndex"`
@@ -115,10 +150,26 @@ func (NomisCategory) TableName() string {
 	return "nomis_category"
 }
 
+// IsTotalCat is true if catcode is the total category of its table.
+// Total categories are the ones ending in 0001, eg QS101EW0001.
+func IsTotalCat(catcode string) bool {
+	return strings.HasSuffix(catcode, "0001")
+}
+
+// TotalCat returns the code of the total category in catcode's table,
+// eg QS101EW0001 for QS101EW0003.
+func TotalCat(catcode string) string {
+	if len(catcode) < 4 {
+		return ""
+	}
+	return catcode[:len(catcode)-4] + "0001"
+}
+
 type NomisDesc struct {
 	ID              int32 `gorm:"uniqueIndex;primaryKey"`
 	NomisTopicID    int32 `gorm:"primaryKey"`
 	Name            string
+	WelshName       string // empty if there is no translation
 	PopStat         string
 	ShortNomisCode  string `gorm:"uniqueIndex"`
 	Year            int32
@@ -134,6 +185,7 @@ type NomisTopic struct {
 	ID           int32 `gorm:"primaryKey"`
 	TopNomisCode string
 	Name         string
+	WelshName    string      // empty if there is no translation
 	NomisDescs   []NomisDesc `gorm:"foreignKey:NomisTopicID;references:ID"`
 }
 
@@ -142,11 +194,44 @@ func (NomisTopic) TableName() string {
 	return "nomis_topic"
 }
 
+// CantVariable maps a census table to the Cantabular variable standing in for it, and the
+// dataset holding that variable.
+// Cantabular variables are not NOMIS tables, so the match is approximate; cmd/cantabular -discover
+// suggests and writes candidates.
+type CantVariable struct {
+	ID             int32  `gorm:"primaryKey"`
+	ShortNomisCode string `gorm:"uniqueIndex"`
+	Dataset        string
+	Variable       string
+}
+
+// don't pluralise table name
+func (CantVariable) TableName() string {
+	return "cant_variable"
+}
+
+// CantGeotype maps a geotype to the Cantabular variable whose categories are its geographies.
+type CantGeotype struct {
+	ID       int32  `gorm:"primaryKey"`
+	Geotype  string `gorm:"uniqueIndex"` // geo_type.name
+	Variable string
+}
+
+// don't pluralise table name
+func (CantGeotype) TableName() string {
+	return "cant_geotype"
+}
+
 type PostCode struct {
-	ID    int32 `gorm:"primaryKey"`
-	GeoID int32 `gorm:"index"`
-	Pcds  string
-	Geo   Geo // XXX
+	ID     int32   `gorm:"primaryKey"`
+	GeoID  int32   `gorm:"index"` // MSOA
+	Pcds   string  `gorm:"index"`
+	Geo    Geo     // XXX
+	Oa11cd string  // output areas aren't in geo, so only the code is kept
+	LsoaID int32   // geo.id of the LSOA
+	LadID  int32   // geo.id of the LAD, or 0 if the LAD code isn't in geo
+	Lat    float64 // centroid, or 0 if the source file has no coordinates
+	Long   float64
 }
 
 // don't pluralise table name
@@ -154,6 +239,21 @@ func (PostCode) TableName() string {
 	return "postcode"
 }
 
+// Languages that names can be returned in.
+const (
+	LangEnglish = "en"
+	LangWelsh   = "cy"
+)
+
+// Localise returns the welsh name if lang is LangWelsh and there is a translation,
+// otherwise the english name.
+func Localise(lang, english, welsh string) string {
+	if lang == LangWelsh && welsh != "" {
+		return welsh
+	}
+	return english
+}
+
 // data prepopulated in Postgres database
 
 // GetGeoTypeValues returns a slice of geo types
@@ -162,6 +262,26 @@ func GetGeoTypeValues() []string {
 	return []string{"EW", "Country", "Region", "LAD", "MSOA", "LSOA"}
 }
 
+// SmallestFirst reports whether an area of geotype1 with code1 comes before an area of geotype2
+// with code2, when areas are listed smallest geotype first.
+// Boundaries can overlap slightly, so areas of the same geotype are ordered by code.
+func SmallestFirst(geotype1, code1, geotype2, code2 string) bool {
+	if geotype1 != geotype2 {
+		return geoTypeRank(geotype1) > geoTypeRank(geotype2)
+	}
+	return code1 < code2
+}
+
+// geoTypeRank is the position of geotype in GetGeoTypeValues, from largest to smallest.
+func geoTypeRank(geotype string) int {
+	for i, v := range GetGeoTypeValues() {
+		if v == geotype {
+			return i
+		}
+	}
+	return -1
+}
+
 // GetGeoTypeValues returns a map of geo types for validation
 func GetGeoTypeMap() map[string]bool {
 	m := make(map[string]bool)
@@ -15,3 +15,56 @@ func TestGetGeoTypeMap(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func TestSmallestFirst(t *testing.T) {
+	if !SmallestFirst("LSOA", "E01000002", "MSOA", "E02000001") {
+		t.Error("LSOA comes before MSOA")
+	}
+	if SmallestFirst("Country", "E92000001", "LAD", "E09000001") {
+		t.Error("Country comes after LAD")
+	}
+	if !SmallestFirst("LSOA", "E01000001", "LSOA", "E01000002") {
+		t.Error("same geotype is ordered by code")
+	}
+}
+
+func TestIsTotalCat(t *testing.T) {
+	if !IsTotalCat("QS101EW0001") {
+		t.Error("QS101EW0001 is a total")
+	}
+	if IsTotalCat("QS101EW0002") {
+		t.Error("QS101EW0002 is not a total")
+	}
+}
+
+func TestTotalCat(t *testing.T) {
+	for catcode, want := range map[string]string{
+		"QS101EW0003": "QS101EW0001",
+		"QS101EW0001": "QS101EW0001",
+		"KS608EW0027": "KS608EW0001",
+		"abc":         "",
+	} {
+		if got := TotalCat(catcode); got != want {
+			t.Errorf("TotalCat(%q) = %q, want %q", catcode, got, want)
+		}
+	}
+}
+
+func TestLocalise(t *testing.T) {
+	tests := []struct {
+		lang    string
+		english string
+		welsh   string
+		want    string
+	}{
+		{LangEnglish, "Newport", "Casnewydd", "Newport"},
+		{LangWelsh, "Newport", "Casnewydd", "Casnewydd"},
+		{LangWelsh, "Bexley", "", "Bexley"},
+		{"", "Newport", "Casnewydd", "Newport"},
+	}
+	for _, test := range tests {
+		if got := Localise(test.lang, test.english, test.welsh); got != test.want {
+			t.Errorf("Localise(%q, %q, %q) = %q, want %q", test.lang, test.english, test.welsh, got, test.want)
+		}
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/lib/pq"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"gorm.io/gorm/logger"
 )
 
@@ -38,7 +39,10 @@ func SetupUpdateDB(dsn string) {
 			log.Fatal(err)
 		}
 
-		execSQL(gdb, []string{"CREATE EXTENSION IF NOT EXISTS postgis"})
+		execSQL(gdb, []string{
+			"CREATE EXTENSION IF NOT EXISTS postgis",
+			"CREATE EXTENSION IF NOT EXISTS pg_trgm", // for name search
+		})
 	}
 
 	{
@@ -103,6 +107,10 @@ func Migrate(db *gorm.DB) {
 		&NomisCategory{},
 		&GeoMetric{},
 		&YearMapping{},
+		&GeoParent{},
+		&Breaks{},
+		&CantVariable{},
+		&CantGeotype{},
 	); err != nil {
 		log.Fatal(err)
 	}
@@ -111,7 +119,13 @@ func Migrate(db *gorm.DB) {
 		"ALTER TABLE geo ADD COLUMN wkb_geometry geometry(Geometry,4326)",
 		"CREATE INDEX geo_wkb_geometry_geom_idx ON public.geo USING gist (wkb_geometry)",
 		"ALTER TABLE geo ADD COLUMN wkb_long_lat_geom geometry(Geometry,4326)",
-		"CREATE INDEX geo_long_lat_geom_idx ON public.geo USING gist ( wkb_long_lat_geom)"})
+		"CREATE INDEX geo_long_lat_geom_idx ON public.geo USING gist ( wkb_long_lat_geom)",
+		// trigram indexes for prefix and fuzzy name search
+		"CREATE INDEX IF NOT EXISTS geo_name_trgm_idx ON public.geo USING gin (name gin_trgm_ops)",
+		"CREATE INDEX IF NOT EXISTS geo_welsh_name_trgm_idx ON public.geo USING gin (welsh_name gin_trgm_ops)",
+		// full text indexes for metadata search
+		"CREATE INDEX IF NOT EXISTS nomis_desc_name_fts_idx ON public.nomis_desc USING gin (to_tsvector('english', name))",
+		"CREATE INDEX IF NOT EXISTS nomis_category_name_fts_idx ON public.nomis_category USING gin (to_tsvector('english', category_name))"})
 
 }
 
@@ -159,6 +173,16 @@ func DataPopulate(db *gorm.DB) {
 		"UPDATE nomis_desc SET nomis_topic_id=400 WHERE short_nomis_code LIKE 'KS4%'",
 	})
 
+	// Cantabular mappings found by hand before cmd/cantabular -discover existed
+	variables := initialCantVariables()
+	if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&variables).Error; err != nil {
+		log.Print(err)
+	}
+	geotypes := initialCantGeotypes()
+	if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&geotypes).Error; err != nil {
+		log.Print(err)
+	}
+
 }
 
 func execSQL(db *gorm.DB, ss []string) {
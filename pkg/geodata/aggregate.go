@@ -0,0 +1,263 @@
+package geodata
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ONSdigital/dp-find-insights-poc-api/pkg/timer"
+	"github.com/ONSdigital/dp-find-insights-poc-api/pkg/where"
+	"github.com/ONSdigital/dp-find-insights-poc-api/sentinel"
+	"github.com/ONSdigital/log.go/v2/log"
+)
+
+// Aggregation methods.
+const (
+	// AggregateWhole sums every area selected, as if it were wholly inside the selection.
+	// Areas are selected the same way as Query2.
+	AggregateWhole = "whole"
+
+	// AggregateApportion sums every area that intersects the selection, weighted by the
+	// fraction of the area's boundary inside the selection.
+	// Only bbox, location/radius and polygon selections have an intersection to weight by.
+	AggregateApportion = "apportion"
+)
+
+// AggregateResp is the result of an aggregate query.
+type AggregateResp struct {
+	Method   string             `json:"method"`
+	Geotype  string             `json:"geotype"`
+	Geocodes []string           `json:"geocodes"` // areas that contributed to the totals
+	Totals   map[string]float64 `json:"totals"`   // summed value of each category
+}
+
+// Aggregate sums metrics for each category over an area selected like Query2.
+// It gives one figure per category for a user-drawn area, instead of a row per geography.
+//
+// Only a single geotype can be aggregated, since summing eg LSOAs and the LADs
+// containing them would count people twice.
+//
+// method is AggregateWhole or AggregateApportion, with AggregateWhole the default.
+//
+func (app *Geodata) Aggregate(ctx context.Context, year int, version string, bbox, location string, radius int, polygon string, geotypes, geos []string, catset *where.ValueSet, censustable, method string) (*AggregateResp, error) {
+	if method == "" {
+		method = AggregateWhole
+	}
+	if len(geotypes) != 1 || strings.Contains(geotypes[0], ",") {
+		return nil, fmt.Errorf("%w: aggregates need a single geotype", sentinel.ErrInvalidParams)
+	}
+	geotype, err := FixGeotype(geotypes[0])
+	if err != nil {
+		return nil, err
+	}
+	if len(catset.Singles) == 0 && len(catset.Ranges) == 0 && censustable == "" {
+		return nil, fmt.Errorf("%w: aggregates need cols or censustable", sentinel.ErrMissingParams)
+	}
+
+	err = validateCensusQuery(
+		CensusQuerySQLArgs{
+			Year:     year,
+			Geos:     geos,
+			BBox:     bbox,
+			Location: location,
+			Radius:   radius,
+			Polygon:  polygon,
+			Geotypes: geotypes,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	sql, values, err := aggregateSQL(year, version, bbox, location, radius, polygon, geotype, geos, catset, censustable, method)
+	if err != nil {
+		return nil, err
+	}
+	log.Info(ctx, "sql", log.Data{"query": sql, "args": values})
+
+	t := timer.New("query")
+	t.Start()
+	rows, err := app.db.DB().QueryContext(ctx, sql, values...)
+	if err != nil {
+		return nil, err
+	}
+	t.Stop()
+	t.Log(ctx)
+	defer rows.Close()
+
+	resp := &AggregateResp{
+		Method:   method,
+		Geotype:  geotype,
+		Geocodes: []string{},
+		Totals:   map[string]float64{},
+	}
+	seen := map[string]bool{}
+	for rows.Next() {
+		var geo string
+		var cat string
+		var value float64
+		var weight float64
+		if err := rows.Scan(&geo, &cat, &value, &weight); err != nil {
+			return nil, err
+		}
+		if !seen[geo] {
+			seen[geo] = true
+			resp.Geocodes = append(resp.Geocodes, geo)
+		}
+		resp.Totals[cat] += value * weight
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.Strings(resp.Geocodes)
+	return resp, nil
+}
+
+// aggregateSQL generates the SQL for an aggregate query, and its bind parameters.
+// The query returns the geocode, category, value and weight of each metric to be summed.
+func aggregateSQL(year int, version string, bbox, location string, radius int, polygon, geotype string, geos []string, catset *where.ValueSet, censustable, method string) (string, []interface{}, error) {
+	qargs := where.NewArgs()
+
+	// areasSQL selects the id, code and weight of each area to be summed
+	var areasSQL string
+	switch method {
+	case AggregateWhole:
+		geoConditions, err := geoConditionsSQL(geos, bbox, location, radius, polygon, qargs)
+		if err != nil {
+			return "", nil, err
+		}
+		areasSQL = fmt.Sprintf(`
+	SELECT
+		geo.id,
+		geo.code,
+		1.0 AS weight
+	FROM
+		geo,
+		geo_type
+	WHERE geo.valid
+	AND geo_type.id = geo.type_id
+	AND geo_type.name = %s
+	%s`,
+			qargs.Add(geotype),
+			geoConditions,
+		)
+	case AggregateApportion:
+		shape, err := selectionShapeSQL(geos, bbox, location, radius, polygon, qargs)
+		if err != nil {
+			return "", nil, err
+		}
+		areasSQL = fmt.Sprintf(`
+	SELECT
+		geo.id,
+		geo.code,
+		COALESCE(
+			ST_Area(ST_Intersection(geo.wkb_geometry, shape.geom)::geography) /
+			NULLIF(ST_Area(geo.wkb_geometry::geography), 0),
+			0
+		) AS weight
+	FROM
+		geo,
+		geo_type,
+		(SELECT %s AS geom) AS shape
+	WHERE geo.valid
+	AND geo_type.id = geo.type_id
+	AND geo_type.name = %s
+	AND geo.wkb_geometry IS NOT NULL
+	AND ST_Intersects(geo.wkb_geometry, shape.geom)`,
+			shape,
+			qargs.Add(geotype),
+		)
+	default:
+		return "", nil, fmt.Errorf("%w: method must be %q or %q", sentinel.ErrInvalidParams, AggregateWhole, AggregateApportion)
+	}
+
+	catConditions, err := categorySQL(catset, censustable, qargs)
+	if err != nil {
+		return "", nil, err
+	}
+	censustableFromSQL, censustableAndSQL := censusTableFromAndSQL(censustable, qargs)
+
+	template := `
+WITH areas AS (%s
+)
+SELECT
+	areas.code AS geography_code,
+	nomis_category.long_nomis_code AS category_code,
+	geo_metric.metric AS value,
+	areas.weight
+FROM
+	areas,
+	geo_metric,
+	data_ver,
+	nomis_category
+	%s
+WHERE geo_metric.geo_id = areas.id
+AND areas.weight > 0
+	-- censustable conditions
+%s
+AND data_ver.id = geo_metric.data_ver_id
+AND data_ver.census_year = %s
+AND data_ver.ver_string = %s
+AND nomis_category.id = geo_metric.category_id
+AND nomis_category.year = data_ver.census_year
+	-- category conditions
+%s
+`
+
+	sql := fmt.Sprintf(
+		template,
+		areasSQL,
+		censustableFromSQL,
+		censustableAndSQL,
+		qargs.Add(year),
+		qargs.Add(version),
+		catConditions,
+	)
+	return sql, qargs.Values(), nil
+}
+
+// selectionShapeSQL generates a geometry expression for the area covered by bbox, location/radius
+// and polygon, for apportioning.
+// Geographies listed in geos have no shape to apportion by, so are not allowed.
+//
+func selectionShapeSQL(geos []string, bbox, location string, radius int, polygon string, qargs *where.Args) (string, error) {
+	if len(geos) != 0 {
+		return "", fmt.Errorf("%w: rows cannot be apportioned; use bbox, location/radius or polygon", sentinel.ErrInvalidParams)
+	}
+
+	var shapes []string
+	if bbox != "" {
+		multipoint, err := parseBBox(bbox)
+		if err != nil {
+			return "", err
+		}
+		shapes = append(shapes, fmt.Sprintf("ST_Envelope(ST_GeomFromText(%s, 4326))", qargs.Add(multipoint)))
+	}
+	if location != "" || radius != 0 {
+		coords, err := parseRadius(location, radius)
+		if err != nil {
+			return "", err
+		}
+		shapes = append(shapes, fmt.Sprintf(
+			"ST_Buffer(ST_SetSRID(ST_Point(%s, %s), 4326)::geography, %s)::geometry",
+			qargs.Add(coords[0]),
+			qargs.Add(coords[1]),
+			qargs.Add(radius),
+		))
+	}
+	if polygon != "" {
+		linestring, err := parsePolygon(polygon)
+		if err != nil {
+			return "", err
+		}
+		shapes = append(shapes, fmt.Sprintf("ST_Polygon(%s::geometry, 4326)", qargs.Add(linestring)))
+	}
+
+	if len(shapes) == 1 {
+		return shapes[0], nil
+	}
+	// overlapping shapes must not be counted twice
+	return fmt.Sprintf("ST_Union(ARRAY[%s])", strings.Join(shapes, ", ")), nil
+}
@@ -0,0 +1,88 @@
+package geodata
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ONSdigital/dp-find-insights-poc-api/pkg/where"
+	"github.com/ONSdigital/dp-find-insights-poc-api/sentinel"
+)
+
+func Test_aggregateSQL(t *testing.T) {
+	const polygon = "0.0844,51.4897,0.1214,51.4910,0.1338,51.4635,0.1017,51.4647,0.0844,51.4897"
+	catset := &where.ValueSet{Singles: []string{"QS101EW0001"}}
+
+	var tests = map[string]struct {
+		bbox     string
+		location string
+		radius   int
+		polygon  string
+		geos     []string
+		method   string
+		want     []string // fragments expected in the sql
+		wantErr  error
+	}{
+		"whole polygon": {
+			polygon: polygon,
+			method:  AggregateWhole,
+			want:    []string{"1.0 AS weight", "ST_COVERS("},
+		},
+		"whole rows": {
+			geos:   []string{"E01000001"},
+			method: AggregateWhole,
+			want:   []string{"1.0 AS weight", "geo.code IN ("},
+		},
+		"apportion polygon": {
+			polygon: polygon,
+			method:  AggregateApportion,
+			want:    []string{"(SELECT ST_Polygon($1::geometry, 4326) AS geom) AS shape", "ST_Intersection(geo.wkb_geometry, shape.geom)"},
+		},
+		"apportion radius": {
+			location: "0.1338,51.4635",
+			radius:   1000,
+			method:   AggregateApportion,
+			want:     []string{"ST_Buffer(ST_SetSRID(ST_Point($1, $2), 4326)::geography, $3)::geometry"},
+		},
+		"apportion several shapes": {
+			bbox:    "0.1338,51.4635,0.1017,51.4647",
+			polygon: polygon,
+			method:  AggregateApportion,
+			want:    []string{"ST_Union(ARRAY[ST_Envelope(ST_GeomFromText($1, 4326)), ST_Polygon($2::geometry, 4326)])"},
+		},
+		"apportion rows": {
+			geos:    []string{"E01000001"},
+			method:  AggregateApportion,
+			wantErr: sentinel.ErrInvalidParams,
+		},
+		"bad polygon": {
+			polygon: "0.0844,51.4897,0.1214,51.4910",
+			method:  AggregateApportion,
+			wantErr: sentinel.ErrInvalidParams,
+		},
+		"unknown method": {
+			polygon: polygon,
+			method:  "average",
+			wantErr: sentinel.ErrInvalidParams,
+		},
+	}
+
+	for name, test := range tests {
+		sql, values, err := aggregateSQL(2011, "2.2", test.bbox, test.location, test.radius, test.polygon, "LSOA", test.geos, catset, "", test.method)
+		if !errors.Is(err, test.wantErr) {
+			t.Errorf("%s: error %v, want %v", name, err, test.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		for _, want := range test.want {
+			if !strings.Contains(sql, want) {
+				t.Errorf("%s: sql does not contain %q:\n%s", name, want, sql)
+			}
+		}
+		if n := strings.Count(sql, "$"); n != len(values) {
+			t.Errorf("%s: %d placeholders, %d values", name, n, len(values))
+		}
+	}
+}
@@ -0,0 +1,386 @@
+package geodata
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/ONSdigital/dp-find-insights-poc-api/pkg/timer"
+	"github.com/ONSdigital/dp-find-insights-poc-api/pkg/where"
+	"github.com/ONSdigital/dp-find-insights-poc-api/sentinel"
+	"github.com/ONSdigital/log.go/v2/log"
+)
+
+// Chunk holds multiple catgeory data for a single geocode
+//
+type Chunk struct {
+	geocode string
+	geotype string
+	metrics map[string]float64
+}
+
+// Selection restricts the geographies that breaks are found over, using the same selectors as /query2.
+// A geography is selected if it is listed in Geos, or is in BBox, Location/Radius or Polygon,
+// and if Within is set, it must also be below a geography in Within.
+// The zero Selection selects all geographies.
+//
+type Selection struct {
+	Geos     []string
+	BBox     string
+	Location string
+	Radius   int
+	Polygon  string
+	Within   []string
+}
+
+// all is true if sel selects all geographies.
+func (sel Selection) all() bool {
+	return sel.empty() && len(sel.Within) == 0
+}
+
+// empty is true if sel has no selectors other than Within.
+func (sel Selection) empty() bool {
+	return len(sel.Geos) == 0 && sel.BBox == "" && sel.Location == "" && sel.Radius == 0 && sel.Polygon == ""
+}
+
+// BreaksParser holds data and methods neccessary to parse and process data for ckmeans and other breaks queries
+//
+type BreaksParser struct {
+	catcodes   []string
+	geotypes   []string
+	classifier Classifier
+	divideBy   string
+	k          int
+	rowGeocode string
+	rowGeotype string
+	nmetrics   int
+	metrics    map[string]map[string][]float64
+	breaks     map[string]map[string][]float64
+	chunk      *Chunk
+}
+
+// NewBreaksParser creates a new BreaksParser, which will use classifier to find the breaks.
+//
+func NewBreaksParser(classifier Classifier, divideBy string, k int) *BreaksParser {
+	return &BreaksParser{
+		catcodes:   []string{},
+		geotypes:   []string{},
+		classifier: classifier,
+		divideBy:   divideBy,
+		k:          k,
+		rowGeocode: "",
+		rowGeotype: "",
+		chunk: &Chunk{
+			geocode: "",
+			geotype: "",
+			metrics: map[string]float64{},
+		},
+		nmetrics: 0,
+		metrics:  map[string]map[string][]float64{},
+		breaks:   map[string]map[string][]float64{},
+	}
+}
+
+// ------------------------------------------------- main ----------------------------------------------------------- //
+
+// Breaks does an 'all rows' query for census data using sql generator from geodata pkg, parses the results by
+// category and then by geotype, and gets breaks for each geotype in each category using the classification
+// method (see NewClassifier). Optionally, if divideBy is not blank, will divide all categories by the category
+// indicated by the divideBy value, prior to getting breaks.
+// Only geographies in sel are classified, so breaks can be made for the area a user is looking at.
+//
+func (app *Geodata) Breaks(ctx context.Context, year int, version string, sel Selection, cat []string, geotype []string, method string, k int, divideBy string) (map[string]map[string][]float64, error) {
+	// initialise
+	classifier, err := NewClassifier(method)
+	if err != nil {
+		return nil, err
+	}
+	parser := NewBreaksParser(classifier, divideBy, k)
+	if err := parser.parseArgs(cat, geotype); err != nil {
+		return nil, err
+	}
+
+	// ckmeans breaks over all geographies may have been saved by StoreBreaks
+	if sel.all() && (method == "" || strings.EqualFold(method, ClassifyCkmeans)) {
+		breaks, ok, err := app.storedBreaks(ctx, year, version, parser)
+		if err != nil {
+			log.Warn(ctx, "cannot get stored breaks", log.Data{"message": err.Error()})
+		} else if ok {
+			return breaks, nil
+		}
+	}
+
+	if err := app.collectMetrics(ctx, year, version, sel, parser); err != nil {
+		return nil, err
+	}
+
+	if err = parser.processBreaks(); err != nil {
+		return nil, err
+	}
+	return parser.breaks, nil
+}
+
+// collectMetrics queries for census data for the geographies in sel, and collects the metrics (or ratios,
+// if parser.divideBy is set) for each geotype in each category parsed by parser.parseArgs into parser.metrics.
+//
+func (app *Geodata) collectMetrics(ctx context.Context, year int, version string, sel Selection, parser *BreaksParser) error {
+	// get sql
+	sql, values, err := getBreaksSQL(ctx, year, version, sel, parser)
+	if err != nil {
+		return err
+	}
+
+	// query for data
+	t := timer.New("query")
+	t.Start()
+	rows, err := app.db.DB().QueryContext(ctx, sql, values...)
+	if err != nil {
+		return err
+	}
+	t.Stop()
+	t.Log(ctx)
+	defer rows.Close()
+
+	// scan data from rows
+	tnext := timer.New("next")
+	tscan := timer.New("scan")
+	for {
+		tnext.Start()
+		ok := rows.Next()
+		tnext.Stop()
+
+		if !ok {
+			// nothing to process if we found no data at all
+			if parser.nmetrics == 0 {
+				break
+			}
+			// ensure last chunk is processed
+			if err := parser.processChunk(); err != nil {
+				return err
+			}
+			break
+		}
+
+		// consume row data
+		if err := parser.processRow(rows, tscan); err != nil {
+			return err
+		}
+	}
+	tnext.Log(ctx)
+	tscan.Log(ctx)
+	return rows.Err()
+}
+
+// ---------------------------------------------- BreaksParser methods --------------------------------------------- //
+
+// BreaksParser.parseArgs parses and validates cat and geotype tokens.
+//
+func (parser *BreaksParser) parseArgs(cat []string, geotype []string) error {
+	if err := parser.parseCat(cat); err != nil {
+		return err
+	}
+	return parser.parseValidateGeotype(geotype)
+}
+
+// BreaksParser.parseCat parses single values and combines with split comma-seperated cat values and returns as array.
+// Will return error if any cat range values (cat1..cat2) are found (for error handling we need to know
+// explicitly beforehand which cats to expect in our results)
+//
+func (parser *BreaksParser) parseCat(cat []string) error {
+	catset, err := where.ParseMultiArgs(cat)
+	if err != nil {
+		return err
+	}
+	if catset.Ranges != nil {
+		return fmt.Errorf("%w: breaks endpoints do not accept range values for cats", sentinel.ErrInvalidParams)
+	}
+	parser.catcodes = catset.Singles
+	return nil
+}
+
+// BreaksParser.parseValidateGeotype arses single values and combines with split comma-seperated geotype values
+// and returns as array. Any badly-cased geotype values will be corrected, and any unrecognised geotype
+// value will cause an error to be returned.
+//
+func (parser *BreaksParser) parseValidateGeotype(geotype []string) error {
+	geoset, err := where.ParseMultiArgs(geotype)
+	if err != nil {
+		return err
+	}
+	geoset, err = MapGeotypes(geoset)
+	if err != nil {
+		return err
+	}
+	parser.geotypes = geoset.Singles
+	return nil
+}
+
+// BreaksParser.processRow loads data from a sql.Row into a Chunk containing data from a single geotype and geocode
+// (NB this only works because the SQL returned by getBreaksSQL orders by geocode). If the current row contains data
+// from a different geotype or geocode, the Chunk is considered complete and is processed and reset.
+//
+func (parser *BreaksParser) processRow(rows *sql.Rows, tscan *timer.Timer) error {
+	// read data from row
+	var rowCatcode string
+	var rowMetric float64
+
+	tscan.Start()
+	err := rows.Scan(&parser.rowGeocode, &parser.rowGeotype, &rowCatcode, &rowMetric)
+	tscan.Stop()
+	if err != nil {
+		return err
+	}
+
+	// reset chunk with new geo and geotype if this is the first row we've read
+	if parser.nmetrics == 0 {
+		parser.resetChunk()
+	}
+
+	// if geotype OR geoID changes then we have reached the end of a chunk and should process it.
+	// NB - geotype will change WITHOUT geoID changing if we only have one category, so check both
+	if parser.isChunkComplete() {
+		if err := parser.processChunk(); err != nil {
+			return err
+		}
+	}
+
+	// otherwise collect results - ordered by geo, so should come in chunks
+	parser.addToChunk(rowCatcode, rowMetric)
+	parser.nmetrics++
+	return nil
+}
+
+// BreaksParser.processChunk parses data from a Chunk containing required category data for single geotype and geocode
+// into the main BreaksParser.metrics container. If BreaksParser.divideBy is not blank, all categories will be
+// divided by the divideBy category before being stored. Chunk is reset after data has been processed from it.
+//
+func (parser *BreaksParser) processChunk() error {
+	// check divideBy if doing ratios
+	metricDenominator, prs := parser.chunk.metrics[parser.divideBy]
+	if !prs && parser.divideBy != "" {
+		return fmt.Errorf("Incomplete data for category %s: %w", parser.divideBy, sentinel.ErrPartialContent)
+	}
+
+	// process all other required catcodes
+	for _, catcode := range parser.catcodes {
+
+		// check catcode has metric
+		metricCatcode, prs := parser.chunk.metrics[catcode]
+		if !prs {
+			return fmt.Errorf("Incomplete data for category %s: %w", catcode, sentinel.ErrPartialContent)
+		}
+
+		// derive or get metric
+		var outputMetric float64
+		if parser.divideBy != "" {
+			// make ratio if doing that
+			outputMetric = metricCatcode / metricDenominator
+		} else {
+			// otherwise just use data as is
+			outputMetric = metricCatcode
+		}
+
+		// append to metrics
+		if _, prs := parser.metrics[catcode]; !prs {
+			parser.metrics[catcode] = map[string][]float64{}
+		}
+		parser.metrics[catcode][parser.chunk.geotype] = append(parser.metrics[catcode][parser.chunk.geotype], outputMetric)
+	}
+
+	// reset chunk
+	parser.resetChunk()
+	return nil
+}
+
+// BreaksParser.isChunkComplete returns True if BreaksParser.Chunk contains data from a different geotype or geocode
+// from the sql.Row BreaksParser is currently processing.
+//
+func (parser *BreaksParser) isChunkComplete() bool {
+	// if geotype OR geoID changes then we have reached the end of a chunk and should process it.
+	// NB - geotype will change WITHOUT geoID changing if we only have one category, so check both
+	return parser.rowGeotype != parser.chunk.geotype || parser.rowGeocode != parser.chunk.geocode
+}
+
+// BreaksParser.addToChunk adds data for a new category code to BreaksParser.Chunk
+//
+func (parser *BreaksParser) addToChunk(catcode string, metric float64) {
+	parser.chunk.metrics[catcode] = metric
+}
+
+// BreaksParser.resetChunk deletes all data from BreaksParser.Chunk and sets the Chunk geotype and geocode to that of
+// the sql.Row that BreaksParser is currently processing.
+//
+func (parser *BreaksParser) resetChunk() {
+	for k := range parser.chunk.metrics {
+		delete(parser.chunk.metrics, k)
+	}
+	parser.chunk.geocode = parser.rowGeocode
+	parser.chunk.geotype = parser.rowGeotype
+}
+
+// BreaksParser.processBreaks crawls through BreaksParser.metrics and runs the classifier on each geotype's data for each
+// category, storing the results in BreaksParser.breaks
+//
+func (parser *BreaksParser) processBreaks() error {
+	if parser.nmetrics == 0 {
+		return nil
+	}
+	for _, catcode := range parser.catcodes {
+		for _, geotype := range parser.geotypes {
+			// a selection may have no geographies of this geotype
+			if len(parser.metrics[catcode][geotype]) == 0 {
+				continue
+			}
+			catBreaks, err := parser.classifier.Breaks(parser.metrics[catcode][geotype], parser.k)
+			if err != nil {
+				return err
+			}
+			catMaxMin := getMinMax(parser.metrics[catcode][geotype])
+			// append to breaks
+			if _, prs := parser.breaks[catcode]; !prs {
+				parser.breaks[catcode] = map[string][]float64{}
+			}
+			parser.breaks[catcode][geotype] = catBreaks
+			parser.breaks[catcode][geotype+"_min_max"] = catMaxMin
+		}
+	}
+	return nil
+}
+
+// ---------------------------------------------- functions --------------------------------------------------------- //
+
+// getBreaksSQL validates supplied arguments and then generates SQL for breaks query. This is the same as the
+// SQL used for a /query2 style query with a geotype filter, which is ordered by geocode (this is needed to process
+// data in chunks). An empty selection selects all rows. The bind parameters for the SQL are returned alongside it.
+//
+func getBreaksSQL(ctx context.Context, year int, version string, sel Selection, parser *BreaksParser) (string, []interface{}, error) {
+	// make 'cols' arg for using CensusQuerySQL (cats plus divide_by, if present)
+	cols := make([]string, len(parser.catcodes))
+	copy(cols, parser.catcodes)
+	if parser.divideBy != "" {
+		cols = append(cols, parser.divideBy)
+	}
+
+	geos := sel.Geos
+	if sel.empty() {
+		geos = []string{allRowsToken}
+	}
+
+	sql, values, _, err := CensusQuerySQL(
+		ctx,
+		CensusQuerySQLArgs{
+			Year:     year,
+			Version:  version,
+			Geos:     geos,
+			BBox:     sel.BBox,
+			Location: sel.Location,
+			Radius:   sel.Radius,
+			Polygon:  sel.Polygon,
+			Within:   sel.Within,
+			Geotypes: parser.geotypes,
+			Cols:     cols,
+		},
+	)
+	return sql, values, err
+}
@@ -0,0 +1,66 @@
+package geodata
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_getBreaksSQL(t *testing.T) {
+	var tests = map[string]struct {
+		sel          Selection
+		divideBy     string
+		wantContains []string
+		wantMissing  []string
+		wantArgs     []interface{}
+	}{
+		"all geographies": {
+			wantMissing: []string{"geo.code IN", "geo_parent"},
+			wantArgs:    []interface{}{"LAD", 2011, "2.2", "QS101EW0002"},
+		},
+		"divide_by": {
+			divideBy: "QS101EW0001",
+			wantArgs: []interface{}{"LAD", 2011, "2.2", "QS101EW0002", "QS101EW0001"},
+		},
+		"rows": {
+			sel:          Selection{Geos: []string{"E09000001...E09000033"}},
+			wantContains: []string{"geo.code BETWEEN"},
+			wantArgs:     []interface{}{"E09000001", "E09000033", "LAD", 2011, "2.2", "QS101EW0002"},
+		},
+		"bbox": {
+			sel:          Selection{BBox: "0.1338,51.4635,0.1017,51.4647"},
+			wantContains: []string{"ST_GeomFromText"},
+			wantArgs:     []interface{}{"MULTIPOINT(0.133800 51.463500, 0.101700 51.464700)", "LAD", 2011, "2.2", "QS101EW0002"},
+		},
+		"within only": {
+			sel:          Selection{Within: []string{"E12000007"}},
+			wantContains: []string{"geo_parent"},
+			wantMissing:  []string{"geo.code IN"},
+		},
+	}
+
+	for name, test := range tests {
+		parser := NewBreaksParser(nil, test.divideBy, 5)
+		if err := parser.parseArgs([]string{"QS101EW0002"}, []string{"lad"}); err != nil {
+			t.Fatal(err)
+		}
+
+		sql, args, err := getBreaksSQL(context.Background(), 2011, "2.2", test.sel, parser)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		assert.Equal(t, 1, strings.Count(sql, "ORDER BY"), name)
+		for _, s := range test.wantContains {
+			assert.Contains(t, sql, s, name)
+		}
+		for _, s := range test.wantMissing {
+			assert.NotContains(t, sql, s, name)
+		}
+		if test.wantArgs != nil {
+			assert.ElementsMatch(t, test.wantArgs, args, name)
+		}
+	}
+}
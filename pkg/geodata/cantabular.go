@@ -0,0 +1,454 @@
+package geodata
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ONSdigital/dp-find-insights-poc-api/api"
+	"github.com/ONSdigital/dp-find-insights-poc-api/cantabular"
+	"github.com/ONSdigital/dp-find-insights-poc-api/model"
+	"github.com/ONSdigital/dp-find-insights-poc-api/pkg/table"
+	"github.com/ONSdigital/dp-find-insights-poc-api/pkg/timer"
+	"github.com/ONSdigital/dp-find-insights-poc-api/pkg/where"
+	"github.com/ONSdigital/dp-find-insights-poc-api/sentinel"
+	"github.com/gosimple/slug"
+	"github.com/lib/pq"
+)
+
+// Retrieve metrics from Cantabular, with any derived columns, generated in format.
+//
+// catset and censustable select categories exactly as they do for PGMetricsStream, and the
+// output is the same table, so clients cannot tell which backend answered.
+// Census tables and geotypes are translated with the cantabular.Mapping read from the database.
+// Category codes are the NOMIS style codes made by cantabular.CategoryCode, with the
+// 0001 total computed as the sum of the variable's categories.
+// Cantabular's "Not applicable" category is not in NOMIS, so it is left out of both.
+//
+// Each Cantabular variable is queried once per geotype among geocodes, however many
+// census tables or categories are built from it.
+//
+func (app *Geodata) CantabularMetrics(ctx context.Context, geocodes []string, catset *where.ValueSet, include []string, censustable string, derived Derived, format table.Format) ([]byte, error) {
+	if app.cant == nil {
+		return nil, fmt.Errorf("%w: cantabular not enabled", sentinel.ErrNotSupported)
+	}
+
+	tbl, err := app.cantabularTable(ctx, geocodes, catset, censustable, derived)
+	if err != nil {
+		return nil, err
+	}
+	return generateTable(ctx, tbl, format, include)
+}
+
+// cantabularTable fetches metrics for geocodes from Cantabular into a table, and adds derived columns.
+// If there are no geocodes, the Cantabular queries are skipped and the table is empty.
+func (app *Geodata) cantabularTable(ctx context.Context, geocodes []string, catset *where.ValueSet, censustable string, derived Derived) (*table.Table, error) {
+	tbl := table.New()
+
+	catset, deriver, err := derived.prepare(catset)
+	if err != nil {
+		return nil, err
+	}
+
+	mapping, err := app.cant.Mapping(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	vars, err := cantVars(mapping, catset, censustable)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(geocodes) == 0 {
+		return tbl, nil
+	}
+
+	bygeotype, err := app.cantGeotypes(ctx, mapping, geocodes)
+	if err != nil {
+		return nil, err
+	}
+
+	want := cantWanted(catset, censustable)
+
+	var nmetrics int
+	for _, geotype := range sortedKeys(bygeotype) {
+		for _, v := range sortedVars(vars) {
+			tables := vars[v]
+
+			t := timer.New("cantabular")
+			t.Start()
+			geoq, cats, values, err := app.cant.QueryVariableFilter(ctx, v, mapping.Geotypes[geotype], bygeotype[geotype])
+			t.Stop()
+			t.Log(ctx)
+			if err != nil {
+				return nil, err
+			}
+
+			n, err := addCantMetrics(tbl, geotype, tables, geoq, cats, values, want)
+			if err != nil {
+				return nil, err
+			}
+			nmetrics += n
+			if app.maxMetrics > 0 && nmetrics > app.maxMetrics {
+				return nil, fmt.Errorf("%w: limit is %d", sentinel.ErrTooManyMetrics, app.maxMetrics)
+			}
+		}
+	}
+
+	if deriver != nil {
+		tbl.Derive(deriver)
+	}
+
+	return tbl, nil
+}
+
+// CantabularYear returns true if Cantabular is enabled and serves data for census year.
+func (app *Geodata) CantabularYear(year int) bool {
+	return app.cant != nil && app.cant.Serves(year)
+}
+
+// CantabularMetadata returns the census tables mapped to Cantabular for year as JSON, shaped like the
+// metadata for postgres years: tables are grouped under their NOMIS topic, eg QS1 for QS101EW.
+// Table and category names are Cantabular's labels; Cantabular has no Welsh, so names
+// are in English whatever lang is.
+// Categories are numbered with cantabular.CategoryCodes, in Cantabular's order, after the
+// total, which is moved to the table's total if filterTotals is true.
+//
+func (app *Geodata) CantabularMetadata(ctx context.Context, year int, filterTotals bool, lang string) ([]byte, error) {
+	if app.cant == nil {
+		return nil, fmt.Errorf("%w: cantabular not enabled", sentinel.ErrNotSupported)
+	}
+	if !app.cant.Serves(year) {
+		return nil, fmt.Errorf("%w: no cantabular metadata for %d", sentinel.ErrNotFound, year)
+	}
+
+	mapping, err := app.cant.Mapping(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var vars []cantabular.Variable
+	seen := map[cantabular.Variable]bool{}
+	for _, code := range mapping.TableCodes() {
+		v := mapping.Tables[code]
+		if !seen[v] {
+			seen[v] = true
+			vars = append(vars, v)
+		}
+	}
+
+	t := timer.New("cantabular")
+	t.Start()
+	infos, err := app.cant.Describe(ctx, vars)
+	t.Stop()
+	t.Log(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	topicNames, err := app.topicNames(ctx, lang)
+	if err != nil {
+		return nil, err
+	}
+
+	var mdr api.MetadataResponse
+	for _, code := range mapping.TableCodes() {
+		topic := cantTopicCode(code)
+		if len(mdr) == 0 || *mdr[len(mdr)-1].Code != topic {
+			name, ok := topicNames[topic]
+			if !ok {
+				name = topic
+			}
+			mdr = append(mdr, api.Metadata{
+				Code:   stringPtr(topic),
+				Name:   stringPtr(name),
+				Slug:   stringPtr(slug.Make(name)),
+				Tables: &api.Tables{},
+			})
+		}
+		tables := mdr[len(mdr)-1].Tables
+		*tables = append(*tables, cantTableMetadata(code, infos[mapping.Tables[code]], filterTotals))
+	}
+	if mdr == nil {
+		mdr = api.MetadataResponse{}
+	}
+
+	return json.Marshal(mdr)
+}
+
+// cantTableMetadata describes census table code, which is built from the variable described by info.
+func cantTableMetadata(code string, info cantabular.VariableInfo, filterTotals bool) api.Table {
+	totalName := "All categories: " + info.Label
+	total := api.Triplet{
+		Code: stringPtr(code + "0001"),
+		Name: stringPtr(totalName),
+		Slug: stringPtr(slug.Make(totalName)),
+	}
+
+	table := api.Table{
+		Code: stringPtr(code),
+		Name: stringPtr(info.Label),
+		Slug: stringPtr(slug.Make(info.Label)),
+	}
+
+	cats := api.Categories{}
+	if filterTotals {
+		table.Total = &total
+	} else {
+		cats = append(cats, total)
+	}
+	for i, catcode := range cantabular.CategoryCodes(code, info.Categories) {
+		if catcode == "" {
+			continue
+		}
+		cat := info.Categories[i]
+		cats = append(cats, api.Triplet{
+			Code: stringPtr(catcode),
+			Name: stringPtr(string(cat.Label)),
+			Slug: stringPtr(slug.Make(string(cat.Label))),
+		})
+	}
+	table.Categories = &cats
+	return table
+}
+
+// topicNames returns the names of the NOMIS topics in lang, by topic code.
+func (app *Geodata) topicNames(ctx context.Context, lang string) (map[string]string, error) {
+	rows, err := app.db.DB().QueryContext(ctx, "SELECT top_nomis_code, name, welsh_name FROM nomis_topic WHERE id != 0")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	names := map[string]string{}
+	for rows.Next() {
+		var code, name string
+		var welsh sql.NullString
+		if err := rows.Scan(&code, &name, &welsh); err != nil {
+			return nil, err
+		}
+		names[code] = model.Localise(lang, name, welsh.String)
+	}
+	return names, rows.Err()
+}
+
+// cantTopicCode returns the NOMIS topic code of census table code, eg QS1 for QS101EW.
+func cantTopicCode(code string) string {
+	if len(code) < 3 {
+		return code
+	}
+	return code[:3]
+}
+
+// stringPtr returns a pointer to a copy of s.
+func stringPtr(s string) *string {
+	return &s
+}
+
+// cantGeotypes groups geocodes by their geotype.
+// Geocodes not in the geo table are dropped, as they are for postgres queries.
+// Geotypes Cantabular does not have are sentinel.ErrInvalidParams.
+//
+func (app *Geodata) cantGeotypes(ctx context.Context, mapping *cantabular.Mapping, geocodes []string) (map[string][]string, error) {
+	sql := `
+SELECT
+	geo.code,
+	geo_type.name
+FROM
+	geo,
+	geo_type
+WHERE geo.valid
+AND geo_type.id = geo.type_id
+AND geo.code = ANY ($1)
+ORDER BY geo.code
+`
+
+	t := timer.New("query")
+	t.Start()
+	rows, err := app.db.DB().QueryContext(ctx, sql, pq.Array(geocodes))
+	if err != nil {
+		return nil, err
+	}
+	t.Stop()
+	t.Log(ctx)
+	defer rows.Close()
+
+	bygeotype := map[string][]string{}
+	for rows.Next() {
+		var geocode, geotype string
+		if err := rows.Scan(&geocode, &geotype); err != nil {
+			return nil, err
+		}
+		if _, err := mapping.Geotype(geotype); err != nil {
+			return nil, err
+		}
+		bygeotype[geotype] = append(bygeotype[geotype], geocode)
+	}
+	return bygeotype, rows.Err()
+}
+
+// cantVars works out the census tables needed for catset and censustable, grouped by the
+// Cantabular variable each is built from, so each variable need only be queried once.
+// With no categories and no censustable, every census table is needed, just as every
+// category is selected from postgres.
+//
+func cantVars(mapping *cantabular.Mapping, catset *where.ValueSet, censustable string) (map[cantabular.Variable][]string, error) {
+	needed := map[string]bool{}
+	need := func(code string) error {
+		if _, err := mapping.Table(code); err != nil {
+			return err
+		}
+		needed[code] = true
+		return nil
+	}
+
+	if censustable != "" {
+		if err := need(censustable); err != nil {
+			return nil, err
+		}
+	}
+
+	var named bool
+	if catset != nil {
+		for _, single := range catset.Singles {
+			named = true
+			if err := need(cantTableCode(single)); err != nil {
+				return nil, err
+			}
+		}
+		for _, r := range catset.Ranges {
+			named = true
+			for code := range mapping.Tables {
+				if code+"0001" <= r.High && code+"9999" >= r.Low {
+					needed[code] = true
+				}
+			}
+		}
+	}
+
+	if !named && censustable == "" {
+		for code := range mapping.Tables {
+			needed[code] = true
+		}
+	}
+
+	vars := map[cantabular.Variable][]string{}
+	for code := range needed {
+		v := mapping.Tables[code]
+		vars[v] = append(vars[v], code)
+	}
+	for _, tables := range vars {
+		sort.Strings(tables)
+	}
+	return vars, nil
+}
+
+// cantTableCode returns the census table code of the long category code catcode.
+func cantTableCode(catcode string) string {
+	if len(catcode) < 4 {
+		return catcode
+	}
+	return catcode[:len(catcode)-4]
+}
+
+// cantWanted returns a func reporting whether a category code is selected by catset or
+// censustable, matching the conditions categorySQL puts on postgres queries.
+func cantWanted(catset *where.ValueSet, censustable string) func(string) bool {
+	if catset == nil {
+		catset = where.NewValueSet()
+	}
+	if len(catset.Singles) == 0 && len(catset.Ranges) == 0 && censustable == "" {
+		return func(string) bool { return true }
+	}
+
+	singles := map[string]bool{}
+	for _, single := range catset.Singles {
+		singles[single] = true
+	}
+
+	return func(catcode string) bool {
+		if singles[catcode] {
+			return true
+		}
+		for _, r := range catset.Ranges {
+			if catcode >= r.Low && catcode <= r.High {
+				return true
+			}
+		}
+		return censustable != "" && cantTableCode(catcode) == censustable
+	}
+}
+
+// addCantMetrics adds the wanted cells from one Cantabular table query to tbl, returning
+// how many were added.
+// Every census table in tables is built from the queried variable, so each gets the same
+// values under its own category codes.
+// The NotApplicable category is left out, and out of the total, as it is from NOMIS.
+//
+func addCantMetrics(tbl *table.Table, geotype string, tables []string, geoq, cats cantabular.Pairs, values cantabular.IntValues, want func(string) bool) (int, error) {
+	if len(values) != len(geoq)*len(cats) {
+		return 0, fmt.Errorf("cantabular returned %d values for %d geographies and %d categories", len(values), len(geoq), len(cats))
+	}
+
+	catcodes := map[string][]string{}
+	for _, code := range tables {
+		catcodes[code] = cantabular.CategoryCodes(code, cats)
+	}
+
+	var n int
+	for g, geo := range geoq {
+		// 2011 cantabular geocodes have syn prepended
+		geocode := strings.TrimPrefix(string(geo.Code), "syn")
+		row := values[g*len(cats) : (g+1)*len(cats)]
+
+		var total float64
+		for i, value := range row {
+			if string(cats[i].Code) != cantabular.NotApplicable {
+				total += float64(value)
+			}
+		}
+
+		for _, code := range tables {
+			if cat := code + "0001"; want(cat) {
+				tbl.SetCell(geocode, geotype, cat, total)
+				n++
+			}
+			for i, value := range row {
+				if cat := catcodes[code][i]; cat != "" && want(cat) {
+					tbl.SetCell(geocode, geotype, cat, float64(value))
+					n++
+				}
+			}
+		}
+	}
+	return n, nil
+}
+
+// sortedKeys returns the keys of m in order, so queries are made in a repeatable order.
+func sortedKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// sortedVars returns the keys of vars in order of dataset then variable.
+func sortedVars(vars map[cantabular.Variable][]string) []cantabular.Variable {
+	keys := make([]cantabular.Variable, 0, len(vars))
+	for v := range vars {
+		keys = append(keys, v)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Dataset != keys[j].Dataset {
+			return keys[i].Dataset < keys[j].Dataset
+		}
+		return keys[i].Variable < keys[j].Variable
+	})
+	return keys
+}
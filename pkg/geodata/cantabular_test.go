@@ -0,0 +1,218 @@
+package geodata
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/ONSdigital/dp-find-insights-poc-api/cantabular"
+	"github.com/ONSdigital/dp-find-insights-poc-api/pkg/table"
+	"github.com/ONSdigital/dp-find-insights-poc-api/pkg/where"
+	"github.com/ONSdigital/dp-find-insights-poc-api/sentinel"
+	"github.com/stretchr/testify/assert"
+)
+
+// testMapping returns a cantabular.Mapping like the one seeded into the db.
+func testMapping() *cantabular.Mapping {
+	return &cantabular.Mapping{
+		Tables: map[string]cantabular.Variable{
+			"KS207WA": {Dataset: "Usual-Residents", Variable: "WELSHPUK112_R003A"},
+			"KS208WA": {Dataset: "Usual-Residents", Variable: "WELSHPUK112_R003A"},
+			"QS104EW": {Dataset: "Usual-Residents", Variable: "SEX"},
+			"QS402EW": {Dataset: "People-Households", Variable: "TYPACCOM_T009A"},
+			"QS403EW": {Dataset: "People-Households", Variable: "TENHUK11_T010A"},
+			"QS406EW": {Dataset: "People-Households", Variable: "SIZHUK11_T007A"},
+			"QS501EW": {Dataset: "Usual-Residents", Variable: "HLQPUK11_T007A"},
+			"QS701EW": {Dataset: "Usual-Residents", Variable: "TRANSPORT_R005A"},
+		},
+		Geotypes: map[string]string{
+			"LAD": "LA",
+		},
+	}
+}
+
+func TestCantVars(t *testing.T) {
+	var tests = map[string]struct {
+		catset      *where.ValueSet
+		censustable string
+		want        map[cantabular.Variable][]string
+		wantErr     error
+	}{
+		"single": {
+			catset: &where.ValueSet{Singles: []string{"QS501EW0002"}},
+			want: map[cantabular.Variable][]string{
+				{Dataset: "Usual-Residents", Variable: "HLQPUK11_T007A"}: {"QS501EW"},
+			},
+		},
+		"categories in one table query once": {
+			catset: &where.ValueSet{Singles: []string{"QS501EW0002", "QS501EW0003"}},
+			want: map[cantabular.Variable][]string{
+				{Dataset: "Usual-Residents", Variable: "HLQPUK11_T007A"}: {"QS501EW"},
+			},
+		},
+		"tables from one variable query once": {
+			catset: &where.ValueSet{Singles: []string{"KS207WA0001", "KS208WA0002"}},
+			want: map[cantabular.Variable][]string{
+				{Dataset: "Usual-Residents", Variable: "WELSHPUK112_R003A"}: {"KS207WA", "KS208WA"},
+			},
+		},
+		"range across tables": {
+			catset: &where.ValueSet{Ranges: []*where.ValueRange{{Low: "QS402EW0003", High: "QS406EW0002"}}},
+			want: map[cantabular.Variable][]string{
+				{Dataset: "People-Households", Variable: "TYPACCOM_T009A"}: {"QS402EW"},
+				{Dataset: "People-Households", Variable: "TENHUK11_T010A"}: {"QS403EW"},
+				{Dataset: "People-Households", Variable: "SIZHUK11_T007A"}: {"QS406EW"},
+			},
+		},
+		"censustable": {
+			censustable: "QS104EW",
+			want: map[cantabular.Variable][]string{
+				{Dataset: "Usual-Residents", Variable: "SEX"}: {"QS104EW"},
+			},
+		},
+		"unknown table": {
+			catset:  &where.ValueSet{Singles: []string{"QS999EW0002"}},
+			wantErr: sentinel.ErrInvalidParams,
+		},
+		"unknown censustable": {
+			censustable: "QS999EW",
+			wantErr:     sentinel.ErrInvalidParams,
+		},
+	}
+
+	for name, test := range tests {
+		got, err := cantVars(testMapping(), test.catset, test.censustable)
+		if !errors.Is(err, test.wantErr) {
+			t.Errorf("%s: got error %v, want %v", name, err, test.wantErr)
+			continue
+		}
+		if test.wantErr != nil {
+			continue
+		}
+		assert.Equal(t, test.want, got, name)
+	}
+}
+
+func TestCantVarsEverything(t *testing.T) {
+	mapping := testMapping()
+	got, err := cantVars(mapping, where.NewValueSet(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ntables int
+	for _, tables := range got {
+		ntables += len(tables)
+	}
+	assert.Equal(t, len(mapping.Tables), ntables)
+}
+
+func TestCantWanted(t *testing.T) {
+	var tests = map[string]struct {
+		catset      *where.ValueSet
+		censustable string
+		catcode     string
+		want        bool
+	}{
+		"everything": {
+			catset:  where.NewValueSet(),
+			catcode: "QS101EW0002",
+			want:    true,
+		},
+		"single": {
+			catset:  &where.ValueSet{Singles: []string{"QS101EW0002"}},
+			catcode: "QS101EW0002",
+			want:    true,
+		},
+		"other single": {
+			catset:  &where.ValueSet{Singles: []string{"QS101EW0002"}},
+			catcode: "QS101EW0003",
+		},
+		"in range": {
+			catset:  &where.ValueSet{Ranges: []*where.ValueRange{{Low: "QS101EW0002", High: "QS101EW0004"}}},
+			catcode: "QS101EW0004",
+			want:    true,
+		},
+		"outside range": {
+			catset:  &where.ValueSet{Ranges: []*where.ValueRange{{Low: "QS101EW0002", High: "QS101EW0004"}}},
+			catcode: "QS101EW0001",
+		},
+		"in censustable": {
+			catset:      where.NewValueSet(),
+			censustable: "QS101EW",
+			catcode:     "QS101EW0001",
+			want:        true,
+		},
+		"outside censustable": {
+			catset:      where.NewValueSet(),
+			censustable: "QS101EW",
+			catcode:     "QS104EW0001",
+		},
+	}
+
+	for name, test := range tests {
+		got := cantWanted(test.catset, test.censustable)(test.catcode)
+		assert.Equal(t, test.want, got, name)
+	}
+}
+
+func TestAddCantMetrics(t *testing.T) {
+	geoq := cantabular.Pairs{
+		{Code: "synE06000001", Label: "Hartlepool"},
+		{Code: "synE06000002", Label: "Middlesbrough"},
+	}
+	cats := cantabular.Pairs{
+		{Code: "1", Label: "Welsh"},
+		{Code: "-9", Label: "Not applicable"},
+		{Code: "2", Label: "Not Welsh"},
+	}
+	values := cantabular.IntValues{1, 10, 2, 3, 20, 4}
+
+	// not applicable is neither a category nor part of the total
+	want := cantWanted(&where.ValueSet{Singles: []string{"KS207WA0001", "KS207WA0003", "KS207WA0004", "KS208WA0002"}}, "")
+
+	tbl := table.New()
+	n, err := addCantMetrics(tbl, "LAD", []string{"KS207WA", "KS208WA"}, geoq, cats, values, want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 6, n)
+
+	var body bytes.Buffer
+	if err := tbl.Generate(&body, []string{table.ColGeographyCode, table.ColGeotype}); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "geography_code,geotype,KS207WA0001,KS207WA0003,KS208WA0002\nE06000001,LAD,3,2,1\nE06000002,LAD,7,4,3\n", body.String())
+}
+
+func TestAddCantMetricsShortValues(t *testing.T) {
+	geoq := cantabular.Pairs{{Code: "synE06000001"}}
+	cats := cantabular.Pairs{{Code: "1"}, {Code: "2"}}
+
+	_, err := addCantMetrics(table.New(), "LAD", []string{"KS207WA"}, geoq, cats, cantabular.IntValues{1}, cantWanted(nil, ""))
+	assert.Error(t, err)
+}
+
+func TestCantTableMetadata(t *testing.T) {
+	info := cantabular.VariableInfo{
+		Label: "Sex",
+		Categories: cantabular.Pairs{
+			{Code: "1", Label: "Male"},
+			{Code: "2", Label: "Female"},
+			{Code: "-9", Label: "Not applicable"},
+		},
+	}
+
+	b, err := json.Marshal(cantTableMetadata("QS104EW", info, false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, `{"categories":[{"code":"QS104EW0001","name":"All categories: Sex","slug":"all-categories-sex"},{"code":"QS104EW0002","name":"Male","slug":"male"},{"code":"QS104EW0003","name":"Female","slug":"female"}],"code":"QS104EW","name":"Sex","slug":"sex"}`, string(b))
+
+	b, err = json.Marshal(cantTableMetadata("QS104EW", info, true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, `{"categories":[{"code":"QS104EW0002","name":"Male","slug":"male"},{"code":"QS104EW0003","name":"Female","slug":"female"}],"code":"QS104EW","name":"Sex","slug":"sex","total":{"code":"QS104EW0001","name":"All categories: Sex","slug":"all-categories-sex"}}`, string(b))
+}
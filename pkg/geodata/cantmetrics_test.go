@@ -0,0 +1,202 @@
+//go:build comptest
+// +build comptest
+
+package geodata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"testing"
+
+	"github.com/ONSdigital/dp-find-insights-poc-api/cantabular"
+	"github.com/ONSdigital/dp-find-insights-poc-api/cantabular/fake"
+	"github.com/ONSdigital/dp-find-insights-poc-api/comptests"
+	"github.com/ONSdigital/dp-find-insights-poc-api/pkg/database"
+	"github.com/ONSdigital/dp-find-insights-poc-api/pkg/table"
+	"github.com/ONSdigital/dp-find-insights-poc-api/pkg/where"
+	"github.com/ONSdigital/dp-find-insights-poc-api/sentinel"
+)
+
+// cantMetricsTestSetup adds the geographies in the fake Cantabular fixtures to the db.
+func cantMetricsTestSetup(t *testing.T, db *database.Database) {
+	// clear out any leaked data
+	err := comptests.ClearDB(db)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	comptests.DoSQL(t, db, "INSERT INTO geo_type (id,name) VALUES (1,'Country'),(2,'LAD')")
+
+	geos := []struct {
+		typeID int
+		code   string
+		name   string
+	}{
+		{1, "E92000001", "England"},
+		{1, "W92000004", "Wales"},
+		{2, "E06000001", "Hartlepool"},
+		{2, "E06000002", "Middlesbrough"},
+		{2, "W06000001", "Isle of Anglesey"},
+	}
+	for i, geo := range geos {
+		comptests.DoSQL(
+			t,
+			db,
+			fmt.Sprintf(
+				`INSERT INTO geo (id,type_id,code,name,lat,long,valid,wkb_geometry,wkb_long_lat_geom)
+				VALUES (%d,%d,'%s','%s',1,-0.1,true,null,null)`,
+				i+1,
+				geo.typeID,
+				geo.code,
+				geo.name,
+			),
+		)
+	}
+}
+
+// fakeCantabular returns a client for a fake Cantabular, and a func to stop it.
+func fakeCantabular(t *testing.T) (*cantabular.Client, func()) {
+	srv, err := fake.NewServer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cant := cantabular.New(srv.URL, "", "")
+	cant.SetMapping(&cantabular.Mapping{
+		Tables: map[string]cantabular.Variable{
+			"QS104EW": {Dataset: "Usual-Residents", Variable: "SEX"},
+			"QS501EW": {Dataset: "Usual-Residents", Variable: "HLQPUK11_T007A"},
+		},
+		Geotypes: map[string]string{
+			"Country": "Country",
+			"LAD":     "LA",
+		},
+	})
+	cant.SetYears([]int{2021})
+	return cant, srv.Close
+}
+
+func TestCantabularMetrics(t *testing.T) {
+	// GIVEN the database has the fake's geographies
+	dsn := comptests.DefaultDSN
+	db, err := database.Open("pgx", dsn)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// AND a fake Cantabular
+	cant, stop := fakeCantabular(t)
+	defer stop()
+
+	func() {
+		db.DB().Exec("BEGIN")
+		defer db.DB().Exec("ROLLBACK")
+
+		cantMetricsTestSetup(t, db)
+
+		app, err := New(db, cant, 100)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		var tests = map[string]struct {
+			geocodes    []string
+			catset      *where.ValueSet
+			censustable string
+			derived     Derived
+			want        string
+		}{
+			"single category": {
+				geocodes: []string{"E06000001", "E06000002"},
+				catset:   &where.ValueSet{Singles: []string{"QS104EW0002"}},
+				want:     "geography_code,geotype,QS104EW0002\nE06000001,LAD,44838\nE06000002,LAD,68313\n",
+			},
+			"range and total": {
+				geocodes: []string{"E06000001"},
+				catset:   &where.ValueSet{Ranges: []*where.ValueRange{{Low: "QS104EW0001", High: "QS104EW0003"}}},
+				want:     "geography_code,geotype,QS104EW0001,QS104EW0002,QS104EW0003\nE06000001,LAD,92028,44838,47190\n",
+			},
+			"censustable across geotypes": {
+				geocodes:    []string{"W92000004", "W06000001"},
+				catset:      where.NewValueSet(),
+				censustable: "QS501EW",
+				want:        "geography_code,geotype,QS501EW0001,QS501EW0002,QS501EW0003,QS501EW0004,QS501EW0005,QS501EW0006,QS501EW0007\nW06000001,LAD,52671,14316,7125,8412,6815,12102,3901\nW92000004,Country,2515824,658065,327844,390605,304275,633755,201280\n",
+			},
+			"tables together": {
+				geocodes: []string{"E06000001"},
+				catset:   &where.ValueSet{Singles: []string{"QS104EW0002", "QS501EW0002"}},
+				want:     "geography_code,geotype,QS104EW0002,QS501EW0002\nE06000001,LAD,44838,21054\n",
+			},
+			"percent of total": {
+				geocodes: []string{"E06000001"},
+				catset:   &where.ValueSet{Singles: []string{"QS104EW0003"}},
+				derived:  Derived{PercentOf: PercentOfTotal},
+				want:     "geography_code,geotype,QS104EW0001,QS104EW0003,QS104EW0003_pct\nE06000001,LAD,92028,47190,51.27787195201\n",
+			},
+		}
+
+		include := []string{table.ColGeographyCode, table.ColGeotype}
+		for name, test := range tests {
+			// WHEN we get metrics from Cantabular
+			got, err := app.CantabularMetrics(context.Background(), test.geocodes, test.catset, include, test.censustable, test.derived, table.FormatCSV)
+
+			// THEN we get the same table postgres would give
+			if err != nil {
+				t.Errorf("%s: %s", name, err)
+				continue
+			}
+			if string(got) != test.want {
+				t.Errorf("%s: got %q, want %q", name, got, test.want)
+			}
+		}
+	}()
+}
+
+func TestCantabularMetadata(t *testing.T) {
+	// GIVEN the database has the NOMIS topics
+	dsn := comptests.DefaultDSN
+	db, err := database.Open("pgx", dsn)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// AND a fake Cantabular
+	cant, stop := fakeCantabular(t)
+	defer stop()
+
+	func() {
+		db.DB().Exec("BEGIN")
+		defer db.DB().Exec("ROLLBACK")
+
+		err := comptests.ClearDB(db)
+		if err != nil {
+			log.Fatal(err)
+		}
+		comptests.DoSQL(t, db, "INSERT INTO nomis_topic (id,top_nomis_code,name) VALUES (1,'QS1','Population Basics'),(5,'QS5','Education')")
+
+		app, err := New(db, cant, 100)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		// WHEN we get the metadata from Cantabular
+		got, err := app.CantabularMetadata(context.Background(), 2021, true, "en")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// THEN the mapped tables are grouped under their topics, with categories in Cantabular's order
+		want := `[{"code":"QS1","name":"Population Basics","slug":"population-basics","tables":[{"categories":[{"code":"QS104EW0002","name":"Male","slug":"male"},{"code":"QS104EW0003","name":"Female","slug":"female"}],"code":"QS104EW","name":"Sex","slug":"sex","total":{"code":"QS104EW0001","name":"All categories: Sex","slug":"all-categories-sex"}}]},{"code":"QS5","name":"Education","slug":"education","tables":[{"categories":[{"code":"QS501EW0002","name":"No qualifications","slug":"no-qualifications"},{"code":"QS501EW0003","name":"Level 1 qualifications","slug":"level-1-qualifications"},{"code":"QS501EW0004","name":"Level 2 qualifications","slug":"level-2-qualifications"},{"code":"QS501EW0005","name":"Level 3 qualifications","slug":"level-3-qualifications"},{"code":"QS501EW0006","name":"Level 4 qualifications and above","slug":"level-4-qualifications-and-above"},{"code":"QS501EW0007","name":"Apprenticeships and other qualifications","slug":"apprenticeships-and-other-qualifications"}],"code":"QS501EW","name":"Highest level of qualification (7 categories)","slug":"highest-level-of-qualification-7-categories","total":{"code":"QS501EW0001","name":"All categories: Highest level of qualification (7 categories)","slug":"all-categories-highest-level-of-qualification-7-categories"}}]}]`
+		if string(got) != want {
+			t.Errorf("got %s, want %s", got, want)
+		}
+
+		// AND years Cantabular does not serve are not found
+		_, err = app.CantabularMetadata(context.Background(), 2031, true, "en")
+		if !errors.Is(err, sentinel.ErrNotFound) {
+			t.Errorf("got %v, want %v", err, sentinel.ErrNotFound)
+		}
+	}()
+}
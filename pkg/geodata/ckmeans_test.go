@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"log"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/ONSdigital/dp-find-insights-poc-api/comptests"
@@ -168,6 +169,7 @@ func TestCkmeansHappyPathSingleCategorySingleGeotype(t *testing.T) {
 		result, err := app.CKmeans(
 			context.Background(),
 			2011,
+			"2.2",
 			[]string{"category1"},
 			[]string{"LAD"},
 			testK,
@@ -223,6 +225,7 @@ func TestCkmeansHappyPathMultiCategorySingleGeotype(t *testing.T) {
 		result, err := app.CKmeans(
 			context.Background(),
 			2011,
+			"2.2",
 			[]string{"category1,category2,category3"},
 			[]string{"LAD"},
 			testK,
@@ -291,6 +294,7 @@ func TestCkmeansHappyPathMultiCategoryMultiGeotype(t *testing.T) {
 		result, err := app.CKmeans(
 			context.Background(),
 			2011,
+			"2.2",
 			[]string{"category1,category2,category3"},
 			[]string{"LAD,MSOA"},
 			testK,
@@ -355,6 +359,7 @@ func TestCkmeansNoData(t *testing.T) {
 		result, err := app.CKmeans(
 			context.Background(),
 			2011,
+			"2.2",
 			[]string{"category1"},
 			[]string{"LAD"},
 			testK,
@@ -409,6 +414,7 @@ func TestCkmeansRatiosHappyPathSingleGeotype(t *testing.T) {
 		result, err := app.CKmeans(
 			context.Background(),
 			2011,
+			"2.2",
 			[]string{"numerator1,numerator2,numerator3"},
 			[]string{"LAD"},
 			testK,
@@ -481,6 +487,7 @@ func TestCkmeansRatiosHappyPathMultipleGeotypes(t *testing.T) {
 		result, err := app.CKmeans(
 			context.Background(),
 			2011,
+			"2.2",
 			[]string{"numerator1,numerator2,numerator3"},
 			[]string{"LAD,MSOA"},
 			testK,
@@ -554,6 +561,7 @@ func TestCkmeansRatiosPartialDataOneCategoryMissing(t *testing.T) {
 		result, err := app.CKmeans(
 			context.Background(),
 			2011,
+			"2.2",
 			[]string{"numerator1,numerator2,numerator3"},
 			[]string{"LAD"},
 			testK,
@@ -611,6 +619,7 @@ func TestCkmeansRatiosPartialDataOneCategoryPartialDataOnly(t *testing.T) {
 		result, err := app.CKmeans(
 			context.Background(),
 			2011,
+			"2.2",
 			[]string{"numerator1,numerator2,numerator3"},
 			[]string{"LAD"},
 			testK,
@@ -655,6 +664,7 @@ func TestCkmeansRatiosNoData(t *testing.T) {
 		result, err := app.CKmeans(
 			context.Background(),
 			2011,
+			"2.2",
 			[]string{"doesNotExist1,doesNotExist2"},
 			[]string{"LAD"},
 			testK,
@@ -732,6 +742,7 @@ func TestCkmeansArgParsingAndValidation(t *testing.T) {
 			result, err := app.CKmeans(
 				context.Background(),
 				2011,
+				"2.2",
 				argset["cat"],
 				argset["geotype"],
 				testK,
@@ -771,6 +782,7 @@ func TestCkmeansArgParsingAndValidation(t *testing.T) {
 		result, err := app.CKmeans(
 			context.Background(),
 			2011,
+			"2.2",
 			[]string{"numerator1...numerator3"},
 			[]string{"LAD,MSOA"},
 			testK,
@@ -789,3 +801,53 @@ func TestCkmeansArgParsingAndValidation(t *testing.T) {
 		}
 	}()
 }
+
+func TestStoreBreaksSkipsLargeK(t *testing.T) {
+	// GIVEN the database is setup
+	dsn := comptests.DefaultDSN
+	db, err := database.Open("pgx", dsn)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	func() {
+		db.DB().Exec("BEGIN")
+		defer db.DB().Exec("ROLLBACK")
+
+		// AND GIVEN a category with only 3 LADs
+		metrics := map[string]map[string][]float64{
+			"LAD": {
+				"category1": {1.0, 2.0, 3.0},
+			},
+		}
+		ckmeansTestSetup(t, db, metrics)
+
+		app, err := New(db, nil, 100)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		// WHEN we store breaks for k up to more than the number of LADs
+		err = app.StoreBreaks(context.Background(), 2011, "2.2", []string{"category1"}, []string{"LAD"}, []int{2, 3, 4}, "")
+
+		// THEN k=4 is reported as skipped
+		if err == nil || !strings.Contains(err.Error(), "k=4") {
+			t.Errorf("got error %v, want k=4 skipped", err)
+		}
+
+		// AND the breaks for the smaller ks are still stored
+		for _, k := range []int{2, 3} {
+			parser := NewBreaksParser(nil, "", k)
+			if err := parser.parseArgs([]string{"category1"}, []string{"LAD"}); err != nil {
+				t.Fatal(err)
+			}
+			_, ok, err := app.storedBreaks(context.Background(), 2011, "2.2", parser)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !ok {
+				t.Errorf("k=%d: breaks not stored", k)
+			}
+		}
+	}()
+}
@@ -0,0 +1,185 @@
+package geodata
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/ONSdigital/dp-find-insights-poc-api/sentinel"
+)
+
+// Classification methods accepted by NewClassifier.
+const (
+	ClassifyCkmeans  = "ckmeans"
+	ClassifyQuantile = "quantile"
+	ClassifyEqual    = "equal"
+	ClassifyStdDev   = "stddev"
+	ClassifyHeadTail = "headtail"
+)
+
+// Classifier divides metrics into at most k classes for choropleth maps.
+// Breaks returns the upper breakpoint of each class in ascending order,
+// so the last break is the largest metric.
+//
+type Classifier interface {
+	Breaks(metrics []float64, k int) ([]float64, error)
+}
+
+// ClassifierFunc adapts a function to a Classifier.
+type ClassifierFunc func(metrics []float64, k int) ([]float64, error)
+
+func (f ClassifierFunc) Breaks(metrics []float64, k int) ([]float64, error) {
+	return f(metrics, k)
+}
+
+// classifiers maps method names to Classifiers.
+var classifiers = map[string]Classifier{
+	ClassifyCkmeans:  ClassifierFunc(getBreaks),
+	ClassifyQuantile: ClassifierFunc(quantileBreaks),
+	ClassifyEqual:    ClassifierFunc(equalBreaks),
+	ClassifyStdDev:   ClassifierFunc(stddevBreaks),
+	ClassifyHeadTail: ClassifierFunc(headTailBreaks),
+}
+
+// NewClassifier returns the Classifier for method.
+// An empty method means ckmeans.
+func NewClassifier(method string) (Classifier, error) {
+	if method == "" {
+		method = ClassifyCkmeans
+	}
+	classifier, ok := classifiers[strings.ToLower(method)]
+	if !ok {
+		return nil, fmt.Errorf(
+			"%w: method must be one of %s",
+			sentinel.ErrInvalidParams,
+			strings.Join([]string{ClassifyCkmeans, ClassifyQuantile, ClassifyEqual, ClassifyStdDev, ClassifyHeadTail}, ", "),
+		)
+	}
+	return classifier, nil
+}
+
+// checkClassifyArgs returns an error if metrics cannot be divided into k classes.
+func checkClassifyArgs(metrics []float64, k int) error {
+	if k < 1 {
+		return fmt.Errorf("%w: k must be at least 1", sentinel.ErrInvalidParams)
+	}
+	if len(metrics) == 0 {
+		return fmt.Errorf("%w: no data to classify", sentinel.ErrPartialContent)
+	}
+	return nil
+}
+
+// sorted returns a sorted copy of metrics.
+func sorted(metrics []float64) []float64 {
+	s := make([]float64, len(metrics))
+	copy(s, metrics)
+	sort.Float64s(s)
+	return s
+}
+
+// quantileBreaks puts an equal number of metrics in each class.
+// Classes can share a break when many metrics have the same value.
+func quantileBreaks(metrics []float64, k int) ([]float64, error) {
+	if err := checkClassifyArgs(metrics, k); err != nil {
+		return nil, err
+	}
+	s := sorted(metrics)
+	n := len(s)
+
+	breaks := make([]float64, k)
+	for i := 1; i <= k; i++ {
+		idx := int(math.Ceil(float64(i*n)/float64(k))) - 1
+		if idx < 0 {
+			idx = 0
+		}
+		breaks[i-1] = s[idx]
+	}
+	return breaks, nil
+}
+
+// equalBreaks divides the range of metrics into k classes of equal width.
+func equalBreaks(metrics []float64, k int) ([]float64, error) {
+	if err := checkClassifyArgs(metrics, k); err != nil {
+		return nil, err
+	}
+	minmax := getMinMax(metrics)
+	min, max := minmax[0], minmax[1]
+	width := (max - min) / float64(k)
+
+	breaks := make([]float64, k)
+	for i := 1; i < k; i++ {
+		breaks[i-1] = min + float64(i)*width
+	}
+	breaks[k-1] = max
+	return breaks, nil
+}
+
+// stddevBreaks makes classes one standard deviation wide, centred on the mean.
+// With an odd k the middle class straddles the mean; with an even k the mean is a break.
+// Classes at the ends may be empty if the metrics are skewed.
+func stddevBreaks(metrics []float64, k int) ([]float64, error) {
+	if err := checkClassifyArgs(metrics, k); err != nil {
+		return nil, err
+	}
+	mean, sd := meanStdDev(metrics)
+
+	breaks := make([]float64, k)
+	for i := 1; i < k; i++ {
+		breaks[i-1] = mean + sd*(float64(i)-float64(k)/2)
+	}
+	breaks[k-1] = getMinMax(metrics)[1]
+	return breaks, nil
+}
+
+// meanStdDev returns the mean and population standard deviation of metrics.
+func meanStdDev(metrics []float64) (float64, float64) {
+	var sum float64
+	for _, v := range metrics {
+		sum += v
+	}
+	mean := sum / float64(len(metrics))
+	var sumsq float64
+	for _, v := range metrics {
+		sumsq += (v - mean) * (v - mean)
+	}
+	return mean, math.Sqrt(sumsq / float64(len(metrics)))
+}
+
+// headTailRatio is the largest fraction of metrics that can be in the head for head/tail
+// breaks to continue; beyond it the remaining metrics are no longer heavy-tailed.
+const headTailRatio = 0.4
+
+// headTailBreaks splits metrics at their mean, then splits the head (the metrics above the
+// mean) again, for as long as the head is a minority, up to k classes.
+// It suits heavy-tailed data such as population density, and may return fewer than k classes.
+// See Jiang (2013), Head/tail breaks: a new classification scheme for data with a heavy-tailed distribution.
+func headTailBreaks(metrics []float64, k int) ([]float64, error) {
+	if err := checkClassifyArgs(metrics, k); err != nil {
+		return nil, err
+	}
+	max := getMinMax(metrics)[1]
+
+	var breaks []float64
+	head := metrics
+	for len(breaks) < k-1 && len(head) > 1 {
+		var sum float64
+		for _, v := range head {
+			sum += v
+		}
+		mean := sum / float64(len(head))
+
+		var next []float64
+		for _, v := range head {
+			if v > mean {
+				next = append(next, v)
+			}
+		}
+		if len(next) == 0 || float64(len(next))/float64(len(head)) > headTailRatio {
+			break
+		}
+		breaks = append(breaks, mean)
+		head = next
+	}
+	return append(breaks, max), nil
+}
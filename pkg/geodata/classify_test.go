@@ -0,0 +1,113 @@
+package geodata
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ONSdigital/dp-find-insights-poc-api/sentinel"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewClassifier(t *testing.T) {
+	for _, method := range []string{"", "ckmeans", "quantile", "equal", "stddev", "headtail", "Quantile"} {
+		classifier, err := NewClassifier(method)
+		assert.NoError(t, err, method)
+		assert.NotNil(t, classifier, method)
+	}
+
+	_, err := NewClassifier("jenks")
+	if !errors.Is(err, sentinel.ErrInvalidParams) {
+		t.Errorf("got error %v, want %v", err, sentinel.ErrInvalidParams)
+	}
+}
+
+func TestClassifiers(t *testing.T) {
+	metrics := []float64{9, 1, 8, 2, 7, 3, 6, 4, 5, 10}
+
+	var tests = map[string]struct {
+		method  string
+		metrics []float64
+		k       int
+		want    []float64
+		wantErr error
+	}{
+		"quantile": {
+			method:  ClassifyQuantile,
+			metrics: metrics,
+			k:       5,
+			want:    []float64{2, 4, 6, 8, 10},
+		},
+		"quantile uneven": {
+			method:  ClassifyQuantile,
+			metrics: metrics,
+			k:       3,
+			want:    []float64{4, 7, 10},
+		},
+		"equal": {
+			method:  ClassifyEqual,
+			metrics: []float64{0, 3, 10, 7},
+			k:       4,
+			want:    []float64{2.5, 5, 7.5, 10},
+		},
+		"stddev even k": {
+			method:  ClassifyStdDev,
+			metrics: []float64{2, 4, 4, 4, 5, 5, 7, 9}, // mean 5, sd 2
+			k:       4,
+			want:    []float64{3, 5, 7, 9},
+		},
+		"stddev odd k": {
+			method:  ClassifyStdDev,
+			metrics: []float64{2, 4, 4, 4, 5, 5, 7, 9},
+			k:       3,
+			want:    []float64{4, 6, 9},
+		},
+		"headtail": {
+			method:  ClassifyHeadTail,
+			metrics: []float64{1, 1, 1, 1, 1, 1, 1, 1, 2, 3, 40, 60, 100},
+			k:       5,
+			want:    []float64{16.384615384615383, 66.66666666666667, 100},
+		},
+		"headtail stops at k": {
+			method:  ClassifyHeadTail,
+			metrics: []float64{1, 1, 1, 1, 1, 1, 1, 1, 2, 3, 40, 60, 100},
+			k:       2,
+			want:    []float64{16.384615384615383, 100},
+		},
+		"headtail not heavy-tailed": {
+			method:  ClassifyHeadTail,
+			metrics: metrics,
+			k:       5,
+			want:    []float64{10},
+		},
+		"ckmeans": {
+			method:  ClassifyCkmeans,
+			metrics: []float64{1, 2, 3, 100, 101, 102},
+			k:       2,
+			want:    []float64{3, 102},
+		},
+		"bad k": {
+			method:  ClassifyEqual,
+			metrics: metrics,
+			k:       0,
+			wantErr: sentinel.ErrInvalidParams,
+		},
+		"no data": {
+			method:  ClassifyQuantile,
+			k:       3,
+			wantErr: sentinel.ErrPartialContent,
+		},
+	}
+
+	for name, test := range tests {
+		classifier, err := NewClassifier(test.method)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := classifier.Breaks(test.metrics, test.k)
+		if !errors.Is(err, test.wantErr) {
+			t.Errorf("%s: error %v, want %v", name, err, test.wantErr)
+			continue
+		}
+		assert.Equal(t, test.want, got, name)
+	}
+}
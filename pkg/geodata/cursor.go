@@ -0,0 +1,27 @@
+package geodata
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	"github.com/ONSdigital/dp-find-insights-poc-api/sentinel"
+)
+
+// EncodeCursor makes an opaque paging cursor from the last geocode on a page.
+// Clients shouldn't rely on what is inside a cursor, so we are free to change it.
+func EncodeCursor(geocode string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(geocode))
+}
+
+// DecodeCursor returns the geocode inside a cursor made by EncodeCursor.
+// An empty cursor means the first page, and decodes to an empty geocode.
+func DecodeCursor(cursor string) (string, error) {
+	if cursor == "" {
+		return "", nil
+	}
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || len(b) == 0 {
+		return "", fmt.Errorf("%w: invalid cursor", sentinel.ErrInvalidParams)
+	}
+	return string(b), nil
+}
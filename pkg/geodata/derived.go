@@ -0,0 +1,112 @@
+package geodata
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/ONSdigital/dp-find-insights-poc-api/model"
+	"github.com/ONSdigital/dp-find-insights-poc-api/pkg/table"
+	"github.com/ONSdigital/dp-find-insights-poc-api/pkg/where"
+	"github.com/ONSdigital/dp-find-insights-poc-api/sentinel"
+)
+
+// PercentOfTotal is the only value accepted for Derived.PercentOf.
+const PercentOfTotal = "total"
+
+// Derived describes computed columns to add next to the raw category columns in metrics output.
+//
+// If DivideBy is set to a category code, each other category gets a <cat>_per_<DivideBy>
+// column holding cat / DivideBy.
+//
+// If PercentOf is "total", each non-total category gets a <cat>_pct column holding
+// its value as a percentage of its table's total category (see model.IsTotalCat).
+//
+// A derived value with a missing or zero denominator is null.
+//
+type Derived struct {
+	DivideBy  string
+	PercentOf string
+}
+
+// none is true if no derived columns are wanted.
+func (d Derived) none() bool {
+	return d.DivideBy == "" && d.PercentOf == ""
+}
+
+// prepare checks d, and returns catset with the categories needed as denominators added,
+// along with the Deriver that computes the columns.
+// The denominator categories are output as ordinary columns too.
+// If no derived columns are wanted, catset is returned unchanged with a nil Deriver.
+//
+func (d Derived) prepare(catset *where.ValueSet) (*where.ValueSet, table.Deriver, error) {
+	if d.none() {
+		return catset, nil, nil
+	}
+	if d.PercentOf != "" && d.PercentOf != PercentOfTotal {
+		return nil, nil, fmt.Errorf("%w: percent_of must be %q", sentinel.ErrInvalidParams, PercentOfTotal)
+	}
+
+	// An empty catset already means every category, or every category in the census table,
+	// so denominators only need adding when categories are named.
+	if catset != nil && (len(catset.Singles) > 0 || len(catset.Ranges) > 0) {
+		seen := map[string]bool{}
+		for _, single := range catset.Singles {
+			seen[single] = true
+		}
+		wanted := []string{}
+		if d.DivideBy != "" {
+			wanted = append(wanted, d.DivideBy)
+		}
+		if d.PercentOf != "" {
+			for _, single := range catset.Singles {
+				wanted = append(wanted, model.TotalCat(single))
+			}
+			// A range may cover several census tables, each needing its own total.
+			// Tables after the first start with their total, so only the first table's
+			// total can fall outside the range; the totals at both ends are named anyway.
+			for _, r := range catset.Ranges {
+				wanted = append(wanted, model.TotalCat(r.Low), model.TotalCat(r.High))
+			}
+		}
+
+		added := &where.ValueSet{
+			Singles: append([]string{}, catset.Singles...),
+			Ranges:  catset.Ranges,
+		}
+		for _, cat := range wanted {
+			if cat != "" && !seen[cat] {
+				seen[cat] = true
+				added.AddSingle(cat)
+			}
+		}
+		catset = added
+	}
+
+	return catset, d.deriver(), nil
+}
+
+// deriver returns the Deriver computing the columns described by d.
+func (d Derived) deriver() table.Deriver {
+	return func(values map[string]float64) map[string]float64 {
+		derived := map[string]float64{}
+		for cat, value := range values {
+			if d.DivideBy != "" && cat != d.DivideBy {
+				derived[cat+"_per_"+d.DivideBy] = ratio(value, values, d.DivideBy)
+			}
+			if d.PercentOf == PercentOfTotal && !model.IsTotalCat(cat) {
+				derived[cat+"_pct"] = 100 * ratio(value, values, model.TotalCat(cat))
+			}
+		}
+		return derived
+	}
+}
+
+// ratio divides value by the value of category denom in values.
+// The result is NaN if denom is missing or zero.
+func ratio(value float64, values map[string]float64, denom string) float64 {
+	d, ok := values[denom]
+	if !ok || d == 0 {
+		return math.NaN()
+	}
+	return value / d
+}
@@ -0,0 +1,135 @@
+package geodata
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/ONSdigital/dp-find-insights-poc-api/pkg/where"
+	"github.com/ONSdigital/dp-find-insights-poc-api/sentinel"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDerived_prepare(t *testing.T) {
+	var tests = map[string]struct {
+		derived Derived
+		catset  *where.ValueSet
+		want    *where.ValueSet
+		wantErr error
+	}{
+		"nothing derived": {
+			catset: &where.ValueSet{Singles: []string{"QS101EW0002"}},
+			want:   &where.ValueSet{Singles: []string{"QS101EW0002"}},
+		},
+		"divide_by added": {
+			derived: Derived{DivideBy: "QS101EW0001"},
+			catset:  &where.ValueSet{Singles: []string{"QS101EW0002"}},
+			want:    &where.ValueSet{Singles: []string{"QS101EW0002", "QS101EW0001"}},
+		},
+		"divide_by already there": {
+			derived: Derived{DivideBy: "QS101EW0001"},
+			catset:  &where.ValueSet{Singles: []string{"QS101EW0001", "QS101EW0002"}},
+			want:    &where.ValueSet{Singles: []string{"QS101EW0001", "QS101EW0002"}},
+		},
+		"totals added for singles and ranges": {
+			derived: Derived{PercentOf: "total"},
+			catset: &where.ValueSet{
+				Singles: []string{"QS101EW0002", "QS101EW0003"},
+				Ranges:  []*where.ValueRange{{Low: "QS119EW0002", High: "QS119EW0006"}},
+			},
+			want: &where.ValueSet{
+				Singles: []string{"QS101EW0002", "QS101EW0003", "QS101EW0001", "QS119EW0001"},
+				Ranges:  []*where.ValueRange{{Low: "QS119EW0002", High: "QS119EW0006"}},
+			},
+		},
+		"totals added for a range across tables": {
+			derived: Derived{PercentOf: "total"},
+			catset: &where.ValueSet{
+				Ranges: []*where.ValueRange{{Low: "QS104EW0003", High: "QS105EW0002"}},
+			},
+			want: &where.ValueSet{
+				Singles: []string{"QS104EW0001", "QS105EW0001"},
+				Ranges:  []*where.ValueRange{{Low: "QS104EW0003", High: "QS105EW0002"}},
+			},
+		},
+		"empty catset left alone": {
+			derived: Derived{DivideBy: "QS101EW0001", PercentOf: "total"},
+			catset:  &where.ValueSet{},
+			want:    &where.ValueSet{},
+		},
+		"bad percent_of": {
+			derived: Derived{PercentOf: "QS101EW0001"},
+			catset:  &where.ValueSet{},
+			wantErr: sentinel.ErrInvalidParams,
+		},
+	}
+
+	for name, test := range tests {
+		got, deriver, err := test.derived.prepare(test.catset)
+		if !errors.Is(err, test.wantErr) {
+			t.Errorf("%s: error %v, want %v", name, err, test.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		assert.Equal(t, test.want, got, name)
+		assert.Equal(t, test.derived.none(), deriver == nil, name)
+	}
+}
+
+func TestDerived_rangeAcrossTables(t *testing.T) {
+	// every category of two census tables
+	all := map[string]float64{
+		"QS104EW0001": 100,
+		"QS104EW0002": 40,
+		"QS104EW0003": 60,
+		"QS105EW0001": 50,
+		"QS105EW0002": 10,
+		"QS105EW0003": 40,
+	}
+
+	d := Derived{PercentOf: "total"}
+	catset, deriver, err := d.prepare(&where.ValueSet{
+		Ranges: []*where.ValueRange{{Low: "QS104EW0003", High: "QS105EW0002"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// select categories as the metrics queries do
+	want := cantWanted(catset, "")
+	values := map[string]float64{}
+	for cat, value := range all {
+		if want(cat) {
+			values[cat] = value
+		}
+	}
+
+	got := deriver(values)
+	assert.Equal(t, 60.0, got["QS104EW0003_pct"])
+	assert.Equal(t, 20.0, got["QS105EW0002_pct"], "second table has its own total")
+	assert.NotContains(t, got, "QS105EW0003_pct", "outside the range")
+}
+
+func TestDerived_deriver(t *testing.T) {
+	values := map[string]float64{
+		"QS101EW0001": 200,
+		"QS101EW0002": 50,
+		"QS119EW0002": 7,
+	}
+
+	d := Derived{DivideBy: "QS101EW0002", PercentOf: "total"}
+	got := d.deriver()(values)
+
+	assert.Equal(t, 4.0, got["QS101EW0001_per_QS101EW0002"])
+	assert.Equal(t, 0.14, got["QS119EW0002_per_QS101EW0002"])
+	assert.Equal(t, 25.0, got["QS101EW0002_pct"])
+	assert.NotContains(t, got, "QS101EW0002_per_QS101EW0002", "divisor divided by itself")
+	assert.NotContains(t, got, "QS101EW0001_pct", "total as a percentage of itself")
+	assert.True(t, math.IsNaN(got["QS119EW0002_pct"]), "missing total")
+
+	values["QS101EW0002"] = 0
+	got = d.deriver()(values)
+	assert.True(t, math.IsNaN(got["QS101EW0001_per_QS101EW0002"]), "zero denominator")
+}
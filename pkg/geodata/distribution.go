@@ -0,0 +1,159 @@
+package geodata
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/ONSdigital/dp-find-insights-poc-api/sentinel"
+)
+
+const (
+	defaultDistributionBins = 10
+	maxDistributionBins     = 100
+)
+
+// Distribution summarises the values of one category for one geotype, for drawing a histogram in a map legend.
+type Distribution struct {
+	Count  int     `json:"count"`
+	Min    float64 `json:"min"`
+	Max    float64 `json:"max"`
+	Mean   float64 `json:"mean"`
+	Median float64 `json:"median"`
+	Q1     float64 `json:"q1"` // lower quartile
+	Q3     float64 `json:"q3"` // upper quartile
+	StdDev float64 `json:"stddev"`
+	Bins   []Bin   `json:"bins"`
+}
+
+// Bin is one bar of a histogram. It counts the values above Low, up to and including High.
+// The first bin also counts values equal to Low.
+type Bin struct {
+	Low   float64 `json:"low"`
+	High  float64 `json:"high"`
+	Count int     `json:"count"`
+}
+
+// Distribution gets a Distribution for each geotype in each category, over the geographies in sel.
+// Optionally, if divideBy is not blank, all categories are divided by the divideBy category first, as for Breaks.
+//
+// The histogram has nbins bins of equal width between the min and max, or if breaks is given, one bin for
+// each break, as returned by Breaks. breaks can be a single value or comma-separated values.
+// Values above the last break go in an extra bin.
+//
+func (app *Geodata) Distribution(ctx context.Context, year int, version string, sel Selection, cat []string, geotype []string, nbins int, breaks []string, divideBy string) (map[string]map[string]*Distribution, error) {
+	upper, err := parseBinBreaks(nbins, breaks)
+	if err != nil {
+		return nil, err
+	}
+
+	parser := NewBreaksParser(nil, divideBy, 0)
+	if err := parser.parseArgs(cat, geotype); err != nil {
+		return nil, err
+	}
+	if err := app.collectMetrics(ctx, year, version, sel, parser); err != nil {
+		return nil, err
+	}
+
+	result := map[string]map[string]*Distribution{}
+	for _, catcode := range parser.catcodes {
+		for _, geotype := range parser.geotypes {
+			metrics := parser.metrics[catcode][geotype]
+			// a selection may have no geographies of this geotype
+			if len(metrics) == 0 {
+				continue
+			}
+			if _, prs := result[catcode]; !prs {
+				result[catcode] = map[string]*Distribution{}
+			}
+			result[catcode][geotype] = distribution(metrics, nbins, upper)
+		}
+	}
+	return result, nil
+}
+
+// parseBinBreaks validates the histogram options and parses breaks into ascending numbers.
+func parseBinBreaks(nbins int, breaks []string) ([]float64, error) {
+	if len(breaks) == 0 {
+		if nbins < 0 || nbins > maxDistributionBins {
+			return nil, fmt.Errorf("%w: bins must be 1..%d", sentinel.ErrInvalidParams, maxDistributionBins)
+		}
+		return nil, nil
+	}
+	if nbins != 0 {
+		return nil, fmt.Errorf("%w: bins and breaks cannot both be used", sentinel.ErrInvalidParams)
+	}
+
+	var upper []float64
+	for _, arg := range breaks {
+		for _, token := range strings.Split(arg, ",") {
+			v, err := strconv.ParseFloat(strings.TrimSpace(token), 64)
+			if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+				return nil, fmt.Errorf("%w: breaks must be numbers: %q", sentinel.ErrInvalidParams, token)
+			}
+			if len(upper) > 0 && v <= upper[len(upper)-1] {
+				return nil, fmt.Errorf("%w: breaks must be in ascending order", sentinel.ErrInvalidParams)
+			}
+			upper = append(upper, v)
+		}
+	}
+	if len(upper) > maxDistributionBins {
+		return nil, fmt.Errorf("%w: at most %d breaks", sentinel.ErrInvalidParams, maxDistributionBins)
+	}
+	return upper, nil
+}
+
+// distribution summarises metrics, which must not be empty.
+// The histogram has one bin for each break in upper, or nbins equal bins if there are no breaks.
+func distribution(metrics []float64, nbins int, upper []float64) *Distribution {
+	s := sorted(metrics)
+	mean, sd := meanStdDev(s)
+	d := &Distribution{
+		Count:  len(s),
+		Min:    s[0],
+		Max:    s[len(s)-1],
+		Mean:   mean,
+		Median: quantile(s, 0.5),
+		Q1:     quantile(s, 0.25),
+		Q3:     quantile(s, 0.75),
+		StdDev: sd,
+	}
+
+	if len(upper) == 0 {
+		if nbins == 0 {
+			nbins = defaultDistributionBins
+		}
+		upper, _ = equalBreaks(s, nbins)
+	} else if d.Max > upper[len(upper)-1] {
+		upper = append(upper[:len(upper):len(upper)], d.Max)
+	}
+
+	low := math.Min(d.Min, upper[0])
+	d.Bins = make([]Bin, len(upper))
+	for i, high := range upper {
+		d.Bins[i] = Bin{Low: low, High: high}
+		low = high
+	}
+
+	// s is sorted, so walk the values and bins together
+	bin := 0
+	for _, v := range s {
+		for bin < len(upper)-1 && v > upper[bin] {
+			bin++
+		}
+		d.Bins[bin].Count++
+	}
+	return d
+}
+
+// quantile returns the q quantile of the sorted values s, interpolating between values.
+func quantile(s []float64, q float64) float64 {
+	pos := q * float64(len(s)-1)
+	i := int(math.Floor(pos))
+	if i+1 >= len(s) {
+		return s[len(s)-1]
+	}
+	return s[i] + (pos-float64(i))*(s[i+1]-s[i])
+}
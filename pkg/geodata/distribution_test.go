@@ -0,0 +1,88 @@
+package geodata
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ONSdigital/dp-find-insights-poc-api/sentinel"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_parseBinBreaks(t *testing.T) {
+	var tests = map[string]struct {
+		nbins   int
+		breaks  []string
+		want    []float64
+		wantErr error
+	}{
+		"default bins":       {},
+		"fixed bins":         {nbins: 20},
+		"breaks":             {breaks: []string{"1.5,3", "10"}, want: []float64{1.5, 3, 10}},
+		"too many bins":      {nbins: 101, wantErr: sentinel.ErrInvalidParams},
+		"bins and breaks":    {nbins: 5, breaks: []string{"1"}, wantErr: sentinel.ErrInvalidParams},
+		"not a number":       {breaks: []string{"1,two"}, wantErr: sentinel.ErrInvalidParams},
+		"not ascending":      {breaks: []string{"3,2"}, wantErr: sentinel.ErrInvalidParams},
+		"infinity":           {breaks: []string{"1,Inf"}, wantErr: sentinel.ErrInvalidParams},
+		"negative bin count": {nbins: -1, wantErr: sentinel.ErrInvalidParams},
+	}
+
+	for name, test := range tests {
+		got, err := parseBinBreaks(test.nbins, test.breaks)
+		if !errors.Is(err, test.wantErr) {
+			t.Errorf("%s: error %v, want %v", name, err, test.wantErr)
+			continue
+		}
+		assert.Equal(t, test.want, got, name)
+	}
+}
+
+func Test_distribution(t *testing.T) {
+	metrics := []float64{7, 1, 3, 9, 5, 2, 8, 4, 6, 10}
+
+	t.Run("summary", func(t *testing.T) {
+		d := distribution(metrics, 0, nil)
+		assert.Equal(t, 10, d.Count)
+		assert.Equal(t, 1.0, d.Min)
+		assert.Equal(t, 10.0, d.Max)
+		assert.Equal(t, 5.5, d.Mean)
+		assert.Equal(t, 5.5, d.Median)
+		assert.Equal(t, 3.25, d.Q1)
+		assert.Equal(t, 7.75, d.Q3)
+		assert.InDelta(t, 2.8723, d.StdDev, 0.0001)
+		assert.Len(t, d.Bins, defaultDistributionBins)
+	})
+
+	t.Run("fixed bins", func(t *testing.T) {
+		d := distribution(metrics, 3, nil)
+		assert.Equal(t, []Bin{
+			{Low: 1, High: 4, Count: 4},
+			{Low: 4, High: 7, Count: 3},
+			{Low: 7, High: 10, Count: 3},
+		}, d.Bins)
+	})
+
+	t.Run("breaks", func(t *testing.T) {
+		d := distribution(metrics, 0, []float64{2, 5, 8})
+		assert.Equal(t, []Bin{
+			{Low: 1, High: 2, Count: 2},
+			{Low: 2, High: 5, Count: 3},
+			{Low: 5, High: 8, Count: 3},
+			{Low: 8, High: 10, Count: 2}, // values above the last break
+		}, d.Bins)
+	})
+
+	t.Run("breaks below the data", func(t *testing.T) {
+		d := distribution(metrics, 0, []float64{0.5, 20})
+		assert.Equal(t, []Bin{
+			{Low: 0.5, High: 0.5, Count: 0},
+			{Low: 0.5, High: 20, Count: 10},
+		}, d.Bins)
+	})
+
+	t.Run("single value", func(t *testing.T) {
+		d := distribution([]float64{3}, 2, nil)
+		assert.Equal(t, 3.0, d.Median)
+		assert.Equal(t, 0.0, d.StdDev)
+		assert.Equal(t, 1, d.Bins[0].Count)
+	})
+}
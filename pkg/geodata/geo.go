@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 
+	"github.com/ONSdigital/dp-find-insights-poc-api/model"
 	"github.com/twpayne/go-geom/encoding/geojson"
 	"github.com/twpayne/go-geom/encoding/wkb"
 )
@@ -17,13 +18,18 @@ type Resp struct {
 	GeoJSON *geojson.FeatureCollection `json:"geo_json"`
 }
 
-func (app *Geodata) Geo(ctx context.Context, year int, geocode string, geoname string) (*Resp, error) {
+// Geo returns the centroid, boundary and bounding box of the geography with geocode,
+// or with geoname in English or Welsh.
+// The name is returned in lang, falling back to English where there is no translation.
+//
+func (app *Geodata) Geo(ctx context.Context, year int, geocode string, geoname string, lang string) (*Resp, error) {
 	queryString := `
 	SELECT
 		ST_AsBinary(geo.wkb_long_lat_geom),
 		ST_AsBinary(geo.wkb_geometry),
 		ST_AsBinary(ST_BoundingDiagonal(geo.wkb_geometry)),
 		geo.name,
+		geo.welsh_name,
 		geo.code,
 		geo_type.name
 	FROM
@@ -40,7 +46,7 @@ func (app *Geodata) Geo(ctx context.Context, year int, geocode string, geoname s
 		queryCondition = geocode
 	} else {
 		conditionString = `
-			WHERE geo.name = $1
+			WHERE (geo.name = $1 OR geo.welsh_name = $1)
 			AND geo.type_id = geo_type.id
 			`
 		queryCondition = geoname
@@ -54,7 +60,8 @@ func (app *Geodata) Geo(ctx context.Context, year int, geocode string, geoname s
 
 	var centroid, boundary, bbox []byte
 	var name, code, geotype string
-	err = stmt.QueryRowContext(ctx, queryCondition).Scan(&centroid, &boundary, &bbox, &name, &code, &geotype)
+	var welshName sql.NullString
+	err = stmt.QueryRowContext(ctx, queryCondition).Scan(&centroid, &boundary, &bbox, &name, &welshName, &code, &geotype)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return &Resp{}, nil
@@ -89,7 +96,7 @@ func (app *Geodata) Geo(ctx context.Context, year int, geocode string, geoname s
 	}
 
 	r := &Resp{GeoJSON: collection}
-	r.Meta.Name = name
+	r.Meta.Name = model.Localise(lang, name, welshName.String)
 	r.Meta.Code = code
 	r.Meta.Geotype = geotype
 
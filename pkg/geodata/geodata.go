@@ -9,7 +9,6 @@ import (
 	"github.com/ONSdigital/dp-find-insights-poc-api/model"
 	"github.com/ONSdigital/dp-find-insights-poc-api/pkg/database"
 	"github.com/ONSdigital/dp-find-insights-poc-api/pkg/table"
-	"github.com/ONSdigital/dp-find-insights-poc-api/pkg/timer"
 	"github.com/ONSdigital/dp-find-insights-poc-api/pkg/where"
 	"github.com/ONSdigital/dp-find-insights-poc-api/sentinel"
 	"github.com/ONSdigital/log.go/v2/log"
@@ -33,113 +32,27 @@ func New(db *database.Database, cant *cantabular.Client, maxMetrics int) (*Geoda
 	}, nil
 }
 
-func (app *Geodata) Query(ctx context.Context, year int, bbox, location string, radius int, polygon string, geotypes, rows, cols []string, censustable string) (string, error) {
-	return app.censusQuery(ctx, year, rows, bbox, location, radius, polygon, geotypes, cols, censustable)
-}
-
-// collectCells runs the query in sql and returns the results as a csv.
-// sql must be a query against the geo_metric table selecting exactly
-// code, category and metric.
-//
-func (app *Geodata) collectCells(ctx context.Context, sql string, include []string) (string, error) {
-	// Allocate output table
-	//
-	tbl := table.New()
-
-	// Set up output buffer
-	//
-	var body strings.Builder
-	body.Grow(1000000)
-
-	// Query the db.
-	//
-	t := timer.New("query")
-	t.Start()
-	rows, err := app.db.DB().QueryContext(ctx, sql)
-	if err != nil {
-		return "", err
-	}
-	t.Stop()
-	t.Log(ctx)
-	defer rows.Close()
-
-	tnext := timer.New("next")
-	tscan := timer.New("scan")
-	var nmetrics int
-	for {
-		tnext.Start()
-		ok := rows.Next()
-		tnext.Stop()
-		if !ok {
-			break
-		}
-
-		nmetrics++
-		if app.maxMetrics > 0 {
-			if nmetrics > app.maxMetrics {
-				return "", fmt.Errorf("%w: limit is %d", sentinel.ErrTooManyMetrics, app.maxMetrics)
-			}
-		}
-
-		var geo string
-		var geotype string
-		var cat string
-		var value float64
-
-		tscan.Start()
-		err := rows.Scan(&geo, &geotype, &cat, &value)
-		tscan.Stop()
-		if err != nil {
-			return "", err
-		}
-
-		tbl.SetCell(geo, geotype, cat, value)
-	}
-	tnext.Log(ctx)
-	tscan.Log(ctx)
-
-	if err := rows.Err(); err != nil {
-		return "", err
-	}
-
-	tgen := timer.New("generate")
-	tgen.Start()
-	err = tbl.Generate(&body, include)
-	tgen.Stop()
-	tgen.Log(ctx)
-	if err != nil {
-		return "", err
-	}
-
-	return body.String(), nil
-}
-
 type CensusQuerySQLArgs struct {
 	Year        int
+	Version     string // data_ver.ver_string
 	Geos        []string
 	BBox        string
 	Location    string
 	Radius      int
 	Polygon     string
+	Within      []string // only geographies below these in the hierarchy
 	Geotypes    []string
 	Cols        []string
 	Censustable string
 }
 
-// censusQuery is the merged query which is the logical OR of the other specific queries.
-// The specific "skinny" queries can probably go away soon.
-//
-// Any combination of rows, bbox, radius and polygon queries can be given, and geotype can be exposed in the csv output.
-//
-// Although this query method is not complicated, it is too long.
-// Break it up in the fullness of time.
-//
-func (app *Geodata) censusQuery(ctx context.Context, year int, geos []string, bbox, location string, radius int, polygon string, geotypes, cols []string, censustable string) (string, error) {
-
-	sql, include, err := CensusQuerySQL(
+// censusQuerySQL generates and logs the SQL for QueryStream.
+func censusQuerySQL(ctx context.Context, year int, version string, geos []string, bbox, location string, radius int, polygon string, geotypes, cols []string, censustable string) (string, []interface{}, []string, error) {
+	sql, values, include, err := CensusQuerySQL(
 		ctx,
 		CensusQuerySQLArgs{
 			Year:        year,
+			Version:     version,
 			Geos:        geos,
 			BBox:        bbox,
 			Location:    location,
@@ -151,84 +64,62 @@ func (app *Geodata) censusQuery(ctx context.Context, year int, geos []string, bb
 		},
 	)
 	if err != nil {
-		return "", err
+		return "", nil, nil, err
 	}
 
-	log.Info(ctx, "sql", log.Data{"query": sql})
+	log.Info(ctx, "sql", log.Data{"query": sql, "args": values})
 
-	return app.collectCells(ctx, sql, include)
+	return sql, values, include, nil
 }
 
-func CensusQuerySQL(ctx context.Context, args CensusQuerySQLArgs) (sql string, include []string, err error) {
+// CensusQuerySQL generates the SQL for a census query.
+// User supplied values are never interpolated into the SQL; they are returned in values,
+// in $n placeholder order, to be passed as bind parameters.
+func CensusQuerySQL(ctx context.Context, args CensusQuerySQLArgs) (sql string, values []interface{}, include []string, err error) {
 	// validate args
 	if err := validateCensusQuery(args); err != nil {
-		return sql, include, err
-	}
-
-	var geoConditions string
-	if !wantAllRows(args.Geos) {
-		// fetch conditions SQL
-		geoCondition, geoErr := geoSQL(args.Geos)
-		bboxCondition, bboxErr := bboxSQL(args.BBox)
-		radiusCondition, radiusErr := radiusSQL(args.Location, args.Radius)
-		polygonCondition, polygonErr := polygonSQL(args.Polygon)
-
-		// check errs, return on first found
-		for _, err := range []error{
-			geoErr,
-			bboxErr,
-			radiusErr,
-			polygonErr,
-		} {
-			if err != nil {
-				return sql, include, err
-			}
-		}
+		return sql, values, include, err
+	}
 
-		// collate join conditions with sql OR
-		var conditions []string
-		for _, condition := range []string{
-			geoCondition,
-			bboxCondition,
-			radiusCondition,
-			polygonCondition,
-		} {
-			if condition != "" {
-				conditions = append(conditions, condition)
-			}
-		}
-		geoConditions = fmt.Sprintf(
-			"AND (\n    %s)\n",
-			strings.Join(conditions, "    OR\n"),
-		)
+	qargs := where.NewArgs()
+
+	geoConditions, err := geoConditionsSQL(args.Geos, args.BBox, args.Location, args.Radius, args.Polygon, qargs)
+	if err != nil {
+		return sql, values, include, err
+	}
+
+	// construct condition for geographies within others
+	withinConditions, err := withinSQL(args.Within, qargs)
+	if err != nil {
+		return sql, values, include, err
 	}
 
 	// construct WHERE condition for geotypes
-	geotypeConditions, err := geotypeSQL("geo_type.name", args.Geotypes)
+	geotypeConditions, err := geotypeSQL("geo_type.name", args.Geotypes, qargs)
 	if err != nil {
-		return sql, include, err
+		return sql, values, include, err
 	}
 
 	// parse cols query strings into a ValueSet
 	catset, err := where.ParseMultiArgs(args.Cols)
 	if err != nil {
-		return sql, include, err
+		return sql, values, include, err
 	}
 
 	// extract special column names from ValueSet
 	include, catset, err = ExtractSpecialCols(catset)
 	if err != nil {
-		return sql, include, err
+		return sql, values, include, err
 	}
 
 	// construct WHERE condition for categories
-	catConditions, err := categorySQL(catset, args.Censustable)
+	catConditions, err := categorySQL(catset, args.Censustable, qargs)
 	if err != nil {
-		return sql, include, err
+		return sql, values, include, err
 	}
 
 	// construct additional conditions for censustable / short_nomis_code
-	censustableFromSQL, censustableAndSQL := censusTableFromAndSQL(args.Censustable)
+	censustableFromSQL, censustableAndSQL := censusTableFromAndSQL(args.Censustable, qargs)
 
 	// construct final SQL
 	template := `
@@ -249,27 +140,30 @@ AND geo_type.id = geo.type_id
     -- geotype conditions:
 %s
 	-- geo conditions:
-%s
+%s%s
 %s
 AND geo_metric.geo_id = geo.id
 AND data_ver.id = geo_metric.data_ver_id
-AND data_ver.census_year = %d
-AND data_ver.ver_string = '2.2'
+AND data_ver.census_year = %s
+AND data_ver.ver_string = %s
 AND nomis_category.id = geo_metric.category_id
 AND nomis_category.year = data_ver.census_year
     -- category conditions:
 %s
+ORDER BY geo.code
 `
 	sql = fmt.Sprintf(
 		template,
 		censustableFromSQL,
 		geotypeConditions,
 		geoConditions,
+		withinConditions,
 		censustableAndSQL,
-		args.Year,
+		qargs.Add(args.Year),
+		qargs.Add(args.Version),
 		catConditions,
 	)
-	return sql, include, nil
+	return sql, qargs.Values(), include, nil
 }
 
 func validateCensusQuery(args CensusQuerySQLArgs) error {
@@ -333,19 +227,83 @@ func isAll(token string) bool {
 	return strings.EqualFold(token, allRowsToken)
 }
 
-func geoSQL(geos []string) (string, error) {
+// geoConditionsSQL generates the condition selecting geographies listed in geos, or
+// within bbox, location/radius or polygon.
+// A geography is selected if it matches any of them.
+// The condition is empty if geos is rows=ALL.
+//
+func geoConditionsSQL(geos []string, bbox, location string, radius int, polygon string, qargs *where.Args) (string, error) {
+	if wantAllRows(geos) {
+		return "", nil
+	}
+
+	// fetch conditions SQL
+	geoCondition, geoErr := geoSQL(geos, qargs)
+	bboxCondition, bboxErr := bboxSQL(bbox, qargs)
+	radiusCondition, radiusErr := radiusSQL(location, radius, qargs)
+	polygonCondition, polygonErr := polygonSQL(polygon, qargs)
+
+	// check errs, return on first found
+	for _, err := range []error{
+		geoErr,
+		bboxErr,
+		radiusErr,
+		polygonErr,
+	} {
+		if err != nil {
+			return "", err
+		}
+	}
+
+	// collate join conditions with sql OR
+	var conditions []string
+	for _, condition := range []string{
+		geoCondition,
+		bboxCondition,
+		radiusCondition,
+		polygonCondition,
+	} {
+		if condition != "" {
+			conditions = append(conditions, condition)
+		}
+	}
+	return fmt.Sprintf(
+		"AND (\n    %s)\n",
+		strings.Join(conditions, "    OR\n"),
+	), nil
+}
+
+func geoSQL(geos []string, qargs *where.Args) (string, error) {
 	set, err := where.ParseMultiArgs(geos)
 	if err != nil {
 		return "", err
 	}
-	return where.WherePart("geo.code", set), nil
+	return where.WherePart("geo.code", set, qargs), nil
 }
 
-func bboxSQL(bbox string) (string, error) {
+func bboxSQL(bbox string, qargs *where.Args) (string, error) {
 	if bbox == "" {
 		return "", nil
 	}
 
+	multipoint, err := parseBBox(bbox)
+	if err != nil {
+		return "", err
+	}
+
+	sql := fmt.Sprintf(`
+geo.wkb_geometry && ST_GeomFromText(
+	%s,
+	4326
+)
+`,
+		qargs.Add(multipoint),
+	)
+	return sql, nil
+}
+
+// parseBBox validates bbox and returns its corners as WKT MULTIPOINT.
+func parseBBox(bbox string) (string, error) {
 	coords, err := parseCoords(bbox)
 	if err != nil {
 		return "", err
@@ -360,70 +318,104 @@ func bboxSQL(bbox string) (string, error) {
 		return "", err
 	}
 
-	sql := fmt.Sprintf(`
-geo.wkb_geometry && ST_GeomFromText(
-	'MULTIPOINT(%f %f, %f %f)',
-	4326
-)
-`,
+	return fmt.Sprintf(
+		"MULTIPOINT(%f %f, %f %f)",
 		coords[0],
 		coords[1],
 		coords[2],
 		coords[3],
-	)
-	return sql, nil
+	), nil
 }
 
-func radiusSQL(location string, radius int) (string, error) {
+func radiusSQL(location string, radius int, qargs *where.Args) (string, error) {
 	if location == "" && radius == 0 {
 		return "", nil
 	}
 
+	coords, err := parseRadius(location, radius)
+	if err != nil {
+		return "", err
+	}
+
+	sql := fmt.Sprintf(`
+ST_DWithin(
+	geo.wkb_long_lat_geom::geography,
+	ST_SetSRID(
+		ST_Point(%s, %s),
+		4326
+	)::geography,
+	%s
+)
+`,
+		qargs.Add(coords[0]),
+		qargs.Add(coords[1]),
+		qargs.Add(radius),
+	)
+	return sql, nil
+}
+
+// parseRadius validates a location and radius, and returns the location's lon and lat.
+func parseRadius(location string, radius int) ([]float64, error) {
 	if location == "" || radius == 0 {
-		return "", fmt.Errorf("%w: radius queries require both location (%s) and radius (%d)", sentinel.ErrInvalidParams, location, radius)
+		return nil, fmt.Errorf("%w: radius queries require both location (%s) and radius (%d)", sentinel.ErrInvalidParams, location, radius)
+	}
+
+	// A circle "overlaps" the UK bounding box if its location point is within the UK bounding box.
+	// This isn't correct, but is useful as a basic sanity check.
+	coords, err := parsePoint(location)
+	if err != nil {
+		return nil, err
 	}
+	if radius < 1 || radius > maxRadius {
+		return nil, fmt.Errorf("%w: radius must be 1..%d: %d", sentinel.ErrInvalidParams, maxRadius, radius)
+	}
+	return coords, nil
+}
 
+// parsePoint validates a single lon,lat point inside the UK and returns its coordinates.
+func parsePoint(location string) ([]float64, error) {
 	coords, err := parseCoords(location)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	if len(coords) != 2 {
-		return "", fmt.Errorf("%w: location must be a single point", sentinel.ErrInvalidParams)
+		return nil, fmt.Errorf("%w: location must be a single point", sentinel.ErrInvalidParams)
 	}
 	if err := checkValidCoords(coords); err != nil {
-		return "", err
+		return nil, err
 	}
-	// A circle "overlaps" the UK bounding box if its location point is within the UK bounding box.
-	// This isn't correct, but is useful as a basic sanity check.
 	if err := CheckOverlapsUK(coords); err != nil {
-		return "", err
+		return nil, err
 	}
-	if radius < 1 || radius > maxRadius {
-		return "", fmt.Errorf("%w: radius must be 1..%d: %d", sentinel.ErrInvalidParams, maxRadius, radius)
+	return coords, nil
+}
+
+func polygonSQL(polygon string, qargs *where.Args) (string, error) {
+	if polygon == "" {
+		return "", nil
+	}
+
+	linestring, err := parsePolygon(polygon)
+	if err != nil {
+		return "", err
 	}
 
 	sql := fmt.Sprintf(`
-ST_DWithin(
-	geo.wkb_long_lat_geom::geography,
-	ST_SetSRID(
-		ST_Point(%f, %f),
+ST_COVERS(
+	ST_Polygon(
+		%s::geometry,
 		4326
-	)::geography,
-	%d
+	),
+	geo.wkb_geometry
 )
 `,
-		coords[0],
-		coords[1],
-		radius,
+		qargs.Add(linestring),
 	)
 	return sql, nil
 }
 
-func polygonSQL(polygon string) (string, error) {
-	if polygon == "" {
-		return "", nil
-	}
-
+// parsePolygon validates polygon and returns it as a WKT LINESTRING.
+func parsePolygon(polygon string) (string, error) {
 	coords, err := parseCoords(polygon)
 	if err != nil {
 		return "", err
@@ -444,39 +436,27 @@ func polygonSQL(polygon string) (string, error) {
 	if err != nil {
 		return "", err
 	}
-
-	sql := fmt.Sprintf(`
-ST_COVERS(
-	ST_Polygon(
-		'LINESTRING (%s)'::geometry,
-		4326
-	),
-	geo.wkb_geometry
-)
-`,
-		linestring,
-	)
-	return sql, nil
+	return "LINESTRING (" + linestring + ")", nil
 }
 
-func censusTableFromAndSQL(censustable string) (string, string) {
+func censusTableFromAndSQL(censustable string, qargs *where.Args) (string, string) {
 	var fromSQL string
 	var andSQL string
 	if censustable != "" {
 		fromSQL = ", nomis_desc"
 		andSQL = fmt.Sprintf(
-			`AND nomis_desc.short_nomis_code = '%s'`,
-			censustable,
+			`AND nomis_desc.short_nomis_code = %s`,
+			qargs.Add(censustable),
 		)
 	}
 	return fromSQL, andSQL
 }
 
-func categorySQL(set *where.ValueSet, censusTable string) (string, error) {
+func categorySQL(set *where.ValueSet, censusTable string, qargs *where.Args) (string, error) {
 	var conditions []string
 
 	// get sql for selecting named categories
-	namedCatSQL := where.WherePart("nomis_category.long_nomis_code", set)
+	namedCatSQL := where.WherePart("nomis_category.long_nomis_code", set, qargs)
 	if namedCatSQL != "" {
 		conditions = append(conditions, namedCatSQL)
 	}
@@ -535,8 +515,8 @@ func isSpecialCol(col string) bool {
 }
 
 // geotypeSQL generates an AND where part for geotypes.
-func geotypeSQL(col string, args []string) (string, error) {
-	set, err := where.ParseMultiArgs(args)
+func geotypeSQL(col string, geotypes []string, qargs *where.Args) (string, error) {
+	set, err := where.ParseMultiArgs(geotypes)
 	if err != nil {
 		return "", err
 	}
@@ -544,7 +524,7 @@ func geotypeSQL(col string, args []string) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	body := where.WherePart(col, set)
+	body := where.WherePart(col, set, qargs)
 
 	if body == "" {
 		return "", nil
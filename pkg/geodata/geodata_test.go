@@ -23,6 +23,7 @@ func TestGetCensusQuery(t *testing.T) {
 		desc        string
 		args        geodata.CensusQuerySQLArgs
 		wantSQL     string
+		wantArgs    []interface{}
 		wantInclude []string
 		wantErr     error
 	}{
@@ -35,8 +36,9 @@ func TestGetCensusQuery(t *testing.T) {
 		{
 			desc: "rows condition only",
 			args: geodata.CensusQuerySQLArgs{
-				Year: 2011,
-				Geos: []string{"E01000001"},
+				Year:    2011,
+				Version: "2.2",
+				Geos:    []string{"E01000001"},
 			},
 			wantSQL: `
 SELECT
@@ -55,23 +57,26 @@ AND geo_type.id = geo.type_id
  -- geotype conditions:
  -- geo conditions:
 AND (
-    geo.code IN ( 'E01000001' )
+    geo.code IN ( $1 )
 )
 AND geo_metric.geo_id = geo.id
 AND data_ver.id = geo_metric.data_ver_id
-AND data_ver.census_year = 2011
-AND data_ver.ver_string = '2.2'
+AND data_ver.census_year = $2
+AND data_ver.ver_string = $3
 AND nomis_category.id = geo_metric.category_id
 AND nomis_category.year = data_ver.census_year
  -- category conditions:
+ORDER BY geo.code
 `,
+			wantArgs: []interface{}{"E01000001", 2011, "2.2"},
 		},
 		// Bounding Box
 		{
 			desc: "bbox condition only",
 			args: geodata.CensusQuerySQLArgs{
-				Year: 2011,
-				BBox: "-0.370947083400182,51.3624781092781,0.17687729439413147,51.673778133460246",
+				Year:    2011,
+				Version: "2.2",
+				BBox:    "-0.370947083400182,51.3624781092781,0.17687729439413147,51.673778133460246",
 			},
 			wantSQL: `
 SELECT
@@ -91,18 +96,20 @@ AND geo_type.id = geo.type_id
  -- geo conditions:
 AND (
 geo.wkb_geometry && ST_GeomFromText(
- 'MULTIPOINT(-0.370947 51.362478, 0.176877 51.673778)',
+ $1,
  4326
 )
 )
 AND geo_metric.geo_id = geo.id
 AND data_ver.id = geo_metric.data_ver_id
-AND data_ver.census_year = 2011
-AND data_ver.ver_string = '2.2'
+AND data_ver.census_year = $2
+AND data_ver.ver_string = $3
 AND nomis_category.id = geo_metric.category_id
 AND nomis_category.year = data_ver.census_year
  -- category conditions:
+ORDER BY geo.code
 `,
+			wantArgs: []interface{}{"MULTIPOINT(-0.370947 51.362478, 0.176877 51.673778)", 2011, "2.2"},
 		},
 		{
 			desc:    "bbox error - non-numeric data",
@@ -123,9 +130,10 @@ AND nomis_category.year = data_ver.census_year
 		{
 			desc: "single col condition",
 			args: geodata.CensusQuerySQLArgs{
-				Year: 2011,
-				Geos: []string{"E01000001"},
-				Cols: []string{"QS119EW0002"},
+				Year:    2011,
+				Version: "2.2",
+				Geos:    []string{"E01000001"},
+				Cols:    []string{"QS119EW0002"},
 			},
 			wantSQL: `
 SELECT
@@ -144,24 +152,27 @@ AND geo_type.id = geo.type_id
  -- geotype conditions:
  -- geo conditions:
 AND (
- geo.code IN ( 'E01000001' )
+ geo.code IN ( $1 )
 )
 AND geo_metric.geo_id = geo.id
 AND data_ver.id = geo_metric.data_ver_id
-AND data_ver.census_year = 2011
-AND data_ver.ver_string = '2.2'
+AND data_ver.census_year = $3
+AND data_ver.ver_string = $4
 AND nomis_category.id = geo_metric.category_id
 AND nomis_category.year = data_ver.census_year
  -- category conditions:
 AND (
- nomis_category.long_nomis_code IN ( 'QS119EW0002' )
+ nomis_category.long_nomis_code IN ( $2 )
 )
+ORDER BY geo.code
 			`,
+			wantArgs: []interface{}{"E01000001", "QS119EW0002", 2011, "2.2"},
 		},
 		{
 			desc: "censustable condition with single geography",
 			args: geodata.CensusQuerySQLArgs{
 				Year:        2011,
+				Version:     "2.2",
 				Geos:        []string{"E01000001"},
 				Censustable: "QS101EW",
 			},
@@ -183,25 +194,28 @@ AND geo_type.id = geo.type_id
  -- geotype conditions:
  -- geo conditions:
 AND (
- geo.code IN ( 'E01000001' )
+ geo.code IN ( $1 )
 )
-AND nomis_desc.short_nomis_code = 'QS101EW'
+AND nomis_desc.short_nomis_code = $2
 AND geo_metric.geo_id = geo.id
 AND data_ver.id = geo_metric.data_ver_id
-AND data_ver.census_year = 2011
-AND data_ver.ver_string = '2.2'
+AND data_ver.census_year = $3
+AND data_ver.ver_string = $4
 AND nomis_category.id = geo_metric.category_id
 AND nomis_category.year = data_ver.census_year
  -- category conditions:
 AND (
  nomis_category.nomis_desc_id = nomis_desc.id
 )
+ORDER BY geo.code
  `,
+			wantArgs: []interface{}{"E01000001", "QS101EW", 2011, "2.2"},
 		},
 		{
 			desc: "censustable condition with single col",
 			args: geodata.CensusQuerySQLArgs{
 				Year:        2011,
+				Version:     "2.2",
 				Geos:        []string{"E01000001"},
 				Censustable: "QS101EW",
 				Cols:        []string{"QS119EW0002"},
@@ -224,27 +238,30 @@ AND geo_type.id = geo.type_id
  -- geotype conditions:
  -- geo conditions:
 AND (
- geo.code IN ( 'E01000001' )
+ geo.code IN ( $1 )
 )
-AND nomis_desc.short_nomis_code = 'QS101EW'
+AND nomis_desc.short_nomis_code = $3
 AND geo_metric.geo_id = geo.id
 AND data_ver.id = geo_metric.data_ver_id
-AND data_ver.census_year = 2011
-AND data_ver.ver_string = '2.2'
+AND data_ver.census_year = $4
+AND data_ver.ver_string = $5
 AND nomis_category.id = geo_metric.category_id
 AND nomis_category.year = data_ver.census_year
  -- category conditions:
 AND (
- nomis_category.long_nomis_code IN ( 'QS119EW0002' )
+ nomis_category.long_nomis_code IN ( $2 )
  OR
  nomis_category.nomis_desc_id = nomis_desc.id
 )
+ORDER BY geo.code
 `,
+			wantArgs: []interface{}{"E01000001", "QS119EW0002", "QS101EW", 2011, "2.2"},
 		},
 		{
 			desc: "censustable condition with multiple col",
 			args: geodata.CensusQuerySQLArgs{
 				Year:        2011,
+				Version:     "2.2",
 				Geos:        []string{"E01000001"},
 				Censustable: "QS101EW",
 				Cols: []string{
@@ -271,27 +288,30 @@ AND geo_type.id = geo.type_id
  -- geotype conditions:
  -- geo conditions:
 AND (
- geo.code IN ( 'E01000001' )
+ geo.code IN ( $1 )
 )
-AND nomis_desc.short_nomis_code = 'QS101EW'
+AND nomis_desc.short_nomis_code = $5
 AND geo_metric.geo_id = geo.id
 AND data_ver.id = geo_metric.data_ver_id
-AND data_ver.census_year = 2011
-AND data_ver.ver_string = '2.2'
+AND data_ver.census_year = $6
+AND data_ver.ver_string = $7
 AND nomis_category.id = geo_metric.category_id
 AND nomis_category.year = data_ver.census_year
  -- category conditions:
 AND (
- nomis_category.long_nomis_code IN ( 'QS119EW0001', 'QS119EW0002', 'QS119EW0003' )
+ nomis_category.long_nomis_code IN ( $2, $3, $4 )
  OR
  nomis_category.nomis_desc_id = nomis_desc.id
 )
+ORDER BY geo.code
 `,
+			wantArgs: []interface{}{"E01000001", "QS119EW0001", "QS119EW0002", "QS119EW0003", "QS101EW", 2011, "2.2"},
 		},
 		{
 			desc: "censustable condition with ranged col",
 			args: geodata.CensusQuerySQLArgs{
 				Year:        2011,
+				Version:     "2.2",
 				Geos:        []string{"E01000001"},
 				Censustable: "QS101EW",
 				Cols:        []string{"QS119EW0001...QS119EW0004"},
@@ -314,27 +334,30 @@ AND geo_type.id = geo.type_id
  -- geotype conditions:
  -- geo conditions:
 AND (
- geo.code IN ( 'E01000001' )
+ geo.code IN ( $1 )
 )
-AND nomis_desc.short_nomis_code = 'QS101EW'
+AND nomis_desc.short_nomis_code = $4
 AND geo_metric.geo_id = geo.id
 AND data_ver.id = geo_metric.data_ver_id
-AND data_ver.census_year = 2011
-AND data_ver.ver_string = '2.2'
+AND data_ver.census_year = $5
+AND data_ver.ver_string = $6
 AND nomis_category.id = geo_metric.category_id
 AND nomis_category.year = data_ver.census_year
  -- category conditions:
 AND (
- nomis_category.long_nomis_code BETWEEN 'QS119EW0001' AND 'QS119EW0004'
+ nomis_category.long_nomis_code BETWEEN $2 AND $3
  OR
  nomis_category.nomis_desc_id = nomis_desc.id
 )
+ORDER BY geo.code
 `,
+			wantArgs: []interface{}{"E01000001", "QS119EW0001", "QS119EW0004", "QS101EW", 2011, "2.2"},
 		},
 		{
 			desc: "censustable condition with multiple col and range col",
 			args: geodata.CensusQuerySQLArgs{
 				Year:        2011,
+				Version:     "2.2",
 				Geos:        []string{"E01000001"},
 				Censustable: "QS101EW",
 				Cols: []string{
@@ -362,24 +385,26 @@ AND geo_type.id = geo.type_id
  -- geotype conditions:
  -- geo conditions:
 AND (
- geo.code IN ( 'E01000001' )
+ geo.code IN ( $1 )
 )
-AND nomis_desc.short_nomis_code = 'QS101EW'
+AND nomis_desc.short_nomis_code = $7
 AND geo_metric.geo_id = geo.id
 AND data_ver.id = geo_metric.data_ver_id
-AND data_ver.census_year = 2011
-AND data_ver.ver_string = '2.2'
+AND data_ver.census_year = $8
+AND data_ver.ver_string = $9
 AND nomis_category.id = geo_metric.category_id
 AND nomis_category.year = data_ver.census_year
  -- category conditions:
 AND (
- nomis_category.long_nomis_code IN ( 'QS119EW0001', 'QS119EW0002', 'QS119EW0003' )
+ nomis_category.long_nomis_code IN ( $2, $3, $4 )
  OR
- nomis_category.long_nomis_code BETWEEN 'QS117EW0001' AND 'QS117EW0003'
+ nomis_category.long_nomis_code BETWEEN $5 AND $6
  OR
  nomis_category.nomis_desc_id = nomis_desc.id
 )
+ORDER BY geo.code
 `,
+			wantArgs: []interface{}{"E01000001", "QS119EW0001", "QS119EW0002", "QS119EW0003", "QS117EW0001", "QS117EW0003", "QS101EW", 2011, "2.2"},
 		},
 		{
 			desc:    "all rows, too many tokens",
@@ -389,8 +414,9 @@ AND (
 		{
 			desc: "all rows, all categories",
 			args: geodata.CensusQuerySQLArgs{
-				Year: 2011,
-				Geos: []string{"all"},
+				Year:    2011,
+				Version: "2.2",
+				Geos:    []string{"all"},
 			},
 			wantSQL: `
 SELECT
@@ -410,22 +436,27 @@ AND geo_type.id = geo.type_id
  -- geo conditions:
 AND geo_metric.geo_id = geo.id
 AND data_ver.id = geo_metric.data_ver_id
-AND data_ver.census_year = 2011
-AND data_ver.ver_string = '2.2'
+AND data_ver.census_year = $1
+AND data_ver.ver_string = $2
 AND nomis_category.id = geo_metric.category_id
 AND nomis_category.year = data_ver.census_year
  -- category conditions:
+ORDER BY geo.code
 `,
+			wantArgs: []interface{}{2011, "2.2"},
 		},
 	}
 	for _, test := range tests {
 		ctx := context.Background()
-		gotSQL, gotInclude, gotErr := geodata.CensusQuerySQL(ctx, test.args)
+		gotSQL, gotArgs, gotInclude, gotErr := geodata.CensusQuerySQL(ctx, test.args)
 		normedGotSql := normSQL(gotSQL)
 		normedWantSql := normSQL(test.wantSQL)
 		if !reflect.DeepEqual(normedGotSql, normedWantSql) {
 			t.Errorf("%s: returned SQL differs from expected:  %s", test.desc, diff.Diff(normedWantSql, normedGotSql))
 		}
+		if !reflect.DeepEqual(gotArgs, test.wantArgs) {
+			t.Errorf("%s: got these args - %v, wanted %v", test.desc, gotArgs, test.wantArgs)
+		}
 		if !reflect.DeepEqual(gotInclude, test.wantInclude) {
 			t.Errorf("%s: got these geography column values - '%s', wanted '%s'", test.desc, gotInclude, test.wantInclude)
 		}
@@ -0,0 +1,140 @@
+package geodata
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/ONSdigital/dp-find-insights-poc-api/pkg/timer"
+	"github.com/ONSdigital/dp-find-insights-poc-api/pkg/where"
+	"github.com/ONSdigital/dp-find-insights-poc-api/sentinel"
+	"github.com/lib/pq"
+	geom "github.com/twpayne/go-geom"
+	"github.com/twpayne/go-geom/encoding/geojson"
+	"github.com/twpayne/go-geom/encoding/wkb"
+)
+
+// PGMetricsGeoJSON retrieves metrics from postgres like PGMetricsStream, and returns them as a
+// GeoJSON FeatureCollection with one feature per geography, so a choropleth can be drawn
+// from a single request.
+//
+// Each feature's geometry is the area's boundary from geo.wkb_geometry, and its properties
+// hold the geography_code, geotype and the value of each selected category and derived column.
+// Areas with no boundary (eg England and Wales) have a null geometry.
+//
+// If tolerance is greater than zero, boundaries are simplified with ST_SimplifyPreserveTopology.
+// tolerance is in degrees, since boundaries are stored in EPSG:4326.
+//
+func (app *Geodata) PGMetricsGeoJSON(ctx context.Context, year int, version string, geocodes []string, catset *where.ValueSet, censustable string, derived Derived, tolerance float64) ([]byte, error) {
+	if tolerance < 0 {
+		return nil, fmt.Errorf("%w: simplify tolerance must not be negative", sentinel.ErrInvalidParams)
+	}
+
+	tbl, _, err := app.metricsTable(ctx, year, version, geocodes, catset, nil, censustable, derived)
+	if err != nil {
+		return nil, err
+	}
+
+	boundaries, err := app.boundaries(ctx, geocodes, tolerance)
+	if err != nil {
+		return nil, err
+	}
+
+	collection := &geojson.FeatureCollection{}
+	err = tbl.Walk(func(geocode, geotype string, values map[string]float64) error {
+		collection.Features = append(collection.Features, metricsFeature(geocode, geotype, values, boundaries[geocode]))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tgen := timer.New("generate")
+	tgen.Start()
+	body, err := json.Marshal(collection)
+	tgen.Stop()
+	tgen.Log(ctx)
+	return body, err
+}
+
+// metricsFeature builds the feature for a single row of a metrics table.
+// Unknown (NaN) values become null properties.
+func metricsFeature(geocode, geotype string, values map[string]float64, boundary geom.T) *geojson.Feature {
+	props := make(map[string]interface{}, len(values)+2)
+	for catcode, value := range values {
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			props[catcode] = nil
+		} else {
+			props[catcode] = value
+		}
+	}
+	props["geography_code"] = geocode
+	props["geotype"] = geotype
+
+	return &geojson.Feature{
+		ID:         geocode,
+		Geometry:   boundary,
+		Properties: props,
+	}
+}
+
+// boundaries fetches the boundary of each area in geocodes, simplified to tolerance.
+// Areas without a boundary are left out of the returned map.
+func (app *Geodata) boundaries(ctx context.Context, geocodes []string, tolerance float64) (map[string]geom.T, error) {
+	result := map[string]geom.T{}
+	if len(geocodes) == 0 {
+		return result, nil
+	}
+
+	sql, values := boundariesSQL(geocodes, tolerance)
+
+	t := timer.New("boundaries")
+	t.Start()
+	rows, err := app.db.DB().QueryContext(ctx, sql, values...)
+	if err != nil {
+		return nil, err
+	}
+	t.Stop()
+	t.Log(ctx)
+	defer rows.Close()
+
+	for rows.Next() {
+		var code string
+		var boundary []byte
+		if err := rows.Scan(&code, &boundary); err != nil {
+			return nil, err
+		}
+		g, err := wkb.Unmarshal(boundary)
+		if err != nil {
+			return nil, err
+		}
+		result[code] = g
+	}
+	return result, rows.Err()
+}
+
+// boundariesSQL generates the SQL to fetch boundaries for geocodes, and its bind parameters.
+func boundariesSQL(geocodes []string, tolerance float64) (string, []interface{}) {
+	qargs := where.NewArgs()
+
+	geometry := "geo.wkb_geometry"
+	if tolerance > 0 {
+		geometry = fmt.Sprintf("ST_SimplifyPreserveTopology(geo.wkb_geometry, %s)", qargs.Add(tolerance))
+	}
+
+	sql := fmt.Sprintf(`
+SELECT
+	geo.code,
+	ST_AsBinary(%s)
+FROM geo
+WHERE geo.valid
+AND geo.wkb_geometry IS NOT NULL
+AND geo.code = ANY (%s)
+`,
+		geometry,
+		qargs.Add(pq.Array(geocodes)),
+	)
+
+	return sql, qargs.Values()
+}
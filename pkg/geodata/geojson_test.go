@@ -0,0 +1,70 @@
+package geodata
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	geom "github.com/twpayne/go-geom"
+)
+
+func Test_boundariesSQL(t *testing.T) {
+	var tests = map[string]struct {
+		tolerance float64
+		wantGeom  string
+		wantArgs  []interface{}
+	}{
+		"no simplification": {
+			tolerance: 0,
+			wantGeom:  "ST_AsBinary(geo.wkb_geometry)",
+			wantArgs:  []interface{}{pq.Array([]string{"E01000001", "E01000002"})},
+		},
+		"simplified": {
+			tolerance: 0.001,
+			wantGeom:  "ST_AsBinary(ST_SimplifyPreserveTopology(geo.wkb_geometry, $1))",
+			wantArgs:  []interface{}{0.001, pq.Array([]string{"E01000001", "E01000002"})},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			sql, args := boundariesSQL([]string{"E01000001", "E01000002"}, test.tolerance)
+			assert.Contains(t, sql, test.wantGeom)
+			assert.Contains(t, sql, "geo.code = ANY ($")
+			if !reflect.DeepEqual(args, test.wantArgs) {
+				t.Errorf("args %#v, want %#v", args, test.wantArgs)
+			}
+		})
+	}
+}
+
+func Test_metricsFeature(t *testing.T) {
+	boundary := geom.NewPolygon(geom.XY).MustSetCoords([][]geom.Coord{
+		{{0, 0}, {1, 0}, {1, 1}, {0, 0}},
+	})
+
+	var tests = map[string]struct {
+		boundary geom.T
+		want     string
+	}{
+		"with boundary": {
+			boundary: boundary,
+			want:     `{"type":"Feature","id":"E01000001","geometry":{"type":"Polygon","coordinates":[[[0,0],[1,0],[1,1],[0,0]]]},"properties":{"QS101EW0001":1465,"geography_code":"E01000001","geotype":"LSOA"}}`,
+		},
+		"no boundary": {
+			boundary: nil,
+			want:     `{"type":"Feature","id":"E01000001","geometry":null,"properties":{"QS101EW0001":1465,"geography_code":"E01000001","geotype":"LSOA"}}`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			f := metricsFeature("E01000001", "LSOA", map[string]float64{"QS101EW0001": 1465}, test.boundary)
+			got, err := json.Marshal(f)
+			assert.NoError(t, err)
+			assert.JSONEq(t, test.want, string(got))
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package geodata
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ONSdigital/dp-find-insights-poc-api/pkg/timer"
+	"github.com/ONSdigital/dp-find-insights-poc-api/pkg/where"
+	"github.com/ONSdigital/dp-find-insights-poc-api/sentinel"
+	"github.com/lib/pq"
+)
+
+// GeoArea identifies a geography in hierarchy responses.
+type GeoArea struct {
+	Code    string `json:"code"`
+	Name    string `json:"name"`
+	Geotype string `json:"geotype"`
+}
+
+// Children returns the geographies directly below geocode in the hierarchy.
+// If geotype is set, all descendants of that geotype are returned instead, eg all the LSOAs in a LAD.
+// Results are ordered by geocode.
+//
+func (app *Geodata) Children(ctx context.Context, geocode, geotype string) ([]GeoArea, error) {
+	if geotype != "" {
+		var err error
+		geotype, err = FixGeotype(geotype)
+		if err != nil {
+			return nil, err
+		}
+	}
+	sql, values := childrenSQL(geocode, geotype)
+	return app.hierarchy(ctx, geocode, sql, values)
+}
+
+// Ancestors returns the geographies above geocode in the hierarchy, starting with its parent
+// and ending with England and Wales.
+func (app *Geodata) Ancestors(ctx context.Context, geocode string) ([]GeoArea, error) {
+	sql, values := ancestorsSQL(geocode)
+	return app.hierarchy(ctx, geocode, sql, values)
+}
+
+// hierarchy runs a children or ancestors query.
+// An unknown geocode is an error, but a geocode with no children or ancestors is not.
+func (app *Geodata) hierarchy(ctx context.Context, geocode, sql string, values []interface{}) ([]GeoArea, error) {
+	var exists bool
+	err := app.db.DB().QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM geo WHERE geo.valid AND geo.code = $1)", geocode).Scan(&exists)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("%w: geocode %q", sentinel.ErrNotFound, geocode)
+	}
+
+	t := timer.New("query")
+	t.Start()
+	rows, err := app.db.DB().QueryContext(ctx, sql, values...)
+	if err != nil {
+		return nil, err
+	}
+	t.Stop()
+	t.Log(ctx)
+	defer rows.Close()
+
+	areas := []GeoArea{}
+	for rows.Next() {
+		var area GeoArea
+		if err := rows.Scan(&area.Code, &area.Name, &area.Geotype); err != nil {
+			return nil, err
+		}
+		areas = append(areas, area)
+	}
+	return areas, rows.Err()
+}
+
+// childrenSQL generates the SQL to find the children of geocode, or its descendants of geotype.
+func childrenSQL(geocode, geotype string) (string, []interface{}) {
+	qargs := where.NewArgs()
+	geocodeArg := qargs.Add(geocode)
+
+	// without a geotype, only go one level down
+	condition := "AND descendants.depth = 1"
+	if geotype != "" {
+		condition = fmt.Sprintf("AND geo_type.name = %s", qargs.Add(geotype))
+	}
+
+	sql := fmt.Sprintf(`
+WITH RECURSIVE descendants AS (
+	SELECT geo_parent.geo_id, 1 AS depth
+	FROM geo_parent, geo
+	WHERE geo.code = %s
+	AND geo_parent.parent_id = geo.id
+	UNION ALL
+	SELECT geo_parent.geo_id, descendants.depth + 1
+	FROM geo_parent, descendants
+	WHERE geo_parent.parent_id = descendants.geo_id
+)
+SELECT
+	geo.code,
+	geo.name,
+	geo_type.name
+FROM
+	descendants,
+	geo,
+	geo_type
+WHERE geo.id = descendants.geo_id
+AND geo.valid
+AND geo_type.id = geo.type_id
+%s
+ORDER BY geo.code
+`,
+		geocodeArg,
+		condition,
+	)
+	return sql, qargs.Values()
+}
+
+// ancestorsSQL generates the SQL to find the ancestors of geocode, nearest first.
+func ancestorsSQL(geocode string) (string, []interface{}) {
+	qargs := where.NewArgs()
+
+	sql := fmt.Sprintf(`
+WITH RECURSIVE ancestors AS (
+	SELECT geo_parent.parent_id, 1 AS depth
+	FROM geo_parent, geo
+	WHERE geo.code = %s
+	AND geo_parent.geo_id = geo.id
+	UNION ALL
+	SELECT geo_parent.parent_id, ancestors.depth + 1
+	FROM geo_parent, ancestors
+	WHERE geo_parent.geo_id = ancestors.parent_id
+)
+SELECT
+	geo.code,
+	geo.name,
+	geo_type.name
+FROM
+	ancestors,
+	geo,
+	geo_type
+WHERE geo.id = ancestors.parent_id
+AND geo.valid
+AND geo_type.id = geo.type_id
+ORDER BY ancestors.depth
+`,
+		qargs.Add(geocode),
+	)
+	return sql, qargs.Values()
+}
+
+// withinSQL generates the condition selecting geographies anywhere below the geographies in within.
+// within can hold single values and comma-separated lists, but not ranges.
+func withinSQL(within []string, qargs *where.Args) (string, error) {
+	if len(within) == 0 {
+		return "", nil
+	}
+
+	set, err := where.ParseMultiArgs(within)
+	if err != nil {
+		return "", err
+	}
+	if len(set.Ranges) != 0 {
+		return "", fmt.Errorf("%w: within cannot be a range", sentinel.ErrInvalidParams)
+	}
+
+	sql := fmt.Sprintf(`
+AND geo.id IN (
+	WITH RECURSIVE descendants AS (
+		SELECT geo_parent.geo_id
+		FROM geo_parent, geo AS ancestor
+		WHERE ancestor.code = ANY (%s)
+		AND geo_parent.parent_id = ancestor.id
+		UNION
+		SELECT geo_parent.geo_id
+		FROM geo_parent, descendants
+		WHERE geo_parent.parent_id = descendants.geo_id
+	)
+	SELECT geo_id FROM descendants
+)
+`,
+		qargs.Add(pq.Array(set.Singles)),
+	)
+	return sql, nil
+}
@@ -0,0 +1,71 @@
+package geodata
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ONSdigital/dp-find-insights-poc-api/pkg/where"
+	"github.com/ONSdigital/dp-find-insights-poc-api/sentinel"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_childrenSQL(t *testing.T) {
+	sql, args := childrenSQL("E09000004", "")
+	assert.Contains(t, sql, "WITH RECURSIVE descendants")
+	assert.Contains(t, sql, "AND descendants.depth = 1")
+	assert.Equal(t, []interface{}{"E09000004"}, args)
+
+	sql, args = childrenSQL("E09000004", "LSOA")
+	assert.NotContains(t, sql, "depth = 1", "a geotype looks at every level")
+	assert.Contains(t, sql, "AND geo_type.name = $2")
+	assert.Equal(t, []interface{}{"E09000004", "LSOA"}, args)
+}
+
+func Test_ancestorsSQL(t *testing.T) {
+	sql, args := ancestorsSQL("E01000001")
+	assert.Contains(t, sql, "WITH RECURSIVE ancestors")
+	assert.Contains(t, sql, "ORDER BY ancestors.depth")
+	assert.Equal(t, []interface{}{"E01000001"}, args)
+}
+
+func Test_withinSQL(t *testing.T) {
+	var tests = map[string]struct {
+		within   []string
+		wantSQL  bool
+		wantArgs []interface{}
+		wantErr  error
+	}{
+		"no within": {},
+		"single": {
+			within:   []string{"E09000004"},
+			wantSQL:  true,
+			wantArgs: []interface{}{pq.Array([]string{"E09000004"})},
+		},
+		"list": {
+			within:   []string{"E09000004,E09000005", "E09000006"},
+			wantSQL:  true,
+			wantArgs: []interface{}{pq.Array([]string{"E09000004", "E09000005", "E09000006"})},
+		},
+		"range": {
+			within:  []string{"E09000004...E09000006"},
+			wantErr: sentinel.ErrInvalidParams,
+		},
+	}
+
+	for name, test := range tests {
+		qargs := where.NewArgs()
+		sql, err := withinSQL(test.within, qargs)
+		if !errors.Is(err, test.wantErr) {
+			t.Errorf("%s: error %v, want %v", name, err, test.wantErr)
+			continue
+		}
+		if test.wantSQL {
+			assert.Contains(t, sql, "AND geo.id IN (", name)
+			assert.Contains(t, sql, "ancestor.code = ANY ($1)", name)
+		} else {
+			assert.Equal(t, "", sql, name)
+		}
+		assert.Equal(t, test.wantArgs, qargs.Values(), name)
+	}
+}
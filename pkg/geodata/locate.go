@@ -0,0 +1,88 @@
+package geodata
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/ONSdigital/dp-find-insights-poc-api/model"
+	"github.com/ONSdigital/dp-find-insights-poc-api/pkg/timer"
+	"github.com/ONSdigital/dp-find-insights-poc-api/pkg/where"
+)
+
+// Locate finds the area of each geotype that contains the point in location ("lon,lat").
+// Areas are returned smallest first, eg LSOA, MSOA, LAD, Region, Country.
+// Geotypes without boundaries, and points outside every boundary (eg at sea), give no area.
+//
+func (app *Geodata) Locate(ctx context.Context, location string) ([]GeoArea, error) {
+	sql, values, err := locateSQL(location)
+	if err != nil {
+		return nil, err
+	}
+
+	t := timer.New("query")
+	t.Start()
+	rows, err := app.db.DB().QueryContext(ctx, sql, values...)
+	if err != nil {
+		return nil, err
+	}
+	t.Stop()
+	t.Log(ctx)
+	defer rows.Close()
+
+	areas := []GeoArea{}
+	for rows.Next() {
+		var area GeoArea
+		if err := rows.Scan(&area.Code, &area.Name, &area.Geotype); err != nil {
+			return nil, err
+		}
+		areas = append(areas, area)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	sortSmallestFirst(areas)
+	return areas, nil
+}
+
+// locateSQL generates the SQL to find the areas containing location, and its bind parameters.
+func locateSQL(location string) (string, []interface{}, error) {
+	coords, err := parsePoint(location)
+	if err != nil {
+		return "", nil, err
+	}
+
+	qargs := where.NewArgs()
+	sql := fmt.Sprintf(`
+SELECT
+	geo.code,
+	geo.name,
+	geo_type.name
+FROM
+	geo,
+	geo_type
+WHERE geo.valid
+AND geo_type.id = geo.type_id
+AND geo.wkb_geometry IS NOT NULL
+AND ST_Covers(
+	geo.wkb_geometry,
+	ST_SetSRID(
+		ST_Point(%s, %s),
+		4326
+	)
+)
+`,
+		qargs.Add(coords[0]),
+		qargs.Add(coords[1]),
+	)
+	return sql, qargs.Values(), nil
+}
+
+// sortSmallestFirst sorts areas by geotype, smallest geotype first.
+// Boundaries can overlap slightly, so areas of the same geotype are sorted by code.
+func sortSmallestFirst(areas []GeoArea) {
+	sort.Slice(areas, func(i, j int) bool {
+		return model.SmallestFirst(areas[i].Geotype, areas[i].Code, areas[j].Geotype, areas[j].Code)
+	})
+}
@@ -0,0 +1,69 @@
+package geodata
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ONSdigital/dp-find-insights-poc-api/sentinel"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_locateSQL(t *testing.T) {
+	var tests = map[string]struct {
+		location string
+		wantArgs []interface{}
+		wantErr  error
+	}{
+		"valid point": {
+			location: "0.1338,51.4635",
+			wantArgs: []interface{}{0.1338, 51.4635},
+		},
+		"not a number": {
+			location: "east,51.4635",
+			wantErr:  sentinel.ErrInvalidParams,
+		},
+		"not a point": {
+			location: "0.1338,51.4635,0.1017,51.4647",
+			wantErr:  sentinel.ErrInvalidParams,
+		},
+		"out of range": {
+			location: "0.1338,95",
+			wantErr:  sentinel.ErrInvalidParams,
+		},
+		"outside the UK": {
+			location: "2.3522,48.8566",
+			wantErr:  sentinel.ErrInvalidParams,
+		},
+	}
+
+	for name, test := range tests {
+		sql, args, err := locateSQL(test.location)
+		if !errors.Is(err, test.wantErr) {
+			t.Errorf("%s: error %v, want %v", name, err, test.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		assert.Contains(t, sql, "ST_Covers(", name)
+		assert.Equal(t, test.wantArgs, args, name)
+	}
+}
+
+func Test_sortSmallestFirst(t *testing.T) {
+	areas := []GeoArea{
+		{Code: "E92000001", Geotype: "Country"},
+		{Code: "E01000002", Geotype: "LSOA"},
+		{Code: "E09000001", Geotype: "LAD"},
+		{Code: "E01000001", Geotype: "LSOA"},
+		{Code: "E12000007", Geotype: "Region"},
+		{Code: "E02000001", Geotype: "MSOA"},
+	}
+	sortSmallestFirst(areas)
+
+	var got []string
+	for _, area := range areas {
+		got = append(got, area.Code)
+	}
+	assert.Equal(t, []string{"E01000001", "E01000002", "E02000001", "E09000001", "E12000007", "E92000001"}, got)
+}
@@ -4,9 +4,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
-	"strings"
 
-	"github.com/ONSdigital/dp-find-insights-poc-api/cantabular"
 	"github.com/ONSdigital/dp-find-insights-poc-api/pkg/table"
 	"github.com/ONSdigital/dp-find-insights-poc-api/pkg/timer"
 	"github.com/ONSdigital/dp-find-insights-poc-api/pkg/where"
@@ -14,31 +12,47 @@ import (
 	"github.com/lib/pq"
 )
 
-// Retrieve metrics from postgres.
-func (app *Geodata) PGMetrics(ctx context.Context, year int, geocodes []string, catset *where.ValueSet, include []string, censustable string) ([]byte, error) {
-	tbl := table.New()
-
+// generateTable generates tbl in format, with the special columns in include.
+func generateTable(ctx context.Context, tbl *table.Table, format table.Format, include []string) ([]byte, error) {
 	var body bytes.Buffer
 	body.Grow(1000000)
 
-	// If there are no geocodes, skip the db query, and just return an empty csv.
+	tgen := timer.New("generate")
+	tgen.Start()
+	err := tbl.GenerateAs(&body, format, include)
+	tgen.Stop()
+	tgen.Log(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return body.Bytes(), nil
+}
+
+// metricsTable fetches metrics for geocodes from postgres into a table, and adds derived columns.
+// If there are no geocodes, the db query is skipped and the table is empty.
+func (app *Geodata) metricsTable(ctx context.Context, year int, version string, geocodes []string, catset *where.ValueSet, include []string, censustable string, derived Derived) (*table.Table, []string, error) {
+	tbl := table.New()
+
+	catset, deriver, err := derived.prepare(catset)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	if len(geocodes) == 0 {
-		if err := tbl.Generate(&body, include); err != nil {
-			return nil, err
-		}
-		return body.Bytes(), nil
+		return tbl, include, nil
 	}
 
-	sql, include, err := app.metricsSQL(ctx, year, geocodes, catset, include, censustable)
+	sql, values, include, err := app.metricsSQL(ctx, year, version, geocodes, catset, include, censustable)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	t := timer.New("query")
 	t.Start()
-	rows, err := app.db.DB().QueryContext(ctx, sql)
+	rows, err := app.db.DB().QueryContext(ctx, sql, values...)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	t.Stop()
 	t.Log(ctx)
@@ -58,7 +72,7 @@ func (app *Geodata) PGMetrics(ctx context.Context, year int, geocodes []string,
 		nmetrics++
 		if app.maxMetrics > 0 {
 			if nmetrics > app.maxMetrics {
-				return nil, fmt.Errorf("%w: limit is %d", sentinel.ErrTooManyMetrics, app.maxMetrics)
+				return nil, nil, fmt.Errorf("%w: limit is %d", sentinel.ErrTooManyMetrics, app.maxMetrics)
 			}
 		}
 
@@ -71,7 +85,7 @@ func (app *Geodata) PGMetrics(ctx context.Context, year int, geocodes []string,
 		err := rows.Scan(&geo, &geotype, &cat, &value)
 		tscan.Stop()
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		tbl.SetCell(geo, geotype, cat, value)
@@ -80,37 +94,36 @@ func (app *Geodata) PGMetrics(ctx context.Context, year int, geocodes []string,
 	tscan.Log(ctx)
 
 	if err := rows.Err(); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	tgen := timer.New("generate")
-	tgen.Start()
-	err = tbl.Generate(&body, include)
-	tgen.Stop()
-	tgen.Log(ctx)
-	if err != nil {
-		return nil, err
+	if deriver != nil {
+		tbl.Derive(deriver)
 	}
 
-	return body.Bytes(), nil
+	return tbl, include, nil
 }
 
-// XXX mv geoCondition into a generic function in where package, or parse geocodes as a valueset
-func (app *Geodata) metricsSQL(ctx context.Context, year int, geocodes []string, catset *where.ValueSet, include []string, censustable string) (string, []string, error) {
-	// construct AND geo.code IN (...)
+// metricsSQL generates the SQL to fetch metrics for geocodes, and the bind parameters it needs.
+// The geocodes are bound as a single array parameter, so the SQL text does not depend on
+// how many geocodes there are, and a prepared statement can be reused across requests.
+func (app *Geodata) metricsSQL(ctx context.Context, year int, version string, geocodes []string, catset *where.ValueSet, include []string, censustable string) (string, []interface{}, []string, error) {
+	qargs := where.NewArgs()
+
+	// construct AND geo.code = ANY (...)
 	geoCondition := fmt.Sprintf(
-		"AND geo.code IN (%s)",
-		quoteCodes(geocodes),
+		"AND geo.code = ANY (%s)",
+		qargs.Add(pq.Array(geocodes)),
 	)
 
 	// construct WHERE condition for categories
-	catConditions, err := categorySQL(catset, censustable)
+	catConditions, err := categorySQL(catset, censustable, qargs)
 	if err != nil {
-		return "", nil, err
+		return "", nil, nil, err
 	}
 
 	// construct additional conditions for censustable / short_nomis_code
-	censustableFromSQL, censustableAndSQL := censusTableFromAndSQL(censustable)
+	censustableFromSQL, censustableAndSQL := censusTableFromAndSQL(censustable, qargs)
 
 	// construct SQL
 	template := `
@@ -134,12 +147,13 @@ AND geo_type.id = geo.type_id
 %s
 AND geo_metric.geo_id = geo.id
 AND data_ver.id = geo_metric.data_ver_id
-AND data_ver.census_year = %d
-AND data_ver.ver_string = '2.2'
+AND data_ver.census_year = %s
+AND data_ver.ver_string = %s
 AND nomis_category.id = geo_metric.category_id
 AND nomis_category.year = data_ver.census_year
 	-- category conditions;
 %s
+ORDER BY geo.code
 `
 
 	sql := fmt.Sprintf(
@@ -147,41 +161,10 @@ AND nomis_category.year = data_ver.census_year
 		censustableFromSQL,
 		geoCondition,
 		censustableAndSQL,
-		year,
+		qargs.Add(year),
+		qargs.Add(version),
 		catConditions,
 	)
 
-	return sql, include, nil
-}
-
-func quoteCodes(geocodes []string) string {
-	var quoted []string
-	for _, code := range geocodes {
-		quoted = append(quoted, pq.QuoteLiteral(code))
-	}
-	return strings.Join(quoted, ",")
-}
-
-// Retrieve metrics from Cantabular.
-func (app *Geodata) CantabularMetrics(ctx context.Context, geocodes []string, catset *where.ValueSet, geotype string) ([]byte, error) {
-	if app.cant == nil {
-		return nil, fmt.Errorf("%w: cantabular not enabled", sentinel.ErrNotSupported)
-	}
-
-	// the current cantabular queries accept a single category code
-	if len(catset.Singles) != 1 {
-		return nil, fmt.Errorf("%w: cantabular queries only accept a single category code", sentinel.ErrInvalidParams)
-	}
-	if len(catset.Ranges) != 0 {
-		return nil, fmt.Errorf("%w: cantabular queries do not accept category code ranges", sentinel.ErrInvalidParams)
-	}
-	if geotype == "" {
-		return nil, fmt.Errorf("%w: cantabular queries require geotype", sentinel.ErrMissingParams)
-	}
-
-	geoq, catsQL, values, err := app.cant.QueryMetricFilter(ctx, "", strings.Join(geocodes, ","), geotype, catset.Singles[0])
-	if err != nil {
-		return nil, err
-	}
-	return []byte(cantabular.ParseMetric(geoq, catsQL, values)), nil
+	return sql, qargs.Values(), include, nil
 }
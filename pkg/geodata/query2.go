@@ -3,15 +3,31 @@ package geodata
 import (
 	"context"
 	"fmt"
-	"strings"
 
 	"github.com/ONSdigital/dp-find-insights-poc-api/pkg/timer"
+	"github.com/ONSdigital/dp-find-insights-poc-api/pkg/where"
+	"github.com/ONSdigital/dp-find-insights-poc-api/sentinel"
 )
 
 // Proposed replacement for Query.
 // This version separates selecting geocodes from selecting metrics.
-func (app *Geodata) Query2(ctx context.Context, year int, bbox, location string, radius int, polygon string, geotypes, geos []string) ([]string, error) {
-	err := validateCensusQuery(
+//
+// within restricts the geocodes to those below the listed geographies in the hierarchy,
+// eg within=E09000004 with geotypes=LSOA for all the LSOAs in Bexley.
+// within on its own is enough to select geocodes; it doesn't need rows, bbox, etc.
+//
+// Geocodes are returned in order, and can be fetched a page at a time.
+// limit is the most geocodes to return; zero means no limit.
+// cursor is empty for the first page, or the next cursor returned with the previous page.
+// next is empty when there are no more pages.
+//
+func (app *Geodata) Query2(ctx context.Context, year int, bbox, location string, radius int, polygon string, geotypes, geos, within []string, cursor string, limit int) (geocodes []string, next string, err error) {
+	// within with no other conditions means everything within
+	if len(within) != 0 && len(geos) == 0 && bbox == "" && location == "" && radius == 0 && polygon == "" {
+		geos = []string{allRowsToken}
+	}
+
+	err = validateCensusQuery(
 		CensusQuerySQLArgs{
 			Year:     year,
 			Geos:     geos,
@@ -23,19 +39,33 @@ func (app *Geodata) Query2(ctx context.Context, year int, bbox, location string,
 		},
 	)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
-	sql, err := geocodesSQL(year, bbox, location, radius, polygon, geotypes, geos)
+	if limit < 0 {
+		return nil, "", fmt.Errorf("%w: limit must not be negative", sentinel.ErrInvalidParams)
+	}
+	after, err := DecodeCursor(cursor)
 	if err != nil {
-		return nil, err
+		return nil, "", err
+	}
+
+	// ask for one more than limit so we know if there is another page
+	var fetch int
+	if limit > 0 {
+		fetch = limit + 1
+	}
+
+	sql, values, err := geocodesSQL(year, bbox, location, radius, polygon, geotypes, geos, within, after, fetch)
+	if err != nil {
+		return nil, "", err
 	}
 
 	t := timer.New("query")
 	t.Start()
-	rows, err := app.db.DB().QueryContext(ctx, sql)
+	rows, err := app.db.DB().QueryContext(ctx, sql, values...)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 	t.Stop()
 	t.Log(ctx)
@@ -58,7 +88,7 @@ func (app *Geodata) Query2(ctx context.Context, year int, bbox, location string,
 		err := rows.Scan(&geo)
 		tscan.Stop()
 		if err != nil {
-			return nil, err
+			return nil, "", err
 		}
 
 		result = append(result, geo)
@@ -67,55 +97,37 @@ func (app *Geodata) Query2(ctx context.Context, year int, bbox, location string,
 	tscan.Log(ctx)
 
 	if err := rows.Err(); err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
-	return result, nil
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+		next = EncodeCursor(result[limit-1])
+	}
+
+	return result, next, nil
 }
 
-func geocodesSQL(year int, bbox, location string, radius int, polygon string, geotypes, geos []string) (string, error) {
-	var geoConditions string
-	if !wantAllRows(geos) {
-		// fetch conditions SQL
-		geoCondition, geoErr := geoSQL(geos)
-		bboxCondition, bboxErr := bboxSQL(bbox)
-		radiusCondition, radiusErr := radiusSQL(location, radius)
-		polygonCondition, polygonErr := polygonSQL(polygon)
-
-		// check errs, return on first found
-		for _, err := range []error{
-			geoErr,
-			bboxErr,
-			radiusErr,
-			polygonErr,
-		} {
-			if err != nil {
-				return "", err
-			}
-		}
+// geocodesSQL generates the SQL to select geocodes, and the bind parameters it needs.
+// Only geocodes after the geocode in after are selected, and at most limit of them if limit is not zero.
+func geocodesSQL(year int, bbox, location string, radius int, polygon string, geotypes, geos, within []string, after string, limit int) (string, []interface{}, error) {
+	qargs := where.NewArgs()
 
-		// collate join conditions with sql OR
-		var conditions []string
-		for _, condition := range []string{
-			geoCondition,
-			bboxCondition,
-			radiusCondition,
-			polygonCondition,
-		} {
-			if condition != "" {
-				conditions = append(conditions, condition)
-			}
-		}
-		geoConditions = fmt.Sprintf(
-			"AND (\n    %s)\n",
-			strings.Join(conditions, "    OR\n"),
-		)
+	geoConditions, err := geoConditionsSQL(geos, bbox, location, radius, polygon, qargs)
+	if err != nil {
+		return "", nil, err
+	}
+
+	// construct condition for geographies within others
+	withinConditions, err := withinSQL(within, qargs)
+	if err != nil {
+		return "", nil, err
 	}
 
 	// construct WHERE condition for geotypes
-	geotypeConditions, err := geotypeSQL("geo_type.name", geotypes)
+	geotypeConditions, err := geotypeSQL("geo_type.name", geotypes, qargs)
 	if err != nil {
-		return "", err
+		return "", nil, err
 	}
 
 	// construct SQL
@@ -130,13 +142,31 @@ AND geo_type.id = geo.type_id
 	-- geotype conditions:
 %s
 	-- geo conditions:
+%s
+	-- within conditions:
+%s
+	-- paging conditions:
+%s
+ORDER BY geo.code
 %s
 `
 
+	var afterCondition string
+	if after != "" {
+		afterCondition = fmt.Sprintf("AND geo.code > %s", qargs.Add(after))
+	}
+	var limitClause string
+	if limit > 0 {
+		limitClause = fmt.Sprintf("LIMIT %s", qargs.Add(limit))
+	}
+
 	sql := fmt.Sprintf(
 		template,
 		geotypeConditions,
 		geoConditions,
+		withinConditions,
+		afterCondition,
+		limitClause,
 	)
-	return sql, nil
+	return sql, qargs.Values(), nil
 }
@@ -0,0 +1,77 @@
+package geodata
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ONSdigital/dp-find-insights-poc-api/sentinel"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_geocodesSQL_Paging(t *testing.T) {
+	var tests = map[string]struct {
+		after     string
+		limit     int
+		wantAfter string
+		wantLimit string
+		wantArgs  []interface{}
+	}{
+		"first page, no limit": {
+			wantArgs: []interface{}{"E01000001"},
+		},
+		"first page with limit": {
+			limit:     11,
+			wantLimit: "LIMIT $2",
+			wantArgs:  []interface{}{"E01000001", 11},
+		},
+		"later page with limit": {
+			after:     "E01000005",
+			limit:     11,
+			wantAfter: "AND geo.code > $2",
+			wantLimit: "LIMIT $3",
+			wantArgs:  []interface{}{"E01000001", "E01000005", 11},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			sql, args, err := geocodesSQL(2011, "", "", 0, "", nil, []string{"E01000001"}, nil, test.after, test.limit)
+			assert.NoError(t, err)
+			assert.Contains(t, sql, "ORDER BY geo.code")
+			if test.wantAfter != "" {
+				assert.Contains(t, sql, test.wantAfter)
+			} else {
+				assert.NotContains(t, sql, "geo.code >")
+			}
+			if test.wantLimit != "" {
+				assert.Contains(t, sql, test.wantLimit)
+			} else {
+				assert.NotContains(t, sql, "LIMIT")
+			}
+			if !reflect.DeepEqual(args, test.wantArgs) {
+				t.Errorf("args %#v, want %#v", args, test.wantArgs)
+			}
+		})
+	}
+}
+
+func Test_Cursor(t *testing.T) {
+	cursor := EncodeCursor("E01000005")
+	assert.NotContains(t, cursor, "E01000005", "cursor should be opaque")
+	assert.False(t, strings.ContainsAny(cursor, "+/="), "cursor should be url safe")
+
+	geocode, err := DecodeCursor(cursor)
+	assert.NoError(t, err)
+	assert.Equal(t, "E01000005", geocode)
+
+	geocode, err = DecodeCursor("")
+	assert.NoError(t, err)
+	assert.Equal(t, "", geocode, "empty cursor is the first page")
+
+	for _, bad := range []string{"not a cursor!", "@@@"} {
+		_, err = DecodeCursor(bad)
+		assert.True(t, errors.Is(err, sentinel.ErrInvalidParams), "%q should be invalid", bad)
+	}
+}
@@ -0,0 +1,171 @@
+package geodata
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/ONSdigital/dp-find-insights-poc-api/model"
+	"github.com/ONSdigital/dp-find-insights-poc-api/pkg/timer"
+	"github.com/ONSdigital/dp-find-insights-poc-api/pkg/where"
+	"github.com/ONSdigital/dp-find-insights-poc-api/sentinel"
+	"github.com/lib/pq"
+)
+
+const (
+	defaultSearchLimit = 10
+	maxSearchLimit     = 100
+)
+
+// defaultSearchGeotypes are searched when no geotypes are given.
+// LSOA names are just the LAD name and a number, so aren't worth searching.
+var defaultSearchGeotypes = []string{"Region", "LAD", "MSOA"}
+
+// SearchResult is a geography matching a name search.
+type SearchResult struct {
+	Code      string    `json:"code"`
+	Name      string    `json:"name"`
+	WelshName string    `json:"welsh_name,omitempty"`
+	Geotype   string    `json:"geotype"`
+	BBox      []float64 `json:"bbox,omitempty"` // min lon, min lat, max lon, max lat
+}
+
+// Search finds geographies whose English or Welsh name matches q, for autocompletion.
+//
+// Names that start with q, or have a word starting with q, rank above fuzzy (trigram) matches,
+// so "bex" finds Bexley, and "bexly" still finds it.
+// geotypes restricts the geotypes searched; the default is Region, LAD and MSOA.
+// limit is the most results to return; zero means the default of 10.
+// Name is in lang, falling back to English where there is no Welsh name.
+//
+func (app *Geodata) Search(ctx context.Context, q string, geotypes []string, bbox string, limit int, lang string) ([]SearchResult, error) {
+	sql, values, err := searchSQL(q, geotypes, bbox, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	t := timer.New("query")
+	t.Start()
+	rows, err := app.db.DB().QueryContext(ctx, sql, values...)
+	if err != nil {
+		return nil, err
+	}
+	t.Stop()
+	t.Log(ctx)
+	defer rows.Close()
+
+	results := []SearchResult{}
+	for rows.Next() {
+		result, err := scanSearchResult(rows)
+		if err != nil {
+			return nil, err
+		}
+		result.Name = model.Localise(lang, result.Name, result.WelshName)
+		results = append(results, result)
+	}
+	return results, rows.Err()
+}
+
+// scanSearchResult scans a row selected by searchSQL.
+func scanSearchResult(rows *sql.Rows) (SearchResult, error) {
+	var result SearchResult
+	var welsh sql.NullString
+	var xmin, ymin, xmax, ymax sql.NullFloat64
+	if err := rows.Scan(&result.Code, &result.Name, &welsh, &result.Geotype, &xmin, &ymin, &xmax, &ymax); err != nil {
+		return result, err
+	}
+	result.WelshName = welsh.String
+	if xmin.Valid {
+		result.BBox = []float64{xmin.Float64, ymin.Float64, xmax.Float64, ymax.Float64}
+	}
+	return result, nil
+}
+
+// searchSQL generates the SQL for a name search, and its bind parameters.
+func searchSQL(q string, geotypes []string, bbox string, limit int) (string, []interface{}, error) {
+	q = strings.TrimSpace(q)
+	if q == "" {
+		return "", nil, fmt.Errorf("%w: q", sentinel.ErrMissingParams)
+	}
+	if limit < 0 || limit > maxSearchLimit {
+		return "", nil, fmt.Errorf("%w: limit must be 1..%d", sentinel.ErrInvalidParams, maxSearchLimit)
+	}
+	if limit == 0 {
+		limit = defaultSearchLimit
+	}
+
+	if len(geotypes) == 0 {
+		geotypes = defaultSearchGeotypes
+	}
+	set, err := where.ParseMultiArgs(geotypes)
+	if err != nil {
+		return "", nil, err
+	}
+	set, err = MapGeotypes(set)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(set.Ranges) != 0 {
+		return "", nil, fmt.Errorf("%w: geotype cannot be a range", sentinel.ErrInvalidParams)
+	}
+
+	qargs := where.NewArgs()
+
+	qarg := qargs.Add(q)
+	prefix := qargs.Add(likeEscape(q) + "%")
+	wordPrefix := qargs.Add("% " + likeEscape(q) + "%")
+
+	var bboxCondition string
+	if bbox != "" {
+		condition, err := bboxSQL(bbox, qargs)
+		if err != nil {
+			return "", nil, err
+		}
+		bboxCondition = "AND " + condition
+	}
+
+	sql := fmt.Sprintf(`
+SELECT
+	geo.code,
+	geo.name,
+	geo.welsh_name,
+	geo_type.name,
+	ST_XMin(geo.wkb_geometry),
+	ST_YMin(geo.wkb_geometry),
+	ST_XMax(geo.wkb_geometry),
+	ST_YMax(geo.wkb_geometry)
+FROM
+	geo,
+	geo_type
+WHERE geo.valid
+AND geo_type.id = geo.type_id
+AND geo_type.name = ANY (%[4]s)
+AND (
+	geo.name ILIKE %[2]s OR geo.name ILIKE %[3]s OR geo.name %% %[1]s OR
+	geo.welsh_name ILIKE %[2]s OR geo.welsh_name ILIKE %[3]s OR geo.welsh_name %% %[1]s
+)
+%[5]s
+ORDER BY
+	-- prefix matches first
+	(geo.name ILIKE %[2]s OR COALESCE(geo.welsh_name, '') ILIKE %[2]s) DESC,
+	(geo.name ILIKE %[3]s OR COALESCE(geo.welsh_name, '') ILIKE %[3]s) DESC,
+	-- then the closest fuzzy matches
+	GREATEST(similarity(geo.name, %[1]s), similarity(COALESCE(geo.welsh_name, ''), %[1]s)) DESC,
+	geo.name
+LIMIT %[6]s
+`,
+		qarg,
+		prefix,
+		wordPrefix,
+		qargs.Add(pq.Array(set.Singles)),
+		bboxCondition,
+		qargs.Add(limit),
+	)
+	return sql, qargs.Values(), nil
+}
+
+// likeEscape escapes the LIKE wildcards in s.
+func likeEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
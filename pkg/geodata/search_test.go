@@ -0,0 +1,68 @@
+package geodata
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ONSdigital/dp-find-insights-poc-api/sentinel"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_searchSQL(t *testing.T) {
+	var tests = map[string]struct {
+		q        string
+		geotypes []string
+		bbox     string
+		limit    int
+		wantArgs []interface{}
+		wantErr  error
+	}{
+		"defaults": {
+			q:        " Bex ",
+			wantArgs: []interface{}{"Bex", "Bex%", "% Bex%", pq.Array([]string{"Region", "LAD", "MSOA"}), 10},
+		},
+		"geotypes and limit": {
+			q:        "bex",
+			geotypes: []string{"lad,msoa"},
+			limit:    5,
+			wantArgs: []interface{}{"bex", "bex%", "% bex%", pq.Array([]string{"LAD", "MSOA"}), 5},
+		},
+		"wildcards escaped": {
+			q:        "50%_off",
+			wantArgs: []interface{}{"50%_off", `50\%\_off%`, `% 50\%\_off%`, pq.Array([]string{"Region", "LAD", "MSOA"}), 10},
+		},
+		"bbox": {
+			q:        "bex",
+			bbox:     "0.1338,51.4635,0.1017,51.4647",
+			wantArgs: []interface{}{"bex", "bex%", "% bex%", "MULTIPOINT(0.133800 51.463500, 0.101700 51.464700)", pq.Array([]string{"Region", "LAD", "MSOA"}), 10},
+		},
+		"no q": {
+			q:       "  ",
+			wantErr: sentinel.ErrMissingParams,
+		},
+		"bad geotype": {
+			q:        "bex",
+			geotypes: []string{"county"},
+			wantErr:  sentinel.ErrInvalidParams,
+		},
+		"limit too big": {
+			q:       "bex",
+			limit:   1000,
+			wantErr: sentinel.ErrInvalidParams,
+		},
+	}
+
+	for name, test := range tests {
+		sql, args, err := searchSQL(test.q, test.geotypes, test.bbox, test.limit)
+		if !errors.Is(err, test.wantErr) {
+			t.Errorf("%s: error %v, want %v", name, err, test.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		assert.Contains(t, sql, "geo.name % $1", name)
+		assert.Equal(t, test.wantArgs, args, name)
+	}
+}
@@ -0,0 +1,180 @@
+package geodata
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ONSdigital/dp-find-insights-poc-api/pkg/timer"
+	"github.com/ONSdigital/dp-find-insights-poc-api/pkg/where"
+	"github.com/ONSdigital/dp-find-insights-poc-api/sentinel"
+	"github.com/lib/pq"
+)
+
+// StoreBreaks computes ckmeans breaks over all geographies for each geotype in each category, for each k in ks,
+// and saves them in the breaks table, replacing any saved before.
+// The metrics are only queried once, however many ks there are.
+// If divideBy is not blank, the breaks are for ratios of each category to divideBy, as for Breaks.
+//
+// Breaks that can't be computed, eg because k is more than the number of geographies, are skipped
+// and the rest are still saved. The skipped breaks are listed in the returned error.
+//
+func (app *Geodata) StoreBreaks(ctx context.Context, year int, version string, cat []string, geotype []string, ks []int, divideBy string) error {
+	parser := NewBreaksParser(ClassifierFunc(getBreaks), divideBy, 0)
+	if err := parser.parseArgs(cat, geotype); err != nil {
+		return err
+	}
+	if err := app.collectMetrics(ctx, year, version, Selection{}, parser); err != nil {
+		return err
+	}
+
+	var skipped []string
+	for _, k := range ks {
+		for _, catcode := range parser.catcodes {
+			for _, geotype := range parser.geotypes {
+				metrics := parser.metrics[catcode][geotype]
+				if len(metrics) == 0 {
+					continue
+				}
+				breaks, err := parser.classifier.Breaks(metrics, k)
+				if err != nil {
+					skipped = append(skipped, fmt.Sprintf("%s %s k=%d: %s", catcode, geotype, k, err))
+					continue
+				}
+				sql, values := storeBreaksSQL(year, version, catcode, divideBy, geotype, k, breaks, getMinMax(metrics))
+				res, err := app.db.DB().ExecContext(ctx, sql, values...)
+				if err != nil {
+					return err
+				}
+				n, err := res.RowsAffected()
+				if err != nil {
+					return err
+				}
+				if n == 0 {
+					return fmt.Errorf("%w: cannot store breaks for %s divided by %q: category not found for %d version %s", sentinel.ErrInvalidParams, catcode, divideBy, year, version)
+				}
+			}
+		}
+	}
+	if len(skipped) != 0 {
+		return fmt.Errorf("skipped %d breaks: %s", len(skipped), strings.Join(skipped, "; "))
+	}
+	return nil
+}
+
+// storedBreaks returns the breaks saved by StoreBreaks for the categories and geotypes in parser.
+// ok is false unless there are saved breaks for every category and geotype.
+//
+func (app *Geodata) storedBreaks(ctx context.Context, year int, version string, parser *BreaksParser) (breaks map[string]map[string][]float64, ok bool, err error) {
+	sql, values := storedBreaksSQL(year, version, parser.catcodes, parser.geotypes, parser.k, parser.divideBy)
+
+	t := timer.New("query")
+	t.Start()
+	rows, err := app.db.DB().QueryContext(ctx, sql, values...)
+	if err != nil {
+		return nil, false, err
+	}
+	t.Stop()
+	t.Log(ctx)
+	defer rows.Close()
+
+	breaks = map[string]map[string][]float64{}
+	var n int
+	for rows.Next() {
+		var catcode, geotype string
+		var catBreaks, catMinMax pq.Float64Array
+		if err := rows.Scan(&catcode, &geotype, &catBreaks, &catMinMax); err != nil {
+			return nil, false, err
+		}
+		if _, prs := breaks[catcode]; !prs {
+			breaks[catcode] = map[string][]float64{}
+		}
+		breaks[catcode][geotype] = catBreaks
+		breaks[catcode][geotype+"_min_max"] = catMinMax
+		n++
+	}
+	if err := rows.Err(); err != nil {
+		return nil, false, err
+	}
+	return breaks, n == len(parser.catcodes)*len(parser.geotypes), nil
+}
+
+// storedBreaksSQL generates the SQL to fetch saved breaks, and its bind parameters.
+func storedBreaksSQL(year int, version string, catcodes, geotypes []string, k int, divideBy string) (string, []interface{}) {
+	qargs := where.NewArgs()
+
+	sql := fmt.Sprintf(`
+SELECT
+	nomis_category.long_nomis_code,
+	geo_type.name,
+	breaks.breaks,
+	breaks.min_max
+FROM
+	breaks
+	LEFT JOIN nomis_category AS divide_by ON divide_by.id = breaks.divide_by_id,
+	data_ver,
+	nomis_category,
+	geo_type
+WHERE data_ver.id = breaks.data_ver_id
+AND data_ver.census_year = %s
+AND data_ver.ver_string = %s
+AND nomis_category.id = breaks.category_id
+AND nomis_category.long_nomis_code = ANY (%s)
+AND geo_type.id = breaks.type_id
+AND geo_type.name = ANY (%s)
+AND breaks.k = %s
+AND COALESCE(divide_by.long_nomis_code, '') = %s
+`,
+		qargs.Add(year),
+		qargs.Add(version),
+		qargs.Add(pq.Array(catcodes)),
+		qargs.Add(pq.Array(geotypes)),
+		qargs.Add(k),
+		qargs.Add(divideBy),
+	)
+	return sql, qargs.Values()
+}
+
+// storeBreaksSQL generates the SQL to save the breaks for one category and geotype, and its bind parameters.
+// divide_by_id is 0 when divideBy is blank. Nothing is saved if a non-blank divideBy is not a category
+// of year, so ratio breaks can't overwrite the raw value breaks.
+//
+func storeBreaksSQL(year int, version, catcode, divideBy, geotype string, k int, breaks, minMax []float64) (string, []interface{}) {
+	qargs := where.NewArgs()
+
+	sql := fmt.Sprintf(`
+INSERT INTO breaks (data_ver_id, category_id, divide_by_id, type_id, k, breaks, min_max)
+SELECT
+	data_ver.id,
+	nomis_category.id,
+	COALESCE(divide_by.id, 0),
+	geo_type.id,
+	%s,
+	%s,
+	%s
+FROM
+	data_ver
+	LEFT JOIN nomis_category AS divide_by ON divide_by.long_nomis_code = %s AND divide_by.year = data_ver.census_year,
+	nomis_category,
+	geo_type
+WHERE data_ver.census_year = %s
+AND data_ver.ver_string = %s
+AND nomis_category.long_nomis_code = %s
+AND nomis_category.year = data_ver.census_year
+AND geo_type.name = %s
+AND (divide_by.id IS NOT NULL OR %s = '')
+ON CONFLICT (data_ver_id, category_id, divide_by_id, type_id, k) DO UPDATE
+SET breaks = EXCLUDED.breaks, min_max = EXCLUDED.min_max
+`,
+		qargs.Add(k),
+		qargs.Add(pq.Array(breaks)),
+		qargs.Add(pq.Array(minMax)),
+		qargs.Add(divideBy),
+		qargs.Add(year),
+		qargs.Add(version),
+		qargs.Add(catcode),
+		qargs.Add(geotype),
+		qargs.Add(divideBy),
+	)
+	return sql, qargs.Values()
+}
@@ -0,0 +1,50 @@
+package geodata
+
+import (
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_storedBreaksSQL(t *testing.T) {
+	sql, args := storedBreaksSQL(2011, "2.2", []string{"QS101EW0002", "QS101EW0003"}, []string{"LAD"}, 5, "QS101EW0001")
+
+	assert.Contains(t, sql, "FROM\n\tbreaks")
+	assert.Contains(t, sql, "COALESCE(divide_by.long_nomis_code, '') = $6")
+	assert.Equal(t, []interface{}{
+		2011,
+		"2.2",
+		pq.Array([]string{"QS101EW0002", "QS101EW0003"}),
+		pq.Array([]string{"LAD"}),
+		5,
+		"QS101EW0001",
+	}, args)
+}
+
+func Test_storeBreaksSQL(t *testing.T) {
+	sql, args := storeBreaksSQL(2011, "2.2", "QS101EW0002", "QS101EW0001", "LSOA", 3, []float64{1, 2, 3}, []float64{0.5, 3})
+
+	assert.Contains(t, sql, "INSERT INTO breaks")
+	assert.Contains(t, sql, "ON CONFLICT (data_ver_id, category_id, divide_by_id, type_id, k) DO UPDATE")
+	// divide_by is the category of the same year, and must exist unless divideBy is blank
+	assert.Contains(t, sql, "divide_by.long_nomis_code = $4 AND divide_by.year = data_ver.census_year")
+	assert.Contains(t, sql, "AND (divide_by.id IS NOT NULL OR $9 = '')")
+	assert.Equal(t, []interface{}{
+		3,
+		pq.Array([]float64{1, 2, 3}),
+		pq.Array([]float64{0.5, 3}),
+		"QS101EW0001",
+		2011,
+		"2.2",
+		"QS101EW0002",
+		"LSOA",
+		"QS101EW0001",
+	}, args)
+}
+
+func TestSelection_all(t *testing.T) {
+	assert.True(t, Selection{}.all())
+	assert.False(t, Selection{Within: []string{"E12000007"}}.all())
+	assert.False(t, Selection{BBox: "0.1338,51.4635,0.1017,51.4647"}.all())
+}
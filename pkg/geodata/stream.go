@@ -0,0 +1,188 @@
+package geodata
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/ONSdigital/dp-find-insights-poc-api/pkg/table"
+	"github.com/ONSdigital/dp-find-insights-poc-api/pkg/timer"
+	"github.com/ONSdigital/dp-find-insights-poc-api/pkg/where"
+)
+
+// QueryStream runs the merged census query, which is the logical OR of the other specific queries,
+// and writes the result to w in format (csv, json or ndjson).
+// Any combination of rows, bbox, radius and polygon queries can be given, and geotype can be exposed in the csv output.
+//
+// Rows are written to w as they are read from the db, so memory use doesn't grow
+// with the size of the result, and maxMetrics doesn't apply.
+//
+// Errors that happen before the first row is written to w can be reported to the client as usual.
+// Errors after that can only be logged, and the output will be incomplete.
+//
+func (app *Geodata) QueryStream(ctx context.Context, w io.Writer, year int, version string, bbox, location string, radius int, polygon string, geotypes, rows, cols []string, censustable string, format table.Format) error {
+	sql, values, include, err := censusQuerySQL(ctx, year, version, rows, bbox, location, radius, polygon, geotypes, cols, censustable)
+	if err != nil {
+		return err
+	}
+
+	// censusQuerySQL has already checked cols parse
+	catset, err := where.ParseMultiArgs(cols)
+	if err != nil {
+		return err
+	}
+	_, catset, err = ExtractSpecialCols(catset)
+	if err != nil {
+		return err
+	}
+	catcodes, err := app.categoryCodes(ctx, year, catset, censustable)
+	if err != nil {
+		return err
+	}
+
+	return app.streamCells(ctx, w, sql, values, include, catcodes, nil, format)
+}
+
+// PGMetricsStream retrieves metrics for geocodes from postgres, with any derived columns,
+// and writes them to w in format.
+// See QueryStream.
+func (app *Geodata) PGMetricsStream(ctx context.Context, w io.Writer, year int, version string, geocodes []string, catset *where.ValueSet, include []string, censustable string, derived Derived, format table.Format) error {
+	catset, deriver, err := derived.prepare(catset)
+	if err != nil {
+		return err
+	}
+
+	// If there are no geocodes, skip the db query, and just write an empty table.
+	if len(geocodes) == 0 {
+		s, err := table.NewStream(w, format, include)
+		if err != nil {
+			return err
+		}
+		return s.Close()
+	}
+
+	sql, values, include, err := app.metricsSQL(ctx, year, version, geocodes, catset, include, censustable)
+	if err != nil {
+		return err
+	}
+
+	catcodes, err := app.categoryCodes(ctx, year, catset, censustable)
+	if err != nil {
+		return err
+	}
+
+	return app.streamCells(ctx, w, sql, values, include, catcodes, deriver, format)
+}
+
+// categoryCodes returns the codes of the categories selected by catset and censustable, in the same
+// way as the census queries select them, so the columns of a stream are known before its first row.
+//
+func (app *Geodata) categoryCodes(ctx context.Context, year int, catset *where.ValueSet, censustable string) ([]string, error) {
+	sql, values, err := categoryCodesSQL(year, catset, censustable)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := app.db.DB().QueryContext(ctx, sql, values...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var catcodes []string
+	for rows.Next() {
+		var catcode string
+		if err := rows.Scan(&catcode); err != nil {
+			return nil, err
+		}
+		catcodes = append(catcodes, catcode)
+	}
+	return catcodes, rows.Err()
+}
+
+// categoryCodesSQL generates the SQL for categoryCodes, and the bind parameters it needs.
+func categoryCodesSQL(year int, catset *where.ValueSet, censustable string) (string, []interface{}, error) {
+	qargs := where.NewArgs()
+
+	// construct WHERE condition for categories
+	catConditions, err := categorySQL(catset, censustable, qargs)
+	if err != nil {
+		return "", nil, err
+	}
+
+	// construct additional conditions for censustable / short_nomis_code
+	censustableFromSQL, censustableAndSQL := censusTableFromAndSQL(censustable, qargs)
+
+	template := `
+SELECT DISTINCT
+	nomis_category.long_nomis_code
+FROM
+	nomis_category
+	%s
+WHERE nomis_category.year = %s
+	-- censustable conditions
+%s
+	-- category conditions
+%s
+ORDER BY nomis_category.long_nomis_code
+`
+	sql := fmt.Sprintf(
+		template,
+		censustableFromSQL,
+		qargs.Add(year),
+		censustableAndSQL,
+		catConditions,
+	)
+	return sql, qargs.Values(), nil
+}
+
+// streamCells runs the query in sql with bind parameters values and writes the results to w in format.
+// sql must be a query against the geo_metric table selecting exactly
+// code, geotype, category and metric, ordered by code, for the categories in catcodes.
+// If deriver is not nil, the columns it computes are added to each row.
+//
+func (app *Geodata) streamCells(ctx context.Context, w io.Writer, sql string, values []interface{}, include, catcodes []string, deriver table.Deriver, format table.Format) error {
+	s, err := table.NewStream(w, format, include)
+	if err != nil {
+		return err
+	}
+	if deriver != nil {
+		s.Derive(deriver)
+	}
+	s.SetColumns(catcodes)
+
+	t := timer.New("query")
+	t.Start()
+	rows, err := app.db.DB().QueryContext(ctx, sql, values...)
+	if err != nil {
+		return err
+	}
+	t.Stop()
+	t.Log(ctx)
+	defer rows.Close()
+
+	tstream := timer.New("stream")
+	tstream.Start()
+	for rows.Next() {
+		var geo string
+		var geotype string
+		var cat string
+		var value float64
+
+		if err := rows.Scan(&geo, &geotype, &cat, &value); err != nil {
+			return err
+		}
+
+		if err := s.SetCell(geo, geotype, cat, value); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	err = s.Close()
+	tstream.Stop()
+	tstream.Log(ctx)
+
+	return err
+}
@@ -0,0 +1,52 @@
+package geodata
+
+import (
+	"testing"
+
+	"github.com/ONSdigital/dp-find-insights-poc-api/pkg/where"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_categoryCodesSQL(t *testing.T) {
+	var tests = map[string]struct {
+		catset       *where.ValueSet
+		censustable  string
+		wantContains []string
+		wantMissing  []string
+		wantArgs     []interface{}
+	}{
+		"singles": {
+			catset:       &where.ValueSet{Singles: []string{"QS101EW0001", "KS207WA0001"}},
+			wantContains: []string{"nomis_category.long_nomis_code IN"},
+			wantMissing:  []string{"nomis_desc"},
+			wantArgs:     []interface{}{2011, "QS101EW0001", "KS207WA0001"},
+		},
+		"range": {
+			catset:       &where.ValueSet{Ranges: []*where.ValueRange{{Low: "QS101EW0001", High: "QS101EW0003"}}},
+			wantContains: []string{"nomis_category.long_nomis_code BETWEEN"},
+			wantArgs:     []interface{}{2011, "QS101EW0001", "QS101EW0003"},
+		},
+		"censustable": {
+			catset:       where.NewValueSet(),
+			censustable:  "QS101EW",
+			wantContains: []string{"nomis_desc.short_nomis_code = ", "nomis_category.nomis_desc_id = nomis_desc.id"},
+			wantArgs:     []interface{}{2011, "QS101EW"},
+		},
+	}
+
+	for name, test := range tests {
+		sql, args, err := categoryCodesSQL(2011, test.catset, test.censustable)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		assert.Contains(t, sql, "WHERE nomis_category.year = $", name)
+		for _, s := range test.wantContains {
+			assert.Contains(t, sql, s, name)
+		}
+		for _, s := range test.wantMissing {
+			assert.NotContains(t, sql, s, name)
+		}
+		assert.ElementsMatch(t, test.wantArgs, args, name)
+	}
+}
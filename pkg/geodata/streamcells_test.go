@@ -0,0 +1,117 @@
+//go:build comptest
+// +build comptest
+
+package geodata
+
+import (
+	"context"
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/ONSdigital/dp-find-insights-poc-api/comptests"
+	"github.com/ONSdigital/dp-find-insights-poc-api/pkg/database"
+	"github.com/ONSdigital/dp-find-insights-poc-api/pkg/table"
+	"github.com/ONSdigital/dp-find-insights-poc-api/pkg/where"
+)
+
+// streamTestSetup adds England and Wales, where only Wales has a value for the Welsh table.
+func streamTestSetup(t *testing.T, db *database.Database) {
+	// clear out any leaked data
+	err := comptests.ClearDB(db)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	comptests.DoSQL(
+		t,
+		db,
+		`INSERT INTO data_ver (id,created_at,updated_at,deleted_at,census_year,ver_string,source,notes,public)
+		VALUES (1,'0001-01-01 00:00:00','2021-12-06 11:52:26.142808',null,2011,'2.2','Test Data','stream test',true)`,
+	)
+	comptests.DoSQL(t, db, "INSERT INTO nomis_topic (id,top_nomis_code,name) VALUES (1,'QS1','Population Basics')")
+	comptests.DoSQL(t, db, `INSERT INTO nomis_desc (id,nomis_topic_id,name,pop_stat,short_nomis_code,year)
+		VALUES (1,1,'Usual resident population','All usual residents','QS101EW',2011),
+		(2,1,'Welsh language skills','All usual residents aged 3 and over','KS207WA',2011)`)
+	comptests.DoSQL(t, db, `INSERT INTO nomis_category (id,nomis_desc_id,category_name,measurement_unit,stat_unit,long_nomis_code,year)
+		VALUES (1,1,'All usual residents','Count','Person','QS101EW0001',2011),
+		(2,2,'All usual residents aged 3 and over','Count','Person','KS207WA0001',2011)`)
+	comptests.DoSQL(t, db, "INSERT INTO geo_type (id,name) VALUES (1,'Country')")
+	comptests.DoSQL(t, db, `INSERT INTO geo (id,type_id,code,name,lat,long,valid,wkb_geometry,wkb_long_lat_geom)
+		VALUES (1,1,'E92000001','England',1,-0.1,true,null,null),
+		(2,1,'W92000004','Wales',1,-0.1,true,null,null)`)
+	comptests.DoSQL(t, db, `INSERT INTO geo_metric (id,geo_id,category_id,metric,data_ver_id)
+		VALUES (1,1,1,53012456,1),
+		(2,2,1,3063456,1),
+		(3,2,2,2955841,1)`)
+}
+
+func TestStreamMixedCategories(t *testing.T) {
+	// GIVEN the database has a category only Wales has values for
+	dsn := comptests.DefaultDSN
+	db, err := database.Open("pgx", dsn)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	func() {
+		db.DB().Exec("BEGIN")
+		defer db.DB().Exec("ROLLBACK")
+
+		streamTestSetup(t, db)
+
+		app, err := New(db, nil, 100)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		// THEN England gets 0 for the Welsh category, rather than the stream failing at Wales
+		want := "geography_code,geotype,KS207WA0001,QS101EW0001\nE92000001,Country,0,53012456\nW92000004,Country,2955841,3063456\n"
+		include := []string{table.ColGeographyCode, table.ColGeotype}
+
+		// WHEN we stream both categories for England and Wales from /query2
+		var got strings.Builder
+		err = app.PGMetricsStream(
+			context.Background(),
+			&got,
+			2011,
+			"2.2",
+			[]string{"E92000001", "W92000004"},
+			&where.ValueSet{Singles: []string{"QS101EW0001", "KS207WA0001"}},
+			include,
+			"",
+			Derived{},
+			table.FormatCSV,
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.String() != want {
+			t.Errorf("PGMetricsStream: got %q, want %q", got.String(), want)
+		}
+
+		// AND from /query
+		got.Reset()
+		err = app.QueryStream(
+			context.Background(),
+			&got,
+			2011,
+			"2.2",
+			"",
+			"",
+			0,
+			"",
+			nil,
+			[]string{"E92000001,W92000004"},
+			[]string{"geography_code,geotype,QS101EW0001,KS207WA0001"},
+			"",
+			table.FormatCSV,
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.String() != want {
+			t.Errorf("QueryStream: got %q, want %q", got.String(), want)
+		}
+	}()
+}
@@ -0,0 +1,157 @@
+package geodata
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ONSdigital/dp-find-insights-poc-api/pkg/timer"
+	"github.com/ONSdigital/dp-find-insights-poc-api/pkg/where"
+	"github.com/ONSdigital/dp-find-insights-poc-api/sentinel"
+	"github.com/lib/pq"
+)
+
+// TileAuto is the tile geotype that chooses the geotype by zoom level.
+const TileAuto = "auto"
+
+const (
+	maxTileZoom       = 22
+	maxTileCategories = 20
+)
+
+// tileZooms are the geotypes shown for TileAuto, with the lowest zoom level each is shown at.
+// Smaller geotypes are too dense to draw, and too slow to build tiles for, at low zoom levels.
+var tileZooms = []struct {
+	minZoom int
+	geotype string
+}{
+	{0, "LAD"},
+	{9, "MSOA"},
+	{12, "LSOA"},
+}
+
+// Tile returns the Mapbox Vector Tile z/x/y of the boundaries of geotype.
+// The tile has one layer, named after the geotype.
+// Each feature has code and name properties, and a property for each category in cats
+// holding its value for year and data version.
+// If geotype is TileAuto, it is chosen by zoom level: LADs below zoom 9, MSOAs below zoom 12,
+// and LSOAs from zoom 12.
+//
+func (app *Geodata) Tile(ctx context.Context, year int, version string, geotype string, z, x, y int, cats []string) ([]byte, error) {
+	sql, values, err := tileSQL(year, version, geotype, z, x, y, cats)
+	if err != nil {
+		return nil, err
+	}
+
+	t := timer.New("query")
+	t.Start()
+	var tile []byte
+	err = app.db.DB().QueryRowContext(ctx, sql, values...).Scan(&tile)
+	if err != nil {
+		return nil, err
+	}
+	t.Stop()
+	t.Log(ctx)
+
+	return tile, nil
+}
+
+// tileGeotype returns the geotype to show at zoom level z.
+func tileGeotype(geotype string, z int) (string, error) {
+	if strings.EqualFold(geotype, TileAuto) {
+		for _, zoom := range tileZooms {
+			if z >= zoom.minZoom {
+				geotype = zoom.geotype
+			}
+		}
+		return geotype, nil
+	}
+	return FixGeotype(geotype)
+}
+
+// tileSQL generates the SQL for a vector tile, and its bind parameters.
+func tileSQL(year int, version string, geotype string, z, x, y int, cats []string) (string, []interface{}, error) {
+	if z < 0 || z > maxTileZoom {
+		return "", nil, fmt.Errorf("%w: zoom must be 0..%d", sentinel.ErrInvalidParams, maxTileZoom)
+	}
+	if x < 0 || x >= 1<<z || y < 0 || y >= 1<<z {
+		return "", nil, fmt.Errorf("%w: x and y must be 0..%d at zoom %d", sentinel.ErrInvalidParams, 1<<z-1, z)
+	}
+
+	geotype, err := tileGeotype(geotype, z)
+	if err != nil {
+		return "", nil, err
+	}
+
+	set, err := where.ParseMultiArgs(cats)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(set.Ranges) != 0 {
+		return "", nil, fmt.Errorf("%w: cols cannot be a range in tiles", sentinel.ErrInvalidParams)
+	}
+	codes := set.Singles
+	if len(codes) > maxTileCategories {
+		return "", nil, fmt.Errorf("%w: tiles can have at most %d cols", sentinel.ErrInvalidParams, maxTileCategories)
+	}
+
+	qargs := where.NewArgs()
+	geotypeArg := qargs.Add(geotype)
+
+	// one column of metrics for each category, named after the category
+	var metricCols strings.Builder
+	if len(codes) != 0 {
+		yearArg := qargs.Add(year)
+		versionArg := qargs.Add(version)
+		for _, code := range codes {
+			fmt.Fprintf(&metricCols, `,
+		(
+			SELECT geo_metric.metric
+			FROM geo_metric, data_ver, nomis_category
+			WHERE geo_metric.geo_id = geo.id
+			AND data_ver.id = geo_metric.data_ver_id
+			AND data_ver.census_year = %s
+			AND data_ver.ver_string = %s
+			AND nomis_category.id = geo_metric.category_id
+			AND nomis_category.year = data_ver.census_year
+			AND nomis_category.long_nomis_code = %s
+		) AS %s`,
+				yearArg,
+				versionArg,
+				qargs.Add(code),
+				pq.QuoteIdentifier(code),
+			)
+		}
+	}
+
+	sql := fmt.Sprintf(`
+WITH bounds AS (
+	SELECT ST_TileEnvelope(%s, %s, %s) AS geom
+),
+features AS (
+	SELECT
+		ST_AsMVTGeom(ST_Transform(geo.wkb_geometry, 3857), bounds.geom) AS geom,
+		geo.code,
+		geo.name%s
+	FROM
+		geo,
+		geo_type,
+		bounds
+	WHERE geo.valid
+	AND geo_type.id = geo.type_id
+	AND geo_type.name = %s
+	AND geo.wkb_geometry && ST_Transform(bounds.geom, 4326)
+)
+SELECT ST_AsMVT(features.*, %s)
+FROM features
+WHERE features.geom IS NOT NULL
+`,
+		qargs.Add(z),
+		qargs.Add(x),
+		qargs.Add(y),
+		metricCols.String(),
+		geotypeArg,
+		geotypeArg,
+	)
+	return sql, qargs.Values(), nil
+}
@@ -0,0 +1,99 @@
+package geodata
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ONSdigital/dp-find-insights-poc-api/sentinel"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_tileGeotype(t *testing.T) {
+	var tests = map[string]struct {
+		geotype string
+		z       int
+		want    string
+		wantErr error
+	}{
+		"auto low zoom":    {geotype: "auto", z: 3, want: "LAD"},
+		"auto MSOA zoom":   {geotype: "auto", z: 9, want: "MSOA"},
+		"auto LSOA zoom":   {geotype: "AUTO", z: 14, want: "LSOA"},
+		"explicit geotype": {geotype: "lsoa", z: 3, want: "LSOA"},
+		"bad geotype":      {geotype: "county", z: 3, wantErr: sentinel.ErrInvalidParams},
+	}
+
+	for name, test := range tests {
+		got, err := tileGeotype(test.geotype, test.z)
+		if !errors.Is(err, test.wantErr) {
+			t.Errorf("%s: error %v, want %v", name, err, test.wantErr)
+			continue
+		}
+		assert.Equal(t, test.want, got, name)
+	}
+}
+
+func Test_tileSQL(t *testing.T) {
+	var tests = map[string]struct {
+		geotype  string
+		z, x, y  int
+		cats     []string
+		wantCols []string
+		wantArgs []interface{}
+		wantErr  error
+	}{
+		"boundaries only": {
+			geotype:  "auto",
+			z:        10,
+			x:        511,
+			y:        340,
+			wantArgs: []interface{}{"MSOA", 10, 511, 340},
+		},
+		"with metrics": {
+			geotype:  "LAD",
+			z:        8,
+			x:        127,
+			y:        85,
+			cats:     []string{"QS101EW0001,QS101EW0002"},
+			wantCols: []string{`AS "QS101EW0001"`, `AS "QS101EW0002"`},
+			wantArgs: []interface{}{"LAD", 2011, "2.2", "QS101EW0001", "QS101EW0002", 8, 127, 85},
+		},
+		"zoom too big": {
+			geotype: "auto",
+			z:       23,
+			wantErr: sentinel.ErrInvalidParams,
+		},
+		"x out of range": {
+			geotype: "auto",
+			z:       2,
+			x:       4,
+			wantErr: sentinel.ErrInvalidParams,
+		},
+		"negative y": {
+			geotype: "auto",
+			z:       2,
+			y:       -1,
+			wantErr: sentinel.ErrInvalidParams,
+		},
+		"cols range": {
+			geotype: "auto",
+			cats:    []string{"QS101EW0001...QS101EW0003"},
+			wantErr: sentinel.ErrInvalidParams,
+		},
+	}
+
+	for name, test := range tests {
+		sql, args, err := tileSQL(2011, "2.2", test.geotype, test.z, test.x, test.y, test.cats)
+		if !errors.Is(err, test.wantErr) {
+			t.Errorf("%s: error %v, want %v", name, err, test.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		assert.Contains(t, sql, "ST_AsMVT(features.*", name)
+		for _, col := range test.wantCols {
+			assert.Contains(t, sql, col, name)
+		}
+		assert.Equal(t, test.wantArgs, args, name)
+	}
+}
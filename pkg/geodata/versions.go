@@ -0,0 +1,115 @@
+package geodata
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/ONSdigital/dp-find-insights-poc-api/pkg/timer"
+	"github.com/ONSdigital/dp-find-insights-poc-api/pkg/where"
+	"github.com/ONSdigital/dp-find-insights-poc-api/sentinel"
+)
+
+// Version describes one load of census data, a row of data_ver.
+type Version struct {
+	Year    int    `json:"year"`
+	Version string `json:"version"`
+	Public  bool   `json:"public"`
+	Source  string `json:"source"`
+	Notes   string `json:"notes"`
+}
+
+// Versions lists the data versions by year, latest first within each year.
+// Non-public versions are only included if private is true.
+//
+func (app *Geodata) Versions(ctx context.Context, private bool) ([]Version, error) {
+	t := timer.New("query")
+	t.Start()
+	rows, err := app.db.DB().QueryContext(ctx, versionsSQL(private))
+	if err != nil {
+		return nil, err
+	}
+	t.Stop()
+	t.Log(ctx)
+	defer rows.Close()
+
+	versions := []Version{}
+	for rows.Next() {
+		var v Version
+		if err := rows.Scan(&v.Year, &v.Version, &v.Public, &v.Source, &v.Notes); err != nil {
+			return nil, err
+		}
+		versions = append(versions, v)
+	}
+	return versions, rows.Err()
+}
+
+// ResolveVersion returns the data version to query for year.
+// An empty version means the latest public version, which is the one most recently added to data_ver.
+// Unknown versions, and non-public versions unless private is true, are sentinel.ErrNotFound.
+//
+func (app *Geodata) ResolveVersion(ctx context.Context, year int, version string, private bool) (string, error) {
+	query, values := resolveVersionSQL(year, version, private)
+
+	var resolved string
+	err := app.db.DB().QueryRowContext(ctx, query, values...).Scan(&resolved)
+	if errors.Is(err, sql.ErrNoRows) {
+		if version == "" {
+			return "", fmt.Errorf("%w: no public data version for %d", sentinel.ErrNotFound, year)
+		}
+		return "", fmt.Errorf("%w: data version %q for %d", sentinel.ErrNotFound, version, year)
+	}
+	return resolved, err
+}
+
+// versionsSQL generates the SQL to list data versions.
+func versionsSQL(private bool) string {
+	var publicCondition string
+	if !private {
+		publicCondition = "AND data_ver.public"
+	}
+
+	template := `
+SELECT
+	data_ver.census_year,
+	data_ver.ver_string,
+	data_ver.public,
+	COALESCE(data_ver.source, ''),
+	COALESCE(data_ver.notes, '')
+FROM data_ver
+WHERE data_ver.deleted_at IS NULL
+%s
+ORDER BY data_ver.census_year, data_ver.id DESC
+`
+	return fmt.Sprintf(template, publicCondition)
+}
+
+// resolveVersionSQL generates the SQL to find version, or the latest public version, for year.
+func resolveVersionSQL(year int, version string, private bool) (string, []interface{}) {
+	qargs := where.NewArgs()
+
+	yearArg := qargs.Add(year)
+
+	var versionCondition string
+	if version != "" {
+		versionCondition = "AND data_ver.ver_string = " + qargs.Add(version)
+	}
+
+	var publicCondition string
+	if version == "" || !private {
+		publicCondition = "AND data_ver.public"
+	}
+
+	template := `
+SELECT data_ver.ver_string
+FROM data_ver
+WHERE data_ver.deleted_at IS NULL
+AND data_ver.census_year = %s
+%s
+%s
+ORDER BY data_ver.id DESC
+LIMIT 1
+`
+	return fmt.Sprintf(template, yearArg, versionCondition, publicCondition), qargs.Values()
+}
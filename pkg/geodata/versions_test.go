@@ -0,0 +1,55 @@
+package geodata
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_resolveVersionSQL(t *testing.T) {
+	var tests = map[string]struct {
+		version    string
+		private    bool
+		wantArgs   []interface{}
+		wantPublic bool
+	}{
+		"latest public": {
+			wantArgs:   []interface{}{2011},
+			wantPublic: true,
+		},
+		"latest is public even if private": {
+			private:    true,
+			wantArgs:   []interface{}{2011},
+			wantPublic: true,
+		},
+		"named public": {
+			version:    "2.2",
+			wantArgs:   []interface{}{2011, "2.2"},
+			wantPublic: true,
+		},
+		"named private": {
+			version:  "2.3",
+			private:  true,
+			wantArgs: []interface{}{2011, "2.3"},
+		},
+	}
+
+	for name, test := range tests {
+		sql, args := resolveVersionSQL(2011, test.version, test.private)
+		assert.Equal(t, test.wantArgs, args, name)
+		assert.Contains(t, sql, "ORDER BY data_ver.id DESC", name)
+		if test.version != "" {
+			assert.Contains(t, sql, "data_ver.ver_string = $2", name)
+		}
+		if test.wantPublic {
+			assert.Contains(t, sql, "AND data_ver.public", name)
+		} else {
+			assert.NotContains(t, sql, "AND data_ver.public", name)
+		}
+	}
+}
+
+func Test_versionsSQL(t *testing.T) {
+	assert.Contains(t, versionsSQL(false), "AND data_ver.public")
+	assert.NotContains(t, versionsSQL(true), "AND data_ver.public")
+}
@@ -0,0 +1,56 @@
+package table
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ONSdigital/dp-find-insights-poc-api/sentinel"
+)
+
+// Format is an output format a Table can be generated in.
+type Format string
+
+const (
+	FormatCSV    Format = "csv"
+	FormatJSON   Format = "json"
+	FormatNDJSON Format = "ndjson"
+
+	// FormatGeoJSON needs area boundaries, which a Table doesn't hold, so GenerateAs
+	// can't produce it. See geodata.PGMetricsGeoJSON.
+	FormatGeoJSON Format = "geojson"
+)
+
+// ParseFormat converts a format name, as used in format= query parameters, into a Format.
+// "jsonl" is accepted as another name for NDJSON.
+//
+func ParseFormat(s string) (Format, error) {
+	switch strings.ToLower(s) {
+	case "csv":
+		return FormatCSV, nil
+	case "json":
+		return FormatJSON, nil
+	case "ndjson", "jsonl":
+		return FormatNDJSON, nil
+	case "geojson":
+		return FormatGeoJSON, nil
+	}
+	return "", fmt.Errorf("%w: unrecognised format %q", sentinel.ErrInvalidParams, s)
+}
+
+// GenerateAs produces the table on w in the given format.
+// include is only used by the CSV format; see Generate.
+//
+func (tbl *Table) GenerateAs(w io.Writer, format Format, include []string) error {
+	switch format {
+	case FormatCSV, "":
+		return tbl.Generate(w, include)
+	case FormatJSON:
+		return tbl.GenerateJSON(w)
+	case FormatNDJSON:
+		return tbl.GenerateNDJSON(w)
+	case FormatGeoJSON:
+		return fmt.Errorf("%w: geojson output is only available from /query2", sentinel.ErrInvalidParams)
+	}
+	return fmt.Errorf("%w: unrecognised format %q", sentinel.ErrInvalidParams, format)
+}
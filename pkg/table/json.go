@@ -0,0 +1,108 @@
+package table
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+)
+
+// GenerateJSON produces a JSON version of the table on w.
+// It doesn't close w.
+//
+// The output is an array with one object per geography, ordered by geocode.
+// Each object has the geography_code and geotype as strings, followed by
+// one number per category, keyed by category code:
+//
+//	[
+//	{"geography_code":"E01000001","geotype":"LSOA","QS101EW0001":1465,"QS101EW0002":1465},
+//	{"geography_code":"E01000002","geotype":"LSOA","QS101EW0001":1436,"QS101EW0002":1436}
+//	]
+//
+func (tbl *Table) GenerateJSON(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+
+	bw.WriteString("[\n")
+	geocodes, catcodes := tbl.sortedCodes()
+	for i, geocode := range geocodes {
+		if i > 0 {
+			bw.WriteString(",\n")
+		}
+		if err := writeRow(bw, geocode, tbl.areas[Geocode(geocode)], catcodes); err != nil {
+			return err
+		}
+	}
+	if len(geocodes) > 0 {
+		bw.WriteString("\n")
+	}
+	bw.WriteString("]\n")
+
+	return bw.Flush()
+}
+
+// GenerateNDJSON produces a newline delimited JSON version of the table on w.
+// It doesn't close w.
+//
+// Each line is a single geography's object, in the same form as the
+// array elements written by GenerateJSON.
+//
+func (tbl *Table) GenerateNDJSON(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+
+	geocodes, catcodes := tbl.sortedCodes()
+	for _, geocode := range geocodes {
+		if err := writeRow(bw, geocode, tbl.areas[Geocode(geocode)], catcodes); err != nil {
+			return err
+		}
+		bw.WriteString("\n")
+	}
+
+	return bw.Flush()
+}
+
+// writeRow writes the JSON object for a single geography.
+// The object is built by hand rather than with json.Marshal so that keys come out
+// in the same order as the CSV columns.
+func writeRow(bw *bufio.Writer, geocode string, a area, catcodes []string) error {
+	bw.WriteByte('{')
+	if err := writeKeyString(bw, ColGeographyCode, geocode); err != nil {
+		return err
+	}
+	bw.WriteByte(',')
+	if err := writeKeyString(bw, ColGeotype, string(a.geotype)); err != nil {
+		return err
+	}
+	for _, catcode := range catcodes {
+		bw.WriteByte(',')
+		if err := writeString(bw, catcode); err != nil {
+			return err
+		}
+		bw.WriteByte(':')
+		value := a.metrics[Catcode(catcode)]
+		if isNull(value) {
+			bw.WriteString("null")
+		} else {
+			bw.WriteString(formatValue(value))
+		}
+	}
+	bw.WriteByte('}')
+	return nil
+}
+
+// writeKeyString writes a "key":"value" pair.
+func writeKeyString(bw *bufio.Writer, key, value string) error {
+	if err := writeString(bw, key); err != nil {
+		return err
+	}
+	bw.WriteByte(':')
+	return writeString(bw, value)
+}
+
+// writeString writes s as a quoted JSON string.
+func writeString(bw *bufio.Writer, s string) error {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	_, err = bw.Write(b)
+	return err
+}
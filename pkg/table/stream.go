@@ -0,0 +1,206 @@
+package table
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/ONSdigital/dp-find-insights-poc-api/sentinel"
+)
+
+// A Stream writes a table one row at a time, so tables too big to hold in memory
+// can be sent to a client while they are still being read from the database.
+// The output is the same as Generate, GenerateJSON or GenerateNDJSON would produce.
+//
+// Cells must be set grouped by geocode, in the order rows should be written,
+// eg by ordering a query by geography code.
+// A row is written as soon as a cell for a different geocode is set, or when Close is called.
+//
+// Unlike a Table, a Stream can't see every category before it writes the first row,
+// so the columns should be set up front with SetColumns.
+// Otherwise the columns are taken from the categories of the first row.
+// A row with a category that isn't a column is an error.
+// Categories missing from a row are written as 0, as in Generate.
+//
+type Stream struct {
+	format         Format
+	cw             *csv.Writer   // used for FormatCSV
+	bw             *bufio.Writer // used for the JSON formats
+	includeGeocode bool
+	includeGeotype bool
+	derive         Deriver // adds computed columns to each row, if set
+
+	catcodes []string         // column order, fixed by SetColumns or the first row
+	columns  map[Catcode]bool // catcodes as a set
+	csvrow   []string         // re-used for each csv row
+
+	geocode string // geocode of the row being collected
+	current area   // the row being collected
+	nrows   int    // number of rows written
+}
+
+// NewStream starts a stream on w in the given format.
+// include is only used by the CSV format; see Generate.
+// Nothing is written to w until the first row is complete or Close is called.
+//
+func NewStream(w io.Writer, format Format, include []string) (*Stream, error) {
+	s := &Stream{format: format}
+	switch format {
+	case FormatCSV, "":
+		s.format = FormatCSV
+		s.cw = csv.NewWriter(w)
+		s.includeGeocode, s.includeGeotype = includeCols(include)
+	case FormatJSON, FormatNDJSON:
+		s.bw = bufio.NewWriter(w)
+	default:
+		return nil, fmt.Errorf("%w: format %q cannot be streamed", sentinel.ErrInvalidParams, format)
+	}
+	return s, nil
+}
+
+// SetCell sets the value of a cell on the current row, starting a new row if geocode
+// is different from the current row's geocode.
+// Starting a new row writes the previous one.
+//
+func (s *Stream) SetCell(geocode, geotype, catcode string, value float64) error {
+	if s.current.metrics == nil || geocode != s.geocode {
+		if err := s.flushRow(); err != nil {
+			return err
+		}
+		s.geocode = geocode
+		s.current = area{
+			geotype: Geotype(geotype),
+			metrics: map[Catcode]float64{},
+		}
+	}
+	s.current.metrics[Catcode(catcode)] = value
+	return nil
+}
+
+// Derive arranges for the columns computed by d to be added to each row before it is written.
+// Call it before setting any cells.
+//
+func (s *Stream) Derive(d Deriver) {
+	s.derive = d
+}
+
+// SetColumns fixes the category columns, so rows with different categories can be written.
+// Columns computed by the Deriver are added, so call Derive first.
+// Call it before setting any cells.
+//
+func (s *Stream) SetColumns(catcodes []string) {
+	row := area{metrics: make(map[Catcode]float64, len(catcodes))}
+	for _, cat := range catcodes {
+		row.metrics[Catcode(cat)] = 0
+	}
+	if s.derive != nil {
+		for cat := range derive(s.derive, row) {
+			row.metrics[cat] = 0
+		}
+	}
+	s.setColumns(row)
+}
+
+// setColumns sets the columns to the categories of row.
+func (s *Stream) setColumns(row area) {
+	s.catcodes = make([]string, 0, len(row.metrics))
+	s.columns = make(map[Catcode]bool, len(row.metrics))
+	for cat := range row.metrics {
+		s.catcodes = append(s.catcodes, string(cat))
+		s.columns[cat] = true
+	}
+	sort.Strings(s.catcodes)
+}
+
+// Rows returns the number of rows written so far.
+func (s *Stream) Rows() int {
+	return s.nrows
+}
+
+// Close writes the last row and anything needed to end the output.
+// It doesn't close the underlying writer.
+//
+func (s *Stream) Close() error {
+	if err := s.flushRow(); err != nil {
+		return err
+	}
+
+	switch s.format {
+	case FormatCSV:
+		// an empty table still has a heading line
+		if s.nrows == 0 {
+			s.cw.Write(csvColnames(s.includeGeocode, s.includeGeotype, nil))
+		}
+		s.cw.Flush()
+		return s.cw.Error()
+	case FormatJSON:
+		if s.nrows == 0 {
+			s.bw.WriteString("[\n")
+		} else {
+			s.bw.WriteString("\n")
+		}
+		s.bw.WriteString("]\n")
+	}
+	return s.bw.Flush()
+}
+
+// flushRow writes the row being collected, if there is one.
+func (s *Stream) flushRow() error {
+	if s.current.metrics == nil {
+		return nil
+	}
+
+	if s.derive != nil {
+		for cat, value := range derive(s.derive, s.current) {
+			s.current.metrics[cat] = value
+		}
+	}
+
+	// without SetColumns, the first row decides the columns
+	if s.catcodes == nil {
+		s.setColumns(s.current)
+	}
+	for cat := range s.current.metrics {
+		if !s.columns[cat] {
+			return fmt.Errorf("category %s in %s is not a column", cat, s.geocode)
+		}
+	}
+
+	switch s.format {
+	case FormatCSV:
+		if s.nrows == 0 {
+			colnames := csvColnames(s.includeGeocode, s.includeGeotype, s.catcodes)
+			s.cw.Write(colnames)
+			s.csvrow = make([]string, 0, len(colnames))
+		}
+		s.csvrow = csvRow(s.csvrow[:0], s.includeGeocode, s.includeGeotype, s.geocode, s.current, s.catcodes)
+		s.cw.Write(s.csvrow)
+		s.cw.Flush()
+		if err := s.cw.Error(); err != nil {
+			return err
+		}
+	case FormatJSON, FormatNDJSON:
+		if s.format == FormatJSON {
+			if s.nrows == 0 {
+				s.bw.WriteString("[\n")
+			} else {
+				s.bw.WriteString(",\n")
+			}
+		}
+		if err := writeRow(s.bw, s.geocode, s.current, s.catcodes); err != nil {
+			return err
+		}
+		if s.format == FormatNDJSON {
+			s.bw.WriteString("\n")
+		}
+		if err := s.bw.Flush(); err != nil {
+			return err
+		}
+	}
+
+	s.nrows++
+	s.current = area{}
+	return nil
+}
@@ -0,0 +1,175 @@
+package table_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ONSdigital/dp-find-insights-poc-api/pkg/table"
+)
+
+func TestStream(t *testing.T) {
+	type cell struct {
+		geo     string
+		geotype string
+		cat     string
+		val     float64
+	}
+
+	// cells grouped by geocode, as they would come from a query ordered by geography code
+	planets := []cell{
+		{"earth", "solid", "mass", 5.9722e+24},
+		{"earth", "solid", "diameter", 12756},
+		{"moon", "solid", "diameter", 3471},
+		{"moon", "solid", "mass", 7.348e+22},
+		{"sun", "gas", "mass", 1.98847e+30},
+		{"sun", "gas", "diameter", 1392000},
+	}
+	// the second row is missing a category
+	sparse := []cell{
+		{"geo1", "type", "cat1", 1},
+		{"geo1", "type", "cat2", 2},
+		{"geo2", "type", "cat1", 3},
+	}
+
+	include := []string{table.ColGeographyCode, table.ColGeotype}
+	formats := []table.Format{table.FormatCSV, table.FormatJSON, table.FormatNDJSON}
+
+	// density; null for sparse, which has no mass or diameter
+	density := func(values map[string]float64) map[string]float64 {
+		return map[string]float64{"density": values["mass"] / values["diameter"]}
+	}
+
+	// a stream must produce exactly what a Table would
+	for _, input := range [][]cell{nil, planets, sparse} {
+		for _, format := range formats {
+			for _, deriver := range []table.Deriver{nil, density} {
+				tbl := table.New()
+				var streamed strings.Builder
+				s, err := table.NewStream(&streamed, format, include)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if deriver != nil {
+					s.Derive(deriver)
+				}
+				for _, c := range input {
+					tbl.SetCell(c.geo, c.geotype, c.cat, c.val)
+					if err := s.SetCell(c.geo, c.geotype, c.cat, c.val); err != nil {
+						t.Fatal(err)
+					}
+				}
+				if err := s.Close(); err != nil {
+					t.Fatal(err)
+				}
+				if deriver != nil {
+					tbl.Derive(deriver)
+				}
+
+				var generated strings.Builder
+				if err := tbl.GenerateAs(&generated, format, include); err != nil {
+					t.Fatal(err)
+				}
+
+				if streamed.String() != generated.String() {
+					t.Errorf("%s, %d cells: streamed:\n%s\ngenerated:\n%s\n", format, len(input), streamed.String(), generated.String())
+				}
+			}
+		}
+	}
+}
+
+func TestStream_WritesCompletedRows(t *testing.T) {
+	var buf strings.Builder
+	s, err := table.NewStream(&buf, table.FormatCSV, []string{table.ColGeographyCode})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s.SetCell("geo1", "type", "cat", 1)
+	if buf.Len() != 0 {
+		t.Errorf("incomplete row written: %q", buf.String())
+	}
+
+	s.SetCell("geo2", "type", "cat", 2)
+	if buf.String() != "geography_code,cat\ngeo1,1\n" {
+		t.Errorf("first row not written: %q", buf.String())
+	}
+	if s.Rows() != 1 {
+		t.Errorf("Rows() %d, want 1", s.Rows())
+	}
+}
+
+func TestStream_NewCategory(t *testing.T) {
+	var buf strings.Builder
+	s, err := table.NewStream(&buf, table.FormatCSV, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s.SetCell("geo1", "type", "cat1", 1)
+	s.SetCell("geo2", "type", "cat2", 2)
+	if err := s.Close(); err == nil {
+		t.Error("expected error for category not in first row")
+	}
+}
+
+func TestStream_SetColumns(t *testing.T) {
+	// like English and Welsh rows, where only Wales has a KS207WA category
+	cells := []struct {
+		geo string
+		cat string
+		val float64
+	}{
+		{"E92000001", "QS101EW0001", 1},
+		{"W92000004", "KS207WA0001", 2},
+		{"W92000004", "QS101EW0001", 3},
+	}
+	double := func(values map[string]float64) map[string]float64 {
+		derived := map[string]float64{}
+		for cat, value := range values {
+			derived[cat+"_x2"] = 2 * value
+		}
+		return derived
+	}
+
+	for _, deriver := range []table.Deriver{nil, double} {
+		var streamed strings.Builder
+		s, err := table.NewStream(&streamed, table.FormatCSV, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tbl := table.New()
+		if deriver != nil {
+			s.Derive(deriver)
+		}
+		s.SetColumns([]string{"KS207WA0001", "QS101EW0001"})
+		for _, c := range cells {
+			tbl.SetCell(c.geo, "Country", c.cat, c.val)
+			if err := s.SetCell(c.geo, "Country", c.cat, c.val); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := s.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if deriver != nil {
+			tbl.Derive(deriver)
+		}
+
+		// a stream with its columns set writes every category, as a Table does
+		var generated strings.Builder
+		if err := tbl.Generate(&generated, nil); err != nil {
+			t.Fatal(err)
+		}
+		if streamed.String() != generated.String() {
+			t.Errorf("streamed:\n%s\ngenerated:\n%s\n", streamed.String(), generated.String())
+		}
+	}
+}
+
+func TestStream_GeoJSON(t *testing.T) {
+	var buf strings.Builder
+	if _, err := table.NewStream(&buf, table.FormatGeoJSON, nil); err == nil {
+		t.Error("geojson streams should not be allowed")
+	}
+}
@@ -1,5 +1,5 @@
 // The table package implements a simplistic 2-dimensional array that can be populated one cell at a time,
-// and output as a CSV.
+// and output as a CSV, JSON or NDJSON.
 // It is intended to be used to build up a wide table from results of queries on the geo_metric table.
 //
 // So input that looks like this:
@@ -15,12 +15,16 @@
 //	HERE	10		20
 //	THERE	30		40
 //
+// A NaN value means the value is unknown, eg a ratio with a zero denominator.
+// It is output as an empty CSV field, or a JSON null.
+//
 package table
 
 import (
 	"encoding/csv"
 	"fmt"
 	"io"
+	"math"
 	"sort"
 )
 
@@ -34,6 +38,11 @@ type Geocode string // eg "E07000107"
 type Geotype string // eg "LSOA", "LAD", ...
 type Catcode string // eg "QS412EW0001"
 
+// A Deriver computes extra columns for a row from the row's values, which are keyed by category code.
+// It returns the new columns, also keyed by their names.
+// Values that can't be computed, eg because of division by zero, should be NaN.
+type Deriver func(values map[string]float64) map[string]float64
+
 type Table struct {
 	geocodes map[Geocode]bool // geography codes seen
 	catcodes map[Catcode]bool // category codes seen
@@ -77,6 +86,31 @@ func (tbl *Table) SetCell(geocode, geotype, catcode string, value float64) {
 	a.metrics[Catcode(catcode)] = value
 }
 
+// Derive adds the columns computed by d to every row of the table.
+// Call it once all cells have been set.
+//
+func (tbl *Table) Derive(d Deriver) {
+	for geocode, a := range tbl.areas {
+		for cat, value := range derive(d, a) {
+			tbl.catcodes[cat] = true
+			tbl.areas[geocode].metrics[cat] = value
+		}
+	}
+}
+
+// derive runs d on the values in a.
+func derive(d Deriver, a area) map[Catcode]float64 {
+	values := make(map[string]float64, len(a.metrics))
+	for cat, value := range a.metrics {
+		values[string(cat)] = value
+	}
+	derived := map[Catcode]float64{}
+	for col, value := range d(values) {
+		derived[Catcode(col)] = value
+	}
+	return derived
+}
+
 // Generate produces a CSV version of the table on w.
 // It doesn't close w.
 //
@@ -84,24 +118,32 @@ func (tbl *Table) SetCell(geocode, geotype, catcode string, value float64) {
 // Currently supported values are "geography_code" and "geotype".
 //
 func (tbl *Table) Generate(w io.Writer, include []string) error {
-	// sort the geography codes we have seen
-	geocodes := sort.StringSlice{}
-	for geo := range tbl.geocodes {
-		geocodes = append(geocodes, string(geo))
-	}
-	geocodes.Sort()
+	geocodes, catcodes := tbl.sortedCodes()
+	includeGeocode, includeGeotype := includeCols(include)
 
-	// sort the category codes we have seen
-	catcodes := sort.StringSlice{}
-	for cat := range tbl.catcodes {
-		catcodes = append(catcodes, string(cat))
+	// set up csv output on w
+	cw := csv.NewWriter(w)
+
+	// print column headings
+	colnames := csvColnames(includeGeocode, includeGeotype, catcodes)
+	cw.Write(colnames)
+
+	// pre-allocate slice to hold column values
+	row := make([]string, len(colnames)+1)
+
+	// generate table ordered by geocode, with each row ordered by category code
+	for _, geocode := range geocodes {
+		row = csvRow(row[:0], includeGeocode, includeGeotype, geocode, tbl.areas[Geocode(geocode)], catcodes)
+		cw.Write(row)
 	}
-	catcodes.Sort()
 
-	// note which non-category columns we want to include
-	// XXX make it an error on unrecognized columns
-	includeGeocode := false
-	includeGeotype := false
+	cw.Flush()
+	return cw.Error()
+}
+
+// includeCols notes which non-category columns we want to include.
+// XXX make it an error on unrecognized columns
+func includeCols(include []string) (includeGeocode, includeGeotype bool) {
 	for _, col := range include {
 		switch col {
 		case ColGeographyCode:
@@ -110,11 +152,11 @@ func (tbl *Table) Generate(w io.Writer, include []string) error {
 			includeGeotype = true
 		}
 	}
+	return includeGeocode, includeGeotype
+}
 
-	// set up csv output on w
-	cw := csv.NewWriter(w)
-
-	// print column headings
+// csvColnames returns the CSV column headings.
+func csvColnames(includeGeocode, includeGeotype bool, catcodes []string) []string {
 	colnames := []string{}
 	if includeGeocode {
 		colnames = append(colnames, ColGeographyCode)
@@ -122,32 +164,75 @@ func (tbl *Table) Generate(w io.Writer, include []string) error {
 	if includeGeotype {
 		colnames = append(colnames, ColGeotype)
 	}
-	colnames = append(colnames, catcodes...)
-	cw.Write(colnames)
+	return append(colnames, catcodes...)
+}
 
-	// pre-allocate slice to hold column values
-	row := make([]string, len(colnames)+1)
+// csvRow appends the CSV column values for area a to row, ordered by category code.
+func csvRow(row []string, includeGeocode, includeGeotype bool, geocode string, a area, catcodes []string) []string {
+	if includeGeocode {
+		row = append(row, geocode)
+	}
+	if includeGeotype {
+		row = append(row, string(a.geotype))
+	}
+	for _, catcode := range catcodes {
+		row = append(row, formatValue(a.metrics[Catcode(catcode)]))
+	}
+	return row
+}
 
-	// generate table ordered by geocode, with each row ordered by category code
+// Walk calls fn for each row of the table, in geocode order.
+// values is keyed by category code and has an entry for every category seen,
+// so each row has the same shape as a row of the generated CSV.
+// Walk stops at the first error returned by fn.
+//
+func (tbl *Table) Walk(fn func(geocode, geotype string, values map[string]float64) error) error {
+	geocodes, catcodes := tbl.sortedCodes()
 	for _, geocode := range geocodes {
-		row = row[:0]
-		if includeGeocode {
-			row = append(row, geocode)
+		a := tbl.areas[Geocode(geocode)]
+		values := make(map[string]float64, len(catcodes))
+		for _, catcode := range catcodes {
+			values[catcode] = a.metrics[Catcode(catcode)]
 		}
-		if includeGeotype {
-			row = append(row, string(tbl.areas[Geocode(geocode)].geotype))
+		if err := fn(geocode, string(a.geotype), values); err != nil {
+			return err
 		}
+	}
+	return nil
+}
 
-		for _, catcode := range catcodes {
-			// Precision may need to be increased if numbers are printed as exponents,
-			// or if decimals are rounded
-			// See the "specific numeric formatting tests" in table_test.go.
-			row = append(row, fmt.Sprintf("%.13g", tbl.areas[Geocode(geocode)].metrics[Catcode(catcode)]))
-		}
+// sortedCodes returns the geography codes and category codes seen so far, each in sorted order.
+func (tbl *Table) sortedCodes() (geocodes, catcodes []string) {
+	// sort the geography codes we have seen
+	geocodes = make([]string, 0, len(tbl.geocodes))
+	for geo := range tbl.geocodes {
+		geocodes = append(geocodes, string(geo))
+	}
+	sort.Strings(geocodes)
 
-		cw.Write(row)
+	// sort the category codes we have seen
+	catcodes = make([]string, 0, len(tbl.catcodes))
+	for cat := range tbl.catcodes {
+		catcodes = append(catcodes, string(cat))
 	}
+	sort.Strings(catcodes)
 
-	cw.Flush()
-	return cw.Error()
+	return geocodes, catcodes
+}
+
+// formatValue formats a metric value the same way for every output format.
+// Unknown values are empty.
+func formatValue(value float64) string {
+	if isNull(value) {
+		return ""
+	}
+	// Precision may need to be increased if numbers are printed as exponents,
+	// or if decimals are rounded
+	// See the "specific numeric formatting tests" in table_test.go.
+	return fmt.Sprintf("%.13g", value)
+}
+
+// isNull is true for values that can't be output as numbers.
+func isNull(value float64) bool {
+	return math.IsNaN(value) || math.IsInf(value, 0)
 }
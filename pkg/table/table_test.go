@@ -1,10 +1,14 @@
 package table_test
 
 import (
+	"encoding/json"
+	"errors"
+	"reflect"
 	"strings"
 	"testing"
 
 	"github.com/ONSdigital/dp-find-insights-poc-api/pkg/table"
+	"github.com/ONSdigital/dp-find-insights-poc-api/sentinel"
 )
 
 func TestGenerate(t *testing.T) {
@@ -191,3 +195,203 @@ func TestGenerate(t *testing.T) {
 		}
 	}
 }
+
+func TestGenerateJSON(t *testing.T) {
+	type row struct {
+		geo     string
+		geotype string
+		cat     string
+		val     float64
+	}
+
+	var tests = []struct {
+		desc   string
+		input  []row
+		format table.Format
+		want   string
+	}{
+		{
+			desc:   "json, no input",
+			format: table.FormatJSON,
+			want:   "[\n]\n",
+		},
+		{
+			desc:   "ndjson, no input",
+			format: table.FormatNDJSON,
+			want:   "",
+		},
+		{
+			desc:   "json, single cell",
+			input:  []row{{"geo", "type", "cat", 1.23}},
+			format: table.FormatJSON,
+			want:   "[\n{\"geography_code\":\"geo\",\"geotype\":\"type\",\"cat\":1.23}\n]\n",
+		},
+		{
+			desc: "json, rows ordered by geocode and keys ordered by category",
+			input: []row{
+				{"geo2", "type", "cat2", 0},
+				{"geo1", "type", "cat2", 123456789},
+				{"geo2", "type", "cat1", 7.8},
+				{"geo1", "type", "cat1", 9.101112},
+			},
+			format: table.FormatJSON,
+			want: "[\n" +
+				"{\"geography_code\":\"geo1\",\"geotype\":\"type\",\"cat1\":9.101112,\"cat2\":123456789},\n" +
+				"{\"geography_code\":\"geo2\",\"geotype\":\"type\",\"cat1\":7.8,\"cat2\":0}\n" +
+				"]\n",
+		},
+		{
+			desc: "ndjson, one row per line",
+			input: []row{
+				{"geo2", "type", "cat", 7.8},
+				{"geo1", "type", "cat", 9.101112},
+			},
+			format: table.FormatNDJSON,
+			want: "{\"geography_code\":\"geo1\",\"geotype\":\"type\",\"cat\":9.101112}\n" +
+				"{\"geography_code\":\"geo2\",\"geotype\":\"type\",\"cat\":7.8}\n",
+		},
+		{
+			desc:   "strings are escaped",
+			input:  []row{{"ge\"o", "ty\\pe", "c\nat", 1}},
+			format: table.FormatNDJSON,
+			want:   "{\"geography_code\":\"ge\\\"o\",\"geotype\":\"ty\\\\pe\",\"c\\nat\":1}\n",
+		},
+	}
+
+	for _, test := range tests {
+		tbl := table.New()
+		for _, r := range test.input {
+			tbl.SetCell(r.geo, r.geotype, r.cat, r.val)
+		}
+
+		var buf strings.Builder
+		if err := tbl.GenerateAs(&buf, test.format, nil); err != nil {
+			t.Fatalf("%s: %s", test.desc, err)
+		}
+
+		if buf.String() != test.want {
+			t.Errorf("%s:\n%s\nwant:\n%s\n", test.desc, buf.String(), test.want)
+		}
+
+		// every line of ndjson, and the whole of json, must parse
+		docs := []string{buf.String()}
+		if test.format == table.FormatNDJSON {
+			docs = strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+		}
+		for _, doc := range docs {
+			if doc == "" {
+				continue
+			}
+			var v interface{}
+			if err := json.Unmarshal([]byte(doc), &v); err != nil {
+				t.Errorf("%s: invalid json %q: %s", test.desc, doc, err)
+			}
+		}
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	var tests = []struct {
+		in      string
+		want    table.Format
+		wantErr bool
+	}{
+		{in: "csv", want: table.FormatCSV},
+		{in: "JSON", want: table.FormatJSON},
+		{in: "ndjson", want: table.FormatNDJSON},
+		{in: "jsonl", want: table.FormatNDJSON},
+		{in: "geojson", want: table.FormatGeoJSON},
+		{in: "xml", wantErr: true},
+		{in: "", wantErr: true},
+	}
+
+	for _, test := range tests {
+		got, err := table.ParseFormat(test.in)
+		if test.wantErr {
+			if !errors.Is(err, sentinel.ErrInvalidParams) {
+				t.Errorf("%q: want ErrInvalidParams, got %v", test.in, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: %s", test.in, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("%q: got %q, want %q", test.in, got, test.want)
+		}
+	}
+}
+
+func TestWalk(t *testing.T) {
+	tbl := table.New()
+	tbl.SetCell("geo2", "type", "cat1", 7.8)
+	tbl.SetCell("geo1", "type", "cat2", 1)
+	tbl.SetCell("geo1", "type", "cat1", 9.1)
+
+	type row struct {
+		geocode string
+		geotype string
+		values  map[string]float64
+	}
+	var got []row
+	err := tbl.Walk(func(geocode, geotype string, values map[string]float64) error {
+		got = append(got, row{geocode, geotype, values})
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []row{
+		{"geo1", "type", map[string]float64{"cat1": 9.1, "cat2": 1}},
+		{"geo2", "type", map[string]float64{"cat1": 7.8, "cat2": 0}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	// errors from fn stop the walk
+	var n int
+	err = tbl.Walk(func(geocode, geotype string, values map[string]float64) error {
+		n++
+		return sentinel.ErrInvalidParams
+	})
+	if !errors.Is(err, sentinel.ErrInvalidParams) || n != 1 {
+		t.Errorf("walk did not stop on error: %v after %d rows", err, n)
+	}
+}
+
+func TestDerive(t *testing.T) {
+	tbl := table.New()
+	tbl.SetCell("geo1", "type", "cat1", 10)
+	tbl.SetCell("geo1", "type", "cat2", 4)
+	tbl.SetCell("geo2", "type", "cat1", 0)
+	tbl.SetCell("geo2", "type", "cat2", 3)
+
+	// cat2 as a fraction of cat1
+	tbl.Derive(func(values map[string]float64) map[string]float64 {
+		return map[string]float64{"cat2_frac": values["cat2"] / values["cat1"]}
+	})
+
+	var buf strings.Builder
+	if err := tbl.Generate(&buf, []string{table.ColGeographyCode}); err != nil {
+		t.Fatal(err)
+	}
+	want := "geography_code,cat1,cat2,cat2_frac\n" +
+		"geo1,10,4,0.4\n" +
+		"geo2,0,3,\n"
+	if buf.String() != want {
+		t.Errorf("csv:\n%s\nwant:\n%s\n", buf.String(), want)
+	}
+
+	buf.Reset()
+	if err := tbl.GenerateNDJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	want = "{\"geography_code\":\"geo1\",\"geotype\":\"type\",\"cat1\":10,\"cat2\":4,\"cat2_frac\":0.4}\n" +
+		"{\"geography_code\":\"geo2\",\"geotype\":\"type\",\"cat1\":0,\"cat2\":3,\"cat2_frac\":null}\n"
+	if buf.String() != want {
+		t.Errorf("ndjson:\n%s\nwant:\n%s\n", buf.String(), want)
+	}
+}
@@ -0,0 +1,33 @@
+package where
+
+import "fmt"
+
+// Args collects the bind parameters for a parameterised query.
+//
+// Each value added is given the next $n placeholder, so SQL fragments can be
+// built up in any order and still line up with the final args slice:
+//
+//	args := NewArgs()
+//	sql := fmt.Sprintf("SELECT ... WHERE code = %s AND year = %s", args.Add(code), args.Add(year))
+//	rows, err := db.QueryContext(ctx, sql, args.Values()...)
+//
+// User input must only ever reach the database through Add.
+//
+type Args struct {
+	values []interface{}
+}
+
+func NewArgs() *Args {
+	return &Args{}
+}
+
+// Add appends v to the bind parameters and returns its placeholder, eg "$3".
+func (args *Args) Add(v interface{}) string {
+	args.values = append(args.values, v)
+	return fmt.Sprintf("$%d", len(args.values))
+}
+
+// Values returns the bind parameters in placeholder order.
+func (args *Args) Values() []interface{} {
+	return args.values
+}
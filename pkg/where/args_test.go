@@ -0,0 +1,20 @@
+package where
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArgs_Empty(t *testing.T) {
+	args := NewArgs()
+	assert.Nil(t, args.Values())
+}
+
+func TestArgs_Add(t *testing.T) {
+	args := NewArgs()
+	assert.Equal(t, "$1", args.Add("E01000001"))
+	assert.Equal(t, "$2", args.Add(2011))
+	assert.Equal(t, "$3", args.Add("E01000001")) // duplicates are not merged
+	assert.Equal(t, []interface{}{"E01000001", 2011, "E01000001"}, args.Values())
+}
@@ -3,30 +3,29 @@ package where
 import (
 	"fmt"
 	"strings"
-
-	"github.com/lib/pq"
 )
 
 // WherePart returns the part of the where clause between parens, as in:
 // (
-//	   geography_code IN ( ... )
+//	   geography_code IN ( $1, $2, ... )
 //     OR
-//     geography_code BETWEEN (low, high)
+//     geography_code BETWEEN $3 AND $4
 //	   ...
 // )
 //
 // col is the name of the column we are matching (eg, "geography_code" or "category_code").
 // set is a ValueSet which contains the single value and ranges returned by ParseMultiArgs.
+// args receives the values from set as bind parameters; only placeholders appear in the returned SQL.
 //
 // If set has no single values or ranges, an empty string will be returned.
 //
-func WherePart(col string, set *ValueSet) string {
+func WherePart(col string, set *ValueSet, args *Args) string {
 	var conditions []string
 
 	if len(set.Singles) > 0 {
 		var values []string
 		for _, single := range set.Singles {
-			values = append(values, pq.QuoteLiteral(single))
+			values = append(values, args.Add(single))
 		}
 		condition := fmt.Sprintf(
 			"    %s IN ( %s )\n",
@@ -40,8 +39,8 @@ func WherePart(col string, set *ValueSet) string {
 		condition := fmt.Sprintf(
 			"    %s BETWEEN %s AND %s\n",
 			col,
-			pq.QuoteLiteral(vrange.Low),
-			pq.QuoteLiteral(vrange.High),
+			args.Add(vrange.Low),
+			args.Add(vrange.High),
 		)
 		conditions = append(conditions, condition)
 	}
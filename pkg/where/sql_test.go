@@ -1,44 +1,52 @@
 package where
 
 import (
+	"reflect"
 	"testing"
 )
 
 func TestWherePart_OK(t *testing.T) {
 	var tests = []struct {
-		desc string
-		args []string
-		want string
+		desc     string
+		args     []string
+		want     string
+		wantArgs []interface{}
 	}{
 		{
 			"no values",
 			[]string{},
 			"",
+			nil,
 		},
 		{
 			"a single value",
 			[]string{"val"},
-			"    col IN ( 'val' )\n",
+			"    col IN ( $1 )\n",
+			[]interface{}{"val"},
 		},
 		{
 			"two single values",
 			[]string{"val1", "val2"},
-			"    col IN ( 'val1', 'val2' )\n",
+			"    col IN ( $1, $2 )\n",
+			[]interface{}{"val1", "val2"},
 		},
 		{
 			"a range",
 			[]string{"lo...hi"},
-			"    col BETWEEN 'lo' AND 'hi'\n",
+			"    col BETWEEN $1 AND $2\n",
+			[]interface{}{"lo", "hi"},
 		},
 		{
 			"two ranges",
 			[]string{"lo1...hi1", "lo2...hi2"},
-			"    col BETWEEN 'lo1' AND 'hi1'\n    OR\n    col BETWEEN 'lo2' AND 'hi2'\n",
+			"    col BETWEEN $1 AND $2\n    OR\n    col BETWEEN $3 AND $4\n",
+			[]interface{}{"lo1", "hi1", "lo2", "hi2"},
 		},
 		{
 			"singles and ranges",
 			[]string{"val1,lo1...hi1", "val2,lo2...hi2"},
-			"    col IN ( 'val1', 'val2' )\n    OR\n    col BETWEEN 'lo1' AND 'hi1'\n    OR\n    col BETWEEN 'lo2' AND 'hi2'\n",
+			"    col IN ( $1, $2 )\n    OR\n    col BETWEEN $3 AND $4\n    OR\n    col BETWEEN $5 AND $6\n",
+			[]interface{}{"val1", "val2", "lo1", "hi1", "lo2", "hi2"},
 		},
 	}
 
@@ -48,9 +56,13 @@ func TestWherePart_OK(t *testing.T) {
 			t.Errorf("%s: %s\n", test.desc, err)
 			continue
 		}
-		got := WherePart("col", set)
+		args := NewArgs()
+		got := WherePart("col", set, args)
 		if got != test.want {
 			t.Errorf("%s: %s, want %s", test.desc, got, test.want)
 		}
+		if !reflect.DeepEqual(args.Values(), test.wantArgs) {
+			t.Errorf("%s: args %v, want %v", test.desc, args.Values(), test.wantArgs)
+		}
 	}
 }
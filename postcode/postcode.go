@@ -1,8 +1,10 @@
 package postcode
 
 import (
+	"errors"
 	"fmt"
 	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/ONSdigital/dp-find-insights-poc-api/model"
@@ -44,3 +46,83 @@ func normalisePostcode(s string) (string, error) {
 
 	return re.ReplaceAllString(s, "$1 $2"), nil
 }
+
+// Lookup is what we know about a postcode.
+type Lookup struct {
+	Postcode string  `json:"postcode"`
+	OA       string  `json:"oa"`
+	Areas    []Area  `json:"areas"`    // geographies containing the postcode, smallest first
+	Centroid *Centre `json:"centroid"` // for centring maps
+}
+
+// Area is a geography containing a postcode.
+type Area struct {
+	Code    string `json:"code"`
+	Name    string `json:"name"`
+	Geotype string `json:"geotype"`
+}
+
+// Centre is a long, lat point.
+type Centre struct {
+	Long float64 `json:"long"`
+	Lat  float64 `json:"lat"`
+}
+
+// Get looks up every geography containing the postcode in s.
+// If the postcode's own centroid wasn't imported, the centroid is the centre of its LSOA.
+func (p *Postcode) Get(s string) (*Lookup, error) {
+	s, err := normalisePostcode(strings.TrimSpace(s))
+	if err != nil {
+		return nil, err
+	}
+
+	var pc model.PostCode
+	if err := p.gdb.Where(&model.PostCode{Pcds: s}).First(&pc).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: postcode %s", sentinel.ErrNotFound, s)
+		}
+		return nil, err
+	}
+
+	var areas []Area
+	err = p.gdb.Raw(`
+SELECT
+	geo.code,
+	geo.name,
+	geo_type.name AS geotype
+FROM
+	geo,
+	geo_type
+WHERE geo.id IN (?, ?, ?)
+AND geo_type.id = geo.type_id
+`, pc.LsoaID, pc.GeoID, pc.LadID).Scan(&areas).Error
+	if err != nil {
+		return nil, err
+	}
+	sortAreas(areas)
+
+	lookup := &Lookup{
+		Postcode: pc.Pcds,
+		OA:       pc.Oa11cd,
+		Areas:    areas,
+	}
+
+	if pc.Lat != 0 || pc.Long != 0 {
+		lookup.Centroid = &Centre{Long: pc.Long, Lat: pc.Lat}
+	} else if pc.LsoaID != 0 {
+		var lsoa model.Geo
+		if err := p.gdb.First(&lsoa, pc.LsoaID).Error; err != nil {
+			return nil, err
+		}
+		lookup.Centroid = &Centre{Long: lsoa.Long, Lat: lsoa.Lat}
+	}
+
+	return lookup, nil
+}
+
+// sortAreas sorts areas smallest geotype first, and then by code.
+func sortAreas(areas []Area) {
+	sort.Slice(areas, func(i, j int) bool {
+		return model.SmallestFirst(areas[i].Geotype, areas[i].Code, areas[j].Geotype, areas[j].Code)
+	})
+}
@@ -114,3 +114,20 @@ func TestNormalisePostcode(t *testing.T) {
 	}
 
 }
+
+func TestSortAreas(t *testing.T) {
+	areas := []Area{
+		{Code: "E07000046", Geotype: "LAD"},
+		{Code: "E01020122", Geotype: "LSOA"},
+		{Code: "E02004223", Geotype: "MSOA"},
+		{Code: "E01020121", Geotype: "LSOA"},
+	}
+	sortAreas(areas)
+
+	// areas of the same geotype are in code order
+	for i, want := range []string{"E01020121", "E01020122", "E02004223", "E07000046"} {
+		if areas[i].Code != want {
+			t.Errorf("areas[%d] is %s, want %s", i, areas[i].Code, want)
+		}
+	}
+}
@@ -8,6 +8,7 @@ const (
 	ErrTooManyMetrics    = Sentinel("too many metrics")
 	ErrPartialContent    = Sentinel("insufficient data found")
 	ErrNotSupported      = Sentinel("not supported")
+	ErrNotFound          = Sentinel("not found")
 	ErrTableName         = Sentinel("empty table name")
 	ErrInconsistentTypes = Sentinel("inconsistent property types")
 	ErrUnusableType      = Sentinel("unusable property type")
@@ -41,6 +41,7 @@ func Run(ctx context.Context, cfg *config.Config, serviceList *ExternalServiceLi
 	var cant *cantabular.Client
 	if cfg.EnableCantabular {
 		cant = cantabular.New(cfg.CantabularURL, cfg.CantabularUser, os.Getenv("CANT_PW"))
+		cant.SetYears(cfg.CantabularYears)
 	}
 
 	var db *database.Database
@@ -95,6 +96,11 @@ func Run(ctx context.Context, cfg *config.Config, serviceList *ExternalServiceLi
 			return nil, err
 		}
 
+		// cantabular reads its variable and geotype mapping from the db
+		if cant != nil {
+			cant.SetMappingDB(gdb)
+		}
+
 		md, err = metadata.New(gdb)
 		if err != nil {
 			return nil, err
@@ -104,7 +110,14 @@ func Run(ctx context.Context, cfg *config.Config, serviceList *ExternalServiceLi
 
 	}
 
-	cm, err := cache.New(cfg.CacheTTL, cfg.CacheSize)
+	cm, err := cache.NewStore(
+		cfg.CacheStore,
+		cfg.CacheTTL,
+		cfg.CacheLocalTTL,
+		cfg.CacheSize,
+		cfg.CacheMaxEntrySize,
+		cache.Redis{Addr: cfg.RedisAddr, Password: cfg.RedisPassword, DB: cfg.RedisDB},
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -118,7 +131,7 @@ func Run(ctx context.Context, cfg *config.Config, serviceList *ExternalServiceLi
 		log.Fatal(ctx, "could not instantiate healthcheck", err)
 		return nil, err
 	}
-	if err := registerCheckers(ctx, hc, db, md, cant); err != nil {
+	if err := registerCheckers(ctx, hc, db, md, cant, cm); err != nil {
 		return nil, errors.Wrap(err, "unable to register checkers")
 	}
 	hc.Start(ctx)
@@ -130,16 +143,30 @@ func Run(ctx context.Context, cfg *config.Config, serviceList *ExternalServiceLi
 		})
 	}
 
-	timeoutHandler := func(h http.Handler) http.Handler {
-		return http.TimeoutHandler(h, cfg.WriteTimeout, "operation timed out\n")
+	// http.TimeoutHandler buffers the whole response, which would defeat streaming.
+	// So streamed responses get a context deadline instead, which stops their db queries,
+	// and everything else goes through TimeoutHandler as before.
+	// This runs after routing, so handlers.IsStreamed knows which route was taken.
+	timeoutHandler := func(h http.HandlerFunc) http.HandlerFunc {
+		buffered := http.TimeoutHandler(h, cfg.WriteTimeout, "operation timed out\n")
+		return func(w http.ResponseWriter, r *http.Request) {
+			if !handlers.IsStreamed(r) {
+				buffered.ServeHTTP(w, r)
+				return
+			}
+			ctx, cancel := context.WithTimeout(r.Context(), cfg.StreamTimeout)
+			defer cancel()
+			h(w, r.WithContext(ctx))
+		}
 	}
 
 	// build handler chain
 	chain := alice.New(
 		clientInfo,
 		middleware.Whitelist(middleware.HealthcheckFilter(hc.Handler)),
-		timeoutHandler,
-	).Then(api.Handler(a))
+	).Then(api.HandlerWithOptions(a, api.ChiServerOptions{
+		Middlewares: []api.MiddlewareFunc{timeoutHandler},
+	}))
 
 	// bind router handler to http server
 	s := serviceList.GetHTTPServer(cfg.BindAddr, chain)
@@ -210,6 +237,7 @@ func registerCheckers(ctx context.Context,
 	db *database.Database,
 	md *metadata.Metadata,
 	cant *cantabular.Client,
+	cm *cache.Manager,
 ) (err error) {
 	if db != nil {
 		err = hc.AddCheck("postgres", db.Checker)
@@ -220,5 +248,8 @@ func registerCheckers(ctx context.Context,
 	if cant != nil {
 		err = hc.AddCheck("cantabular", cant.Checker)
 	}
+	if cm.Shared() {
+		err = hc.AddCheck("redis", cm.Checker)
+	}
 	return err
 }
@@ -15,72 +15,155 @@ import (
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/+w8a3PbNrZ/5QzvnYndS1MkxZc8kw9u2pvNbRq3cfZ2ZquMByIhCRsSYAHQjibj/75z",
-	"wIcoiZKt1NlmW2c/LEWA5/0EjvvJSkVRCk65Vtb5J0ulS1oQ8/iCaLoQklHzi2lamIf/lnRunVv/NVp/",
-	"OGq+Gr2TrMyptu5sS69Kap1bREqywt/fSykkfl9KUVKpG7C0fZ1RlUpWaia4dV6/hoIqRRbUsi36kRRl",
-	"jgBTUeUZcKFBkRUsaZ4Lq8OmtGR8Yd3d2Zakv1VM0sw6/7VB8r7bJmb/pKmh8m+U5Hq5S1a6pOmHh/Nd",
-	"g3mBH1E5xL3SROprzQq6y+u7JQWzDhnRFAjPADeCmMPFT69AVpwjU30h+K7vnrnRmee987zzYHLue07o",
-	"uxPf/8euMAx2Xam9mHWlEJleUkSIiHhVoNwuf7Bs65eLt29evXlp2daLt6/evXpx8bonyTWOqtzPXb3W",
-	"MLTByDgIvWiI5BsqlQGwrZlZxfLsgCTN+q4ke8wNStE3UnT/x/XOXXeIoAXT16koCqaH8S6YhnodlkQt",
-	"9+GMU39OZ7O5P0u8xItDz/ODOMmC+XxGshml3iwKg3k0HiIhJ3xRoT8MElBKsZCkKBhfQLsTKkUz0AIY",
-	"oi8o1zsELcQhVNc9PeyibBZbXj+bAs/xAmd8jxkcRL8N03Ncxx2MC1sh4G5vUGi9eccCc6L0tQkQGF2G",
-	"CMMdsDRQwGwctkf6UVPJSQ6KyhuW0sMuHrrOeOy6yeQfwwpT+npOWF5JeoAo3EGz30+bNzlzJ2e+b2hL",
-	"zkPPcc0/bz9xqkpTqtQB4pod8yr/NwuvzTODpDWLW4HyIPpC8IXIZsAUmBC6g5CTfeELVxDHNvzaj2ar",
-	"rQjdYBqMyA+P+kPMHJ0ChjzpR6pJRjQZSLAiMxLYK5pddvJqMbigySyn95cm9a6DZL6lqhRc0Qfn/Y6/",
-	"gZRvEA4wvlFVHQLeq7/u7EcVmNAkf3ApNySwd53IH1YWGlEMyahB8uXMYyDi39kW43Ox6xj/y3gGr7hi",
-	"i6VW8JIKVK2pwZgC0nniXEj4raJyhXkupVxVCkMUGRWNNaCbLijmwnK5ghMkOeteMKpASLghkolKQSqE",
-	"zBgnmp7NCLo4QmZUnTqWbeUMwRt+a76ty5JyeCluqOQml77GHSmFm7FJd5XMrXNrqXV5Phrd3t46nCBv",
-	"JCcyXbIbqpyFuHGqD6NMpCNRUn626GCd5TWsUZNWR+ORURnTJqZl5dmc8eyMNfI5K0V6Rkpm9ZJ0k3bv",
-	"bAth4+K5NW4ycUn00qh2lH4oKOFq9GlFibzDVws6UFT9XVEFzV4gOXqCXhZYSlClWYE5gRNdSZLDTFLy",
-	"oRSMawWMm5C2YDeU97UDjeOt4OSblOhvoCSSFFRTeWrDlM9ZrqmsSxXB8xVIcauMqhGaKmnK5qynxhWg",
-	"WcHJNwsq8KkPz4G3VFeSK/i/q8s3cMv0EnKmdBtup7wgH1lRFXBD8ooqOGEOdcxSVZZU9vg5RX4oSZeN",
-	"JCDNK6WptOEDXdXUzoReQkOFyZIdo1N+oihGd5OX1KkDl2VtDvkKUsI7QdaM1qaLqI0UJZqOSRGC0zVQ",
-	"LYBwoZdIA6v5eZaxG5bR69nq2ZR3YkCnUVVZ5ig2Q8iM5uL21JnyKb/IlQDZSIlAwfh1QT6CCQyGmJrn",
-	"FumoYxDbOqo0zeAM9JKp2jX1rTgzsmwgGJEjaQXjU45CQeiNtFulrg1CklvkpWPDtJelKKucaJrZCGWE",
-	"ABqRsDkwDUwhK8ayG46Vdf7rthW/qC0QTd2BF5WUlOt8BeSGsByj4vmUn4Hvep4BxfAT9BSrjXQWfmn1",
-	"21ktK2o3fXov4DGu6QKbzzt7KOEPeoIWkJI8NWxuqH8upIPm8+ZbOMPKA7rQRnlmDBO/RZ22dp3ustbH",
-	"iaJ6QTjMkGGAM1CML3LaOQB1Fg78fOW7/ve/uK7rn9a7sKEiZ4qiiFHntXbFvP9d77OxPfwcnKLN/Vjl",
-	"GtMN8q/W7qqMK8xoZ6w2GGpw1/MeSdPKdf1o6+14yjsZiTlIwhcD7Iwdx+lTg0b75vKdwSgkMtbZZBPv",
-	"WjE7a7MwKWdtFynRVt8MukS8m+43ku6weRjfEvN+eDtkG1N+oWv/EiYN6SVFARiICoika9Yafb+++K55",
-	"uLq8QG001gD7zeH1xXfHmMHri+9sBL6p6zZu3KvuZuNzJNRounthCN6jhWbTI2qCV8WMSmSuL/Re1nP2",
-	"kPLBOi4qnFz+9O7V5ZuL16co4T3hAe2aKMgoFwVWKULCSUr0qAuVp7iriYtoxK3NYG1koE15zYINjCtN",
-	"SVb7yW29ihGGzaHouWZnPo1ymkQAtyzPUW81atMLralw4JLnqynftCOT1bqgvmGWDqz/7dVu9+2QaLsK",
-	"8z1G57pxMGr3XbcuYbmm3BQ2BJlJTSk2+qeqjzPW8NZ9Y0N9Y1R26xWtQs6n/BM6xNT6+cpzvSYsWedg",
-	"3uJ7Y6vWOfxavwBwnTAYj0M/cj0vjNxoMrbXS3HkTkIvicIkHgdB6PWWJm7se1EwCZIgHEdu0l+Kk/HE",
-	"n8Rx7MVxmPjdklc/vLf71Fw3qX2LKtf1/SDyEi+YeEEUhJ4b9lAkSRJMgrGX1P/zG8D4f3dTfocOXmw5",
-	"uL1hQw8V18V3W3RNvChMksiL/LEfu1FfWpPIG/uJF/hx6HnuJNoQSexHk8CP/SCOgnhDkEk0CT0vQQH7",
-	"nuv3lybROI7iceBG8ST2Jjviu/jusaX3F7ERe1vt43vU7np+MnG9IAzCMJkkvjfpYXJ9P4y8OPaTGOUU",
-	"bnDqjqOxF3he7Hlj14+jjQ+jIPK9YDIJg2TsJ0lfeN54PE5C1/WiMHRdd+J/Ye3bB9Tv+l7k+qE3joPY",
-	"DQPf7RuAO/EDN/J9L3CTSRR5fVz+OBrHfjJJIj8Iw8CPe2tBOA5d3489dxL7kyTsryVRPJ74YewHfhIG",
-	"4+jfFzgw0LfpeS5kQTSGeVHNcro+p6tT8EDCvrO3cmhbrq1PLk1n1ZRNGYLw3WCrt1WIArsfVeUak1TF",
-	"zc7gyLxx6MSlvm0boLhgCrMKCAkzkuWm2SpoBoyXlW7ypmW+mpMq11+eIKxQurNHKoE2G21LVUVB5MrU",
-	"um0h2gpc4FbSdvltwYKN3maDjkolC2zMrLKa5Sy13iPo9gzCFC5f9iDCoIAZ1beUcmxUDx1NTLk5nPCa",
-	"0wTsokeAb/zN84rHPa2Y8rddJ94/p/jdpxR/gv64KYB5VVDZlb/eCFVyCrdLyqGUIqtS9Km1ug81T4/e",
-	"V+9vEL2DdevnyGG7EfhPkYR/vCSObImPaBAfhP5L9YGP162YrLyneNxTOO4pGvcUjN6Uv39K2X+WlN1m",
-	"p70JsbvuqiMLjKCOLfhhm+ofmN1zSuRZStIl7WX1e8xe0496VOaEbYlo21t3xEGLUq+ghV6HQoMbDB00",
-	"27C1ozVV0C+rKUkLcUOB5DlQro3851IU7Ul7zUpf0pLdEE0bUS/oQP0kSmq0zF9l1rn1kuqXVBxRCNh/",
-	"VCHwsrOuVGTUBrqA792JGWwIDh0BmtvKoyL8GhNCMZi+pR9zuoJDeMzTYx9F8SrP77628PGS6vWNaQqM",
-	"zwWQmag0EA5EUuLAzygeUwWY2x7K9JJKaLSBwbIRGJzMKm1udGZCL0/3xYxlNwk42AS0FXIzmLIzNVff",
-	"vJmpQRAcMlpSnlGu21tjZX2mauwHiruZZNwV91U/0bVXbpc/tCwY4bWEz4cIxzznTx7NLjpCdyltMMIt",
-	"kWaIrCpRjxldSJLRDE6IhpwSLAc5bWaDGIdmOgW3tuMpDXOnX11abM3o4qdXz7aMqWeYmWitsq2K7+tR",
-	"W7jICtRLs63xhF7ZbOJ7a7hnJgQpGzqINxQwGdrGppWWVaorSeGEcY0FfclSZUM9dQNUp2ZU4atv8v6u",
-	"aNM2m/EX9RxBAJvDSlRwS+p+Y0luKDyrNzzrVyTruycjO3P/3V9vm/22CDD33d93F+97QnqfnqG4PhMi",
-	"p4Q/QtX+kCGmbvppwLYvf7C+xhTRzV/tCeqFEmT0qRRKY1Lou89Be/2p+QDQLuHqF+8CvIuLfcbZgn+I",
-	"gR6RqT+7Hn1x9f8YyX+8urwwdYxxY0Pr11d6YtTaopRpBU2hM6hT40O78fCrD0C/Xr65Mlyq9ydLrUt1",
-	"PhpR7tyyD6ykGSOOkIsR/hpdvrm6TkXG+OJarZSmxWnXPPWnyPSSaIxeU27Cl4nxZnBjfbU+fLH+vevV",
-	"w7qnuONBl+vdJ3b75HdPYwOG5jkrFVM9SGhgbFGJStWDEVtAe4Q4jtM8e+7m/b052Lz38h53Pe+g1ff3",
-	"m+/6GPaW2PjJI17l95Nvq6w61TzmsRd0czX7pmraW7Aj9N37yO7dnvaeP1/rPdhmLqb5ta35VOQPmdIR",
-	"uXreg9jT/V48+w8KRf5o+r8VkAu+sCEnujflCSVhUoGkpaSKct2eloqyFIppZFtyZFfMgcBMVBzjAMzE",
-	"x0Z6s5n4+NzcHyZ26DlBNA5t1/FcL65/BrE50X+3ZKoemVA0p6k2/f1O9MhZ3T2Z8oWpDXwOfItYG7Gb",
-	"8fP1BIeN1TbjaEwzVg+ZrofupnzTa4EoIHBbW1lNDuLoznL2n9wis0c31ubgti6tVP0XVM2xnRZA2suR",
-	"dOsoqXWjLRcScthfUMTHjEA1A1Dr8af/zAGltyRjlTJpOhd1vQAnZgAVGW6z4+lBwzNmImtATf9sHAX9",
-	"BJ2jg2zDlBvO2xdbZt+4uoH0HEO7A0P0fYYBP5b5tjQcZ8JPQj5KyDVtRw6/XYCiv1VmdF7MHxyoCaS5",
-	"QBZLka8WqBaTx5/Vb59BfdCNzM2ZVLoWEVE7YGHKi0pplJi5ryUFPW28vIH83HXcJAiMFpJJjBHe9+qf",
-	"E8+1D8Z/e/NbB3ZywZQ/KBs0tHREPZaWsWA9Xs8NEff50mBeN/WSObGwHqEpS9XNV3psavhuysOD/ZP/",
-	"gEP7nxshPnVW253VU2P11Fg9NVZ/wcbqD+2r/gpt1VNX9cUL/qem6knGf8Ke6o9tqf7YjuqpofpSDdVr",
-	"LBabmRIFBdHpEqWrKJHpEiuyjNXzgXuaLXVLFov6PyIzeG+PafNzJ0N2GFpPcZu/O58LWdRxiilM3HSL",
-	"twZ3S7f5uUF1xX4n3Ua5S13k9xDc4YO/vfvxtSH8wbR+wnbyrtfOinZic7ul/UnSec4WS73b0w7d6ta/",
-	"7neNwXb2+AHcTcnI9V18XwwvLt9eQdnyAfVfcF61o3qDRnh3968AAAD//9rohQrjTQAA",
+	"H4sIAAAAAAAC/+x9/XPbOJLov4Lie1Wx92iKpL61lR88mWw2bzPxbJybudrRvBQkQhI2FKAQoB1Nyv/7",
+	"VTcAfkiULDlO4sx672oikyDQ6EZ/At345E3lciUFE1p5o0+emi7YkuLPZ1Szucw4w7+4Zkv88X8zNvNG",
+	"3v9plR+27FettxlfpUx7N76n1yvmjTyaZXQNf9ve1j8xTROqKXS1yuSKZdqOMJUJg3/tl0pnXMzh0yWj",
+	"Ks/Ykgn9LhdcQ6OEqWnGV5pL4Y08NifPZC60529/LeiyuVuV5vPmF5ruGehnlikpmkbSdJKyW1EEjQok",
+	"3JSYkpN/symi7nmWyWwbP8w93gAJHpMlU4rOmed77CNdrgAObyrzNCFCaqLomixYmsptsG98L2Mfcp6x",
+	"xBv9Zgf5vQGqvzOa6kUD2RZs+v7wJWK6eQYfsaxpoShNM/1O8yXbnuvbBSP4niRUM0JFQqAhkTNy/vNL",
+	"kuVCwKSqSIjDODwLe2dR9DaKRp3hKI6CbhwO4/hfnt9M+1ztHFnnCgbTCwYDwkAiXwLeLv7h+d6v529e",
+	"v3z9wvO9Z29evn357PyV93vDGPlq9+zMOzuh2kTanW7UawL5imUKO9ikzCTnabIHk/h+G5OVyTViMUYs",
+	"hv8VRqMwbAJozvW7qVwuuW4ed841Me/JgqrFrjH703jGJpNZPBlEg6jfjaK40x8kndlsQpMJY9Gk1+3M",
+	"eu0mEFIq5jnwQyMAq0zOM7pccjEnriXJFUuIloTD8CBrtgCay31DvavQYXtI+9LN9c4QREHUCdq3LIO9",
+	"w2/2GQVhEDbKhQ0RcLNTKDhu3lqBKVX6HQoIljQDBi3IAnsh2LB5PbKPmmWCpkSx7IpP2X4W74ZBux2G",
+	"g+G/mgmm9LsZ5WmesT1AQQuWfD5s0fAsHJ7FMcI2GHWjIMT/RbuBU/l0ypTaA5xtMcvTr4w8p2caQbMv",
+	"NwTl3uGXUsxlMiFckYt/NA3o1Pf2aPAGxtjs3/DRZL0hoe1IjRL5cKnfNJmjVUATJ93BLjreskELRR1k",
+	"oqj9YL5haiWFYgfr/dLk2Vb57t0lo9l08YapPNVfHg/vXId1msNTR21s5xOZuQdTa8U+UeZdoxm4XjV0",
+	"i82hJ9eFd9SyqKJGHY306tdNBHjrLNcNjNc8gH0DVXyFG/9eKSU1TQ92O5owWDe4b5njQVjdcmUaMHo8",
+	"ClZy9Q5kzfbSWclVnlL4o1yUZAr+jvIJm5PzNCW5ymlKMqZ4ArB6/lE4XvHp5+P4CDcRmjeuQzvIl+P9",
+	"BrPmxve4mMltvP+Ni4S8FIrPF1qRF0wCsdHR4IrQQt3MZEY+5CxbgzE3ZULlCvQwbS3t+gBdNGdg8K0W",
+	"a3ICICfFA84USIUrmnGZKzKVMku4oJqdTSjoMeiZM3UaeL6Xcuge52vm7V2smCAv5BXLBBqMr6DFlJGr",
+	"Ntp0eZZ6I2+h9WrUal1fXwcClxFNQRzwK6aCubwK8vetRE5bcsXE2bzo6yw1fbWs7dhqt5BkXKPiTlZn",
+	"My6SM27xc7aS0zO64l7FErW25Y3vQd/wcuS1rbm5onqBpG3R+Txjc6pZ69Oa0ewGHs5ZAx9c5ktFGJ0u",
+	"iGIpm2qWFMKUANjIHVW8Fs0ma5LJa+WTyUR+9Ekqp4iHVkYTniswmFoyIyuZrudS+GNBFeGCtJCqsU/m",
+	"/ApoKwUjMz7PM0ZWrBTkuABg6OuFTJkdlEsRkLcb8OgFBc9H6IxPcs2SsaAZIylXACMX0BYWugrGYizO",
+	"ieJinmIPsGRh0TlP3SdKmt7YnLy6vDhXBHoSUhu5wBJC55QLcs31gqPY4Bl5df4jdO0hOTLEwMvEG3kv",
+	"mD53NEDKZHTJNMuUN/ptkwbPzPoGQgXkWZ5lTOh0TegV5Slw9WgszkgcRhGOw+EToLTnONWDL71qzEFn",
+	"OfNt3KnCsFxoNmcZcOwmDL9dvL4kiKjfT2Btq1GrxURwzd/zFUs4DWQ2b8FfrYvXl6DluZi/U2ul2fK0",
+	"INYWXdYyH4trKjC2QKFdQJ5RQSZmTpYYVzTNmSInLJgH5HkYGVP+FFqAS0vPFAP8IQVAqoHhUPmm/MR3",
+	"v+LiVxu7YWnKV4qrSk+wZPg8B/mQUTFnG51WAAmCwP6OwlNYRT/lqQaZiuuflKQlU5waUflqlXJYUdgR",
+	"tHpa9DbOwzDu1Z9VRyhpjJxSEhk+8apELdTCDmvJqYBtYgMPVYRqQSuCpDp5/QM5A4OfFMKWiWQludBE",
+	"S6KYYS9A2HR7sVY7Pr2V2v+8jMLo+a/H0bvykV/+jiu/7071St9BEBR/bVJ+KtMDKA+tnlZ6rNB+5zg7",
+	"6Q+d3Rf9ryVJpZj7JKW6oh7JivIMJOIqY4oJDQIa+FquVlJxDdPOBExXzgglE5kLEANkIj9a7IEqeBoG",
+	"Ubs98LtR0Om1u34YRGHUN392+qfBWLxdcEWueZpauU5omm7LjpSzUtRyVRsuID/AoBbr6JtyoTSjCZEz",
+	"9C24gLU04UY5Y0cwlbGo8yyhilBybdaY1TJiXloWwU5ywFS9BhlbGkWbWH9hdc6Mp7hmMLpqnA/gK0rU",
+	"ik35jE/L4Ql84Jhog4Fk1swtwVicayTbUqL9ohdMAQ0BDKPTYJnKTLME+fLV+Y/4z+XFeW2NOx156zK3",
+	"DZ9CR7jCiwfY5Q4E2kb3tKTfFFZHYYmQk4nUC5yw04yne5cdLhJrvljfFLkEmAQ4o+jZHwucuPt7Y8lb",
+	"NseOnoJYD0gTeMevXnJfi9fBcNwCfsTxMTg2sHnHmWHnRLEPObobcnawjKZkmkqYorW2rQp/Yp4+IUtG",
+	"hcLJzXimtEERVVvdjsUyVxoQBm0VXbJTy+K246dhEA46HSTCYNgH2R5H5s9hFPp7Jb9f/zYgm2pgLA7S",
+	"AxaUAqZ7ovFY3IXIFobb+KjpU2MnuYjXEWz4d3ld93RNCIMl8yLGVvhLyJnWfUFhb/ypE2iUsBnNU31q",
+	"3ivCrlhWdaoLT6/mucHPWZ6m0BlFNYJ81twFEhCWe6bYVAMnNTuLFUeRXDPwfcGRI+Bj4qrNqJmNnBGu",
+	"UYdRIDVPWH26xtiGFSGkLhYFkr2AdTcxl0wvZHIcMX6kmrqdIB8wZT3PyZo4J18Zp9WiG1xOlHpUM6XJ",
+	"Kp+kfOp6KDwp9AXH4rUUZ/UWigjGEre3R7R8zwQx3ig5z/VCZvwPs9gXjCYs2z1b2+He6f7ue5kNSqNa",
+	"jsPQw8iR0ExgNIGCJWCJ+W9ltsqKnYhPnvO+IZZfuDueX/yOIZLvLIGRBwaD5ztCjLyCaJ6NWiIUFdvZ",
+	"G8XhsB10/crDGB4OBkHcvcFAVJ1gKl9CqKjipXg3vtc5aGIlovZF4sxRg4ahl1wpjHlkZEKTFIMcSxRa",
+	"q1xbs87Dr3ClfHmAuKhsvLCMMNvQRyzRbG0CRDVfEYNCFAULBarQORLXrFLvd/i4NckYfa9uCz294u8Z",
+	"aU3fo3byySTXllPJdCG50YDTlCrFZ3bexCyMgLxhOs+EIv/v8uK1+8j5pBp3/FcsIwgG+q1jIWcmyoUd",
+	"+uQ9W5t9YTRfnKULetFFoHz8i5IlF++W9KP1RIE/TT+2GWm5r8fiRLFyQqap4QR1GpCEX/GEvZusybXM",
+	"3itCTQvXfEcM6QfE5PcQQNqMKRQI0pJMaTqFaDszr8zygOn7DWh4A3556Y7HYWxcenST3V+dU9Rsry/e",
+	"ls7MblE3pfoeIyfQBhZaRc0dOcWv4hEBqCJfTlhWMBJTAaoFTXlKlnRNMuQjMmPXLAt2QPX+SBP25OLn",
+	"ty8vXp+/OiVnO1cERJeoIgkTcskF1UfhqWCl41T1syZZgqzhWLZuFp0RQXWe0dRS00CYyWUBInz7IadC",
+	"85SRs8JorqC9aqdxURFB8Cn7APtLZ4420N48uuaJXpAJ09eMGdW+5ALl0ZJ+hC+VThJ2Vf1U4Ka6SGiW",
+	"kIRdcWfwJswHIuiMJc5QBNChk2IlmJ8t/F0uXHh4tT7T5sgGUPCv4HUwqhnoLrVKwRSDDm1Agk7kFauM",
+	"cI9WVm1N3X+ousq+FgGg5symBa5JY/s2R2L85jgMWrUNIUiMO8LqfjkjQu7bQ/ELF0dmzgUCqTfnV0z4",
+	"DlJ4UoCfWP1cd6SCrxFUrtHo/iOMY3FwiLEUJZZs9xjEq82yiFwYDjNAW5emdMFOjopjHAH93aI4tRlY",
+	"YDmKwwyYOAOcF9AeDsydwh11XVGPYeyNfxwO14Ee+h64XhR6HoVOo7gw3OmTXLkVXVoHC84y2Bteu5CW",
+	"afz0eRSjD9TfDpri1PBHrX/ySopEikIMoXcrBfrE8lpgyENLkjGYGQRU9m3Qlj53uVb3SAoDwT3Jikev",
+	"ea/XXPNlP3mvzn/0Rr9FcRh0/agXBnHXj8PQjzth0O/68SD63Yc276yr4o1+G4T4uMn/tQuqPOyYrivq",
+	"49EX3ucLl2yPdlfVViDUqOXSygVzrb6Rs8tdttbkbf7yf4OhZ9sSmsIBK71YAsMzpfkSwKoZrOj5KrfM",
+	"LXRN9vjJX6ZU/6WMh576ZCzMLpVxkqVIzWGPgtPsVhXbnCI5+YuVY9X+mhz2qrs+Fkv6kS/zZbEXywMW",
+	"NHryp6UZjZgg0zRXmmWHuPTEuugVr/xiZc7uIBOIApFmolVPjguCnjlaJlKwmjdDhdQLgIGb+TwpPJQn",
+	"Y1GgAWSY2zkjCMiEpfL61JxMSZW07pg6JuqA2y4MheeZiY/jOSp9Lc8Ql7aHIvK95GIsrCfhsO2IWi6I",
+	"jF7DXIpp4CkYe2QOtv2WXLSgA4sSDmFZwpWZyg8H26U+yUXKlKo+w++K0DPCvc9EHgunxOyBI6eJNy33",
+	"8wJj2KeQm75ZPW7OFUnZTBOZa6MM/pwBmAB44h7Pe4xFceCDkF1HPlwQJz41rQ489FFEgvzm352NIxpU",
+	"H3JEg2r1tOwjNrbYxtP2WBQ4kjPrwd0eoeKqHqAqGc0KcYfmhxm5OvIgAVASjxLgD3uYwK4Gsns5vDr/",
+	"8Zhl8Or8Rx86P/1+jyrUA3NVpFdU+TeOxpGTKdWtQv6fQisr7GcyK9YMWHDY21i4GFm5A0tAi+BbkDB8",
+	"RpYV1iyWjyWO1W5mP3jCrOpBj6CEIiAXIl2PRX0doap2berLMiDl/+47nPgYk3qMST3GpB5jUo8xqceY",
+	"1J81JlX2V2aX1jMofKd3nD0xGotPYM+Nq1GssTci+BSeo6nljchv5gEhYdDttNvduBdGUbcX9oZtv3zV",
+	"74XDbjTodQf9dqfTjSqvhmE/jnqdYWfQ6bZ74aD6qj9oD+Nhv9+P+v3uIC5eRebH734VGhc524AqDOO4",
+	"04sGUWcYdXqdbhR2K0MMBoPOsNOOBub/Ytsx/HMzFjdgny437FO/ZgIdiq7zHzfgGka97mDQi3pxO+6H",
+	"vSq2hr2oHQ+iTgyp/eGwV0NJP+4NO3E/7vR7nX4NkYPesBtFA0BwHIVx9dWw1+73+u1O2OsP+9FwC31l",
+	"3PG+sPcfskb8TbK3byF7GMWDYRh1up1udzAcxNGwMlIYx91e1O/Hgz7gqVubadjutaNOFPWjqB3G/V7t",
+	"w16nF0ed4bDbGbTjwaCKvKjdbg+6YRj1ut0wDIfxF6a+v4f8YRz1wrgbtfudftjtxGF1AYTDuBP24jjq",
+	"hINhrxdVx4rbvXY/HgwHvbjT7XbifuVdp9vuhnHcj8JhPx4OutV3g16/PYy7/bgTD7qddu/rCQ7PL9UY",
+	"hMWp9kZeIvNabrTxIBsU21Zk20Ub9oT847CzEW9WMIQs8hNmYIE9bg4cuDlgEX5P+wLod3/ZzQEcojxw",
+	"ci33bReMBW4YRDbCr1lGWgSexPU9hPvdQRiLN0V0vLp38Nk7B3+C8K6N34h8ybIiehO1gCSn5HrBBFll",
+	"Msmnzg8w5N4X+7v3sPDu+GZ0nBt0AB4241jfCybi4zFxZET3iPjmQcN/tTDmo3N2mHOGRsgOW3mHnbzD",
+	"Rt5hH0dj8fujhfJnsVAcH+zU/0UhFyNISYsYUTqTpWVzoDGTMpqdTel0wSpGzC3LXrOPurVKKd9A0SYD",
+	"baGDLVd6TVzvRvLj2AThYEltrR1NqSX7spTK2BLO0kLInAmN+MeDx3az30yliumMX1HNLKoTrkw1DID6",
+	"FrvRGVW4LSlzoXFz3wezysdzvPDfhMO/H3KaaZ4y5TcdNTbZEguuNFbhc+aZS1Y26RdjUSvwAY/K2Ag8",
+	"STJ6DXwl2EfUrdBFeQKDkiVdkZTNmUiCIgbKWbnbV6SsiWQsyk0hDEq66KzpD48qgMIoQV5QRSZcHHoQ",
+	"22QYzqp7HnYXRAoGHZVzxCawWzCvzsicJTd/nAbkObbkRTLd1tHqVF77JF+ZEycga6ZpjlsRCz5f7Egg",
+	"+bGyFr53M9fwB1fsz5M2sndKX2U3uratUNpzJVc0cIRPIgA+gqzrDZsrCnEnYCv9smC6XbteXHzWRg1V",
+	"U2b25azfB+ZAkWJen8325qjdxkMYn4ZB7IdBxw+Dnh8GAz8Cq/2XzRwHzJ/GL8hconASUMgwozDEDhzw",
+	"fWSduFSve6fqQ0rC+eK75iVHPeyd8s1cd3NuwcKePO6OP+6OP+6O3+PueMFaX2RbHHp5hSXjHjfE/5OT",
+	"ND4ZOwZMazTivVEUd3wP7HNvFMEuVRzEvpfKa28UR932jV827PdcwzjqtdtR0LEN3XcAsm3c7gx8D1M9",
+	"orDfDjtdvNBBeCPYI4uGQdv3jNcGAHT7nZ7vLbkwY/reh8gbDTvtYR/qJnxoe6No0B1Gke+ZtFJvFEXd",
+	"sBcHw5umBJKqd/mYRnIX776URZhvXMUn6qOyBIEtbVCYr7uCK3PWsEG05Qu+YPIIF9D/Vi5gXXpjNd7n",
+	"4RDlb2efUwStj67IZkeCXkDWPBfzlKsFrKdfWaoWOPoP7GPK1vuGxl9HDf3KXVBgPRQHAPx2cmlEmCAn",
+	"FqRTgGm6JicI12lAXtOlraclc02ogbeQtjSrzicYi4srlmUctSHI0+mUrfRZAYWVqJvSvPx8l0FDxXzv",
+	"xO8iZkWepjcPjWlfMF3a61MC1Y3BGwTUI7ZpQP4JuMEtH+RbxvWCZa4ALZDPrhRyArVO0D2UenF6O1O3",
+	"PtlOblpUTJnSMlN7yqooGzyquRfouDpY7EIrzB00XnzyE/4XjtiTN2yOah+vAAJR9PxXfyzwnhiQtzhB",
+	"bhIsmNCBXX1QDZcs6BUjQhZdKGlr5Zq2sK5+pSnbVfLkBZPnxST/NOKqPmwprW4d+b4Mlt9cwW/veRgX",
+	"dZjK0ks/mdJLFsBnXGPqhbFlCTS++X2bcdy2Z2WlGdXf+fJMm4v3Ql4XBZ4fnJYHPtzBhlZiFKmZ2/7H",
+	"UTJhuuBpkjFxpEhIeMamwC6YhvjZssHGtn9ybhCFxgH5letKjD9FSCD2sQkN6kGqTZh0EyJMKSnHeFUf",
+	"Y6cceeYQ8yhGbi9LC5v4DKOoVJiKtEAs31VDPyoc/Si7/oSyyzDl3WXXorjsbu9OpGm2fTGcSeXGi/GI",
+	"FCRhKyYSJrS7M0J5dzQ1/QMxai/r28boZdUJdjncF/9wU0BbyQE+awIcll08vDfSF4BuQ2pHJNc0W5rN",
+	"ErBLEzbPKGTbnVBNUkaVNinuADMQ2l7ABE3dDUx2cqcPbum6ZXT+88snG4upsjAT6VYlRtzYMdvkuPxd",
+	"PcEiqXwhFTPhb5qtsc485UJVwrJqSdOUKW1q8BplhufLPDxZti6ajj3c24ZuS//O9ux2Cuq56Vu67xVO",
+	"6ntQe+f18s82TXdX6P2AuPo3MagjOCMf9ryGWqYWwhcZY+KaTxckbEfn3o1f+RpUWruzT6XVvq5/jCZC",
+	"VP8YYpHb3+7VgrColVu2eIOiQ+m3DuVVAXlQkgavMnICoXIRDUXDFI+H7lKF7hzpoWIHZkXMq8nGjUgl",
+	"u5rzJE5bnmFISfmk6BE2rtlHbQqbKp3lU51njJxwoSXB+6qUby7CUoTp6WlwhPy47bzIWFxgMATaKjcT",
+	"Vl69pTan8YwKTSd5CqIJT29hgRHCBDRJgi8okf5buSsaTMXhp9AFnPMpLkjR0sQ4npgGT6rH5MqdYpwG",
+	"1oWpvt8I8Zk6MM+LgjQ75FsVniZDeyJlyqi4NdAIRLY0rlfGwbVyl3ijzqhQ9iK1+405kss0nxttR9Nr",
+	"ui7ffN1o5KGyZOsWxwaxcvEP7yHGNR3oB4qrVrmeW58wBHGrAKPVWx6BWni40N1ATnLBtRVLVHOl+ZSm",
+	"+NAIq0JMgBQAN0TMwUHdYfy42TwrL4V88MfeHKyl819NB90x7uf7/lXh8CgCPpsRty+x3CUCvmqUwbHe",
+	"g5Q9RUoN+ELVwmqHCiOF97DulEB/yyHYCMeJTUNY6m6Xb5ciRAb88FRn9IqlBK5kVGTswVFaKqaMmOep",
+	"yWyDau5jLxiLX2WW2GNcmsEdA7jz8eHpQuaKLWQKL1MlyZLq6YJBh393b8DjM7yEw2MnphmeWsYD8DBQ",
+	"om4ReeZO2u9B4BlsYfYUUgUrpbO5xW2B2F1s/uHz5N5PNmmwPO9q00j2nGyNwz1Shy+5PvIk1ZbsrdiG",
+	"AMrDEsUP2+aq3+Tc5FwCN+F5L+ttlDyPdQgnRWDm8ejK3ttADLfuEpyHymxDhcONR2xfWI7Wjalc4VyY",
+	"jVuENTvx1dqZT9RYHGR73iJr39qbmx5+SoW927puWD4ald+JUVm/a/1hWJTaXTb+8M1Jd8Fas1xSkrY+",
+	"raTSm5JoL1f/bD8gwL3k8tfonETn57s4ynV/z8HpO+dGPrv8BVgTIswoFVD4IawPLw0SFMAGpFwbPbCL",
+	"pg7dzXTdva0jcw2KF3d3jLB0Rw3M4Qcc/NX5j9UgObRwo5CT+g7PqW8KLVOTayC5qf+Kf8C3S7pSAXk5",
+	"q3XyBM+Il19cU4UHxfjSJKsZoNzrseCqfMDc9XgAc2DOYi1ZNmcJUVxMGeqZQ3aPfi4X7EFsYLWszAop",
+	"rlZ0at2ob8QdtxzexpD95h5OHEZxtGcP560ErTNnJAw7z7a2cDpx3N6zhfMDT9hMZgm5lLleEJNIQJ5T",
+	"pes99fHER2/3fo6DwgMsuJUAc0qp9kbdKAijXhz5HkTJvNFZJ4jDqD+88T1JcYAwCqPOMPT8EvUj7/n/",
+	"tIeke37exKnVMwA71v5XM5prlnJ99K+g/gpWB5YsCg48rJMbUr6HXf3NmzgrhKOkwnONIhTNn+29qQdv",
+	"6d5/PqG9GB8uhxWaOMOiuNae7LrXvrjr8vBb7YtPfPcrLn7d/T77otcgCOzvzbvs65fh7qqcjbfWF71V",
+	"brJvHOFrpC9upoo7YplNuvss2kOKova7StoXF5IeTu/KR375O678vjvVK31j/rv9a5PyU5keQHlo9bTS",
+	"Y4X2O8fZnVMv03uj/71nrJKDE1ahHtnm/dXkoOurq+MF5AcY9Q4XWJuswfu4pvz4hFp3YNRsSuNJq6IK",
+	"j5aEutJu043KMM351zDLRn4JyHH3D9jbB8q7B77P2wG+7iX75Hu/Zf9uGdWPSD4KyXfKFD8nCqoVCXOR",
+	"86GCmm4mlRs9/sQ8fUJM3SqYHDrZBkVUbXULd0rlCgtwuGtZTy2X256fhkE46HSQCoNhHyR8HJk/h1Ho",
+	"75X/fv3bgGzpgrE4SBtYWAqg7ovKY3EXOh+Yed/0qbGXXLDtCFa8MGEXU0UuIE2h3SKiWzHDpuqqfl0v",
+	"PMRjcidUlAaXnPybTbUyJZlWLCux4C4Mm6zLPQxwHbAjkZiu4Cu8ssz0gz2kXLC/4lAp4XZPlyKcLDnd",
+	"iVozv+NQ85iA33xcv/r845lIqu8wKjpVVw80ixTxZJ2Lvd53fEA+9z8t0h/98k2//NEtf3TLH93y/0C3",
+	"/Jt65f8JTvmjT/7F3cVHl/wRx39Cj/zbOuTf1h//ou74vjoC9ogtcssmp9xaBG8sTg6p/eDI6GrlFVUL",
+	"mmvlueIPRREHU16puDr44PDwr4Zu04PK7CFV/LF4CGX2HuMne+InZzCEBYT8jVGdZ+yZTC1R7EkTwcjM",
+	"vKoD9VecdZGFzYuiJ0ums/VYkCKdqIDZLjNQMvB8lYGbrTlT9xvL2T5xvlG31px1cgeCMswqJUuZ2XNI",
+	"RcLgAnXGKy7eW7KOhVF7LH069qBu+tjDWVJB/ufsNfuoz57lmZJZ47lIOSOhleBCEjzGfr+n3C9W9EPO",
+	"yNSAUGSoNkHmVPYqY1ccnKgVnbOAXDI89FXKV1AdY1HwadWE1JLMmbFPAROmh91OD45+HB0v+XKVgh1t",
+	"hL6WKcswKWQms2Lllsn6Pgg4KK/ACidQmR7WoO7CsAs+yyuazVnmliIUCLbVArKC7q6MhKSZYlllhH30",
+	"VDVgdyPCgVRDxa33etySxQbEMEWi7WGUWi7sGgSHQUhRSbrqtwJazpMEY9l5GLan7jv8i71bseydeVF8",
+	"bt6AX5wvRXGDQO3sN3RK/mCZBInvDuBXrwsC3CsCoUNyAoILTytTQcx9EjPO0oScPLv85fTeC2NfMgR3",
+	"7GGW79iDP2iS7Jz/VO+dqU8gsQimBwoRVxbWw6YgMKdMaHtAG9660/U4ckkgVA8gam1hdS5IQZktJJpv",
+	"7wF9Frx3cvYYLL9rsHzO5H/tCpjfMZDue2YaODpon+3DtCnoJHtnRyF9fbKlnWxCP2oTIIJi2qnlitqr",
+	"6sf9K8GrKZNtuJzusRStgnafcNw8yCJOtsKUIkUGlE24m0qRcOht5z6EaXhbiYq/YXZkzbfAUjiblVRN",
+	"hVOX/PjB5X3UK0p+8N0pYvCLZZZslpz8gOX6rasJV8akqbw2XD7L//hjXQxwApmWXKkVxGnFHAYCZxBf",
+	"g/TBy1ZMgAxNqpoL9KQeEDRX0/wh5dL4w0uKd7DszBA6Ogvzm1XfeWtVx0YS5g/s45fKvCwKGxWjHn0s",
+	"aEOGO48UjuYD+4Kz+rUuT/nGse4Lka5rrAc3jqZ0tXLj1Xo2cTTwMvbdL3F86PkuybTRF06mvYdc2j9D",
+	"JeV6uSok7ei3MAj7sQmVRd2BHwaxWVXdqNvDEvPVylJhZ3cmggncNJaVKrRNZXU+ZtkemWU7r5UnV0YN",
+	"0VxLGC9lNsberLuv6RxYZZfWht2gu9ZLvPF3ZFFRE+sBDBtfnivYj2Ibs7NjO7jxzxrUOf9MuNF2Xehl",
+	"egvAxXjk729/eoWAHwwr3oRXrcULHHLT+vTHTevTx5vWp/VNsJrMDso8K515ow9sZyjDeMrIH62PrbXZ",
+	"K/jtJ7oCWf4Lm4K7+panrDxSMed6kU+CqVy2ltisdYXNzqCXM4AdctJgEUHumQmAY9xLSqKgtid47Bm9",
+	"NmGaF0wiOU9sAVhldleZgQlsJinAe1qzzMcFmhA606w4rGF2F9HOcrG6BVVlHh98Ugm3mUo/1D1ZG0Br",
+	"Pq2JuKdqe7iKf99kkAGavodzMi/qN2HL2cbyMMV4IQyPCYkyM6l+YwFCgUwXUiqmqvgHuxjsVpKyK5aO",
+	"4FtbxhWfDm1HlUdRbAhhwvWwDuzjYE8RYmtRfYZZ+K8CSJ+EsA7jnVWP/vhcm5enLkjkxvr/f5AzspOw",
+	"H+9jwExeHzba+n4qSdmoMkSQXGjIcqG7QjIOd1je24dniMzG4nNOz5z6xF15UFygdk8HXjaexl/jiMtj",
+	"rKm0C65EEhhtc1bRNnVDqQglw2YpgnNrkrrpC3XNo614W9EDSrbNAuDMquYw2wfl8bldlqNbcgdcJuCa",
+	"wlBbJ/42b+Qttm/sVyW/Oz1XOwwIe7WWW8yxArtLj5LMKOkmPqEZIxK8YsuRmHGPOymKbPjiBSeNxa2s",
+	"tGVR/OKw9NlOmZAaPvXiMIb/j0jM7a78HMW3lsnE8x2NnEKQeTZl3sh7LZdckR/y9D3Mx/MLBh95cRB7",
+	"vrFKRmC3NLlpSUWQqYdZhb4O4o5F+wl06E0lVCndFfGbx6Z/ztgs5fOF3rYHm4oTmL9uz4ZoNAX3qO0d",
+	"MraOo6ysJFpFy7OLN5dk5eZBzMWHl+5u8EYU3dz87wA6NjrhlNAAAA==",
 }
 
 // GetOpenAPISpec returns the Swagger specification corresponding to the generated code